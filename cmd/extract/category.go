@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+func newCategoryCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "category",
+		Short: "Show the category taxonomy used to classify expressions",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return showCategories(cmd, a)
+		},
+	}
+	cmd.AddCommand(newCategorySetCmd(a), newCategoryRemoveCmd(a))
+	return cmd
+}
+
+func newCategorySetCmd(a *app) *cobra.Command {
+	var displayName, description, parent string
+	var position int
+	cmd := &cobra.Command{
+		Use:   "set <name>",
+		Short: "Add a category or update its display name, description, parent or position",
+		Example: `  extract category set infra --display-name インフラ --description "クラウド・運用・監視の用語" --parent engineering
+  extract category set small-talk --display-name 雑談 --description "会議の前後の雑談"
+  extract category set infra --parent ""    # move to the top level`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			flags := cmd.Flags()
+			repo, err := a.repository()
+			if err != nil {
+				return err
+			}
+
+			taxonomy, err := repo.ListCategories(ctx)
+			if err != nil {
+				return err
+			}
+			category := &models.Category{Name: models.NormalizeCategoryName(args[0])}
+			if existing := taxonomy.Find(args[0]); existing != nil {
+				category = existing
+			}
+			if flags.Changed("display-name") {
+				category.DisplayName = displayName
+			}
+			if flags.Changed("description") {
+				category.Description = description
+			}
+			if flags.Changed("position") {
+				category.Position = position
+			}
+			if flags.Changed("parent") {
+				category.Parent = ""
+				if parent != "" {
+					p := taxonomy.Find(parent)
+					if p == nil {
+						return fmt.Errorf("parent category not found: %s", parent)
+					}
+					// 自身や子孫を親にすると循環するので拒否
+					for ancestor, depth := p, 0; ancestor != nil && depth <= len(taxonomy); ancestor, depth = taxonomy.Find(ancestor.Parent), depth+1 {
+						if ancestor.Name == category.Name {
+							return fmt.Errorf("cannot move %s under itself or its descendant %s", category.Name, p.Name)
+						}
+					}
+					category.Parent = p.Name
+				}
+			}
+
+			if err := repo.SaveCategory(ctx, category); err != nil {
+				return err
+			}
+			progressf("✓ カテゴリ '%s' を保存しました\n", category.Name)
+			return showCategories(cmd, a)
+		},
+	}
+	cmd.Flags().StringVar(&displayName, "display-name", "", "name shown in reports and exports")
+	cmd.Flags().StringVar(&description, "description", "", "what kind of expressions belong here (shown to the LLM)")
+	cmd.Flags().StringVar(&parent, "parent", "", "parent category (expressions in this category also match the parent)")
+	cmd.Flags().IntVar(&position, "position", 0, "display order (default: last for a new category)")
+	cmd.RegisterFlagCompletionFunc("parent", a.completeCategories)
+	cmd.ValidArgsFunction = a.completeCategories
+	return cmd
+}
+
+func newCategoryRemoveCmd(a *app) *cobra.Command {
+	var reassign string
+	cmd := &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove a category, moving its expressions to another category or leaving them uncategorized",
+		Example: `  extract category remove casual --reassign business
+  extract category remove infra    # expressions lose this category`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			repo, err := a.repository()
+			if err != nil {
+				return err
+			}
+
+			taxonomy, err := repo.ListCategories(ctx)
+			if err != nil {
+				return err
+			}
+			category := taxonomy.Find(args[0])
+			if category == nil {
+				return fmt.Errorf("category not found: %s", args[0])
+			}
+			if reassign != "" {
+				target := taxonomy.Find(reassign)
+				if target == nil {
+					return fmt.Errorf("category not found: %s", reassign)
+				}
+				reassign = target.Name
+			}
+
+			affected, err := repo.DeleteCategory(ctx, category.Name, reassign)
+			if err != nil {
+				return err
+			}
+			if reassign != "" {
+				progressf("✓ カテゴリ '%s' を削除し、%d個の表現を '%s' に付け替えました\n", category.Name, affected, reassign)
+			} else {
+				progressf("✓ カテゴリ '%s' を削除しました（%d個の表現から外しました）\n", category.Name, affected)
+			}
+			return showCategories(cmd, a)
+		},
+	}
+	cmd.Flags().StringVar(&reassign, "reassign", "", "move expressions in the removed category to this category")
+	cmd.RegisterFlagCompletionFunc("reassign", a.completeCategories)
+	cmd.ValidArgsFunction = a.completeCategories
+	return cmd
+}
+
+// showCategories はカテゴリの分類体系を出力
+func showCategories(cmd *cobra.Command, a *app) error {
+	repo, err := a.repository()
+	if err != nil {
+		return err
+	}
+	taxonomy, err := repo.ListCategories(cmd.Context())
+	if err != nil {
+		return err
+	}
+	return a.emit(newCategoryListResult(taxonomy))
+}
+
+// categoryResult はカテゴリの定義
+type categoryResult struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Description string `json:"description"`
+	Parent      string `json:"parent,omitempty"`
+	Position    int    `json:"position"`
+}
+
+// categoryListResult はカテゴリの分類体系（category）
+type categoryListResult struct {
+	Categories []categoryResult `json:"categories"`
+	taxonomy   models.Taxonomy
+}
+
+func newCategoryListResult(taxonomy models.Taxonomy) *categoryListResult {
+	r := &categoryListResult{Categories: make([]categoryResult, 0, len(taxonomy)), taxonomy: taxonomy}
+	for _, c := range taxonomy {
+		r.Categories = append(r.Categories, categoryResult{
+			Name:        c.Name,
+			DisplayName: c.DisplayName,
+			Description: c.Description,
+			Parent:      c.Parent,
+			Position:    c.Position,
+		})
+	}
+	return r
+}
+
+func (r *categoryListResult) writeText(w io.Writer) {
+	if len(r.Categories) == 0 {
+		fmt.Fprintln(w, "No categories defined. Add one with 'category set <name>'.")
+		return
+	}
+	fmt.Fprintln(w, "カテゴリ（抽出時にLLMが表現ごとに1〜3個選びます）")
+	seen := make(map[string]bool)
+	writeCategoryTree(w, r.taxonomy, "", 1, seen)
+	// 親が見つからないカテゴリもトップレベルに表示する
+	for _, c := range r.taxonomy {
+		if !seen[c.Name] && r.taxonomy.Find(c.Parent) == nil {
+			seen[c.Name] = true
+			writeCategoryLine(w, c, 1)
+			writeCategoryTree(w, r.taxonomy, c.Name, 2, seen)
+		}
+	}
+}
+
+// writeCategoryTree はparentの子カテゴリを字下げして出力
+func writeCategoryTree(w io.Writer, taxonomy models.Taxonomy, parent string, depth int, seen map[string]bool) {
+	for _, c := range taxonomy.Children(parent) {
+		if seen[c.Name] {
+			continue
+		}
+		seen[c.Name] = true
+		writeCategoryLine(w, c, depth)
+		writeCategoryTree(w, taxonomy, c.Name, depth+1, seen)
+	}
+}
+
+func writeCategoryLine(w io.Writer, c models.Category, depth int) {
+	line := fmt.Sprintf("%s- %s（%s）", strings.Repeat("  ", depth), c.Name, c.Label())
+	if c.Description != "" {
+		line += ": " + c.Description
+	}
+	fmt.Fprintln(w, line)
+}
+
+func (r *categoryListResult) tableRows() [][]string {
+	rows := [][]string{{"NAME", "DISPLAY_NAME", "PARENT", "POSITION", "DESCRIPTION"}}
+	for _, c := range r.Categories {
+		rows = append(rows, []string{c.Name, c.DisplayName, c.Parent, fmt.Sprint(c.Position), c.Description})
+	}
+	return rows
+}
@@ -0,0 +1,562 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/output"
+	"github.com/mamyudapao/learn-by-transcript/internal/service"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+// lookupExpression は表現を取得し、存在しなければエラーを返す
+func lookupExpression(ctx context.Context, repo storage.Repository, expression string) (*models.Expression, error) {
+	expr, err := repo.GetExpression(ctx, expression)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expression: %w", err)
+	}
+	if expr == nil {
+		return nil, fmt.Errorf("expression not found: %s", expression)
+	}
+	return expr, nil
+}
+
+// resolveCategories はカンマ区切りのカテゴリを分類体系で検証し、識別名の一覧にする（空なら未分類）
+func resolveCategories(ctx context.Context, repo storage.Repository, value string) ([]string, error) {
+	taxonomy, err := repo.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resolved, unknown := taxonomy.Resolve(strings.Split(value, ","))
+	if len(unknown) > 0 {
+		names := make([]string, len(taxonomy))
+		for i, c := range taxonomy {
+			names[i] = c.Name
+		}
+		return nil, fmt.Errorf("unknown category: %s (available: %s; add one with 'category set')",
+			strings.Join(unknown, ", "), strings.Join(names, ", "))
+	}
+	return resolved, nil
+}
+
+// validatePriority は優先度が1〜5の範囲か確認
+func validatePriority(priority int) error {
+	if priority < 1 || priority > 5 {
+		return fmt.Errorf("priority must be between 1 and 5: %d", priority)
+	}
+	return nil
+}
+
+func newShowCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:               "show <expression>",
+		Short:             "Show an expression with its occurrences",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: a.completeExpressions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			repo, err := a.repository()
+			if err != nil {
+				return err
+			}
+
+			expr, err := lookupExpression(ctx, repo, strings.Join(args, " "))
+			if err != nil {
+				return err
+			}
+
+			occurrences, err := repo.GetOccurrences(ctx, expr.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get occurrences: %w", err)
+			}
+
+			aliases, err := repo.GetAliases(ctx, expr.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get aliases: %w", err)
+			}
+
+			senses, err := repo.ListSenses(ctx, expr.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get senses: %w", err)
+			}
+
+			usage, err := repo.GetUsage(ctx, expr.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get usage: %w", err)
+			}
+
+			return a.emit(&showResult{
+				expressionResult: newExpressionResult(expr),
+				Aliases:          aliases,
+				Senses:           newSenseResults(senses),
+				Usage:            newUsageResult(usage),
+				Occurrences:      newOccurrenceResults(occurrences),
+			})
+		},
+	}
+}
+
+func newContextsCmd(a *app) *cobra.Command {
+	var limit int
+	var order string
+	cmd := &cobra.Command{
+		Use:               "contexts <expression>",
+		Short:             "Show all distinct contexts with meeting and timestamp",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: a.completeExpressions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			repo, err := a.repository()
+			if err != nil {
+				return err
+			}
+
+			expr, err := lookupExpression(ctx, repo, strings.Join(args, " "))
+			if err != nil {
+				return err
+			}
+
+			occurrences, err := repo.GetOccurrences(ctx, expr.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get occurrences: %w", err)
+			}
+			selected, err := output.SelectContexts(occurrences, limit, order)
+			if err != nil {
+				return err
+			}
+
+			return a.emit(&contextsResult{
+				Expression:      expr.Expression,
+				OccurrenceCount: len(occurrences),
+				Contexts:        newOccurrenceResults(selected),
+			})
+		},
+	}
+	cmd.Flags().IntVar(&limit, "limit", 0, "maximum number of contexts (0 = all)")
+	cmd.Flags().StringVar(&order, "order", "recent", "order by recent or diverse (spread across meetings)")
+	cmd.RegisterFlagCompletionFunc("order", cobra.FixedCompletions([]string{"recent", "diverse"}, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+func newEditCmd(a *app) *cobra.Command {
+	var meaning, category, exprType string
+	var priority int
+	cmd := &cobra.Command{
+		Use:               "edit <expression>",
+		Short:             "Edit meaning/priority/category/type",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: a.completeExpressions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			flags := cmd.Flags()
+			if !flags.Changed("meaning") && !flags.Changed("priority") && !flags.Changed("category") && !flags.Changed("type") {
+				return fmt.Errorf("nothing to edit: specify --meaning, --priority, --category or --type")
+			}
+			if flags.Changed("priority") {
+				if err := validatePriority(priority); err != nil {
+					return err
+				}
+			}
+
+			repo, err := a.repository()
+			if err != nil {
+				return err
+			}
+			expr, err := lookupExpression(ctx, repo, strings.Join(args, " "))
+			if err != nil {
+				return err
+			}
+
+			// 指定されたフラグのみ反映
+			if flags.Changed("meaning") {
+				expr.Meaning = meaning
+			}
+			if flags.Changed("priority") {
+				// 手動で指定した優先度はLLMの判定に代わる基準にもなる
+				expr.Priority = priority
+				expr.BasePriority = priority
+			}
+			if flags.Changed("category") {
+				categories, err := resolveCategories(ctx, repo, category)
+				if err != nil {
+					return err
+				}
+				expr.Category, expr.Categories = "", categories
+			}
+			if flags.Changed("type") {
+				expr.Type = exprType
+			}
+
+			// 手動編集した表現は以降の自動更新で上書きしない
+			expr.ManuallyEdited = true
+			if err := repo.UpdateExpression(ctx, expr); err != nil {
+				return fmt.Errorf("failed to edit expression: %w", err)
+			}
+
+			updated, err := repo.GetExpressionByID(ctx, expr.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get expression: %w", err)
+			}
+			return a.emit(&changeResult{Action: "updated", Expression: newExpressionResult(updated)})
+		},
+	}
+	cmd.Flags().StringVar(&meaning, "meaning", "", "new Japanese meaning")
+	cmd.Flags().IntVar(&priority, "priority", 0, "new priority (1-5)")
+	cmd.Flags().StringVar(&category, "category", "", "new category")
+	cmd.Flags().StringVar(&exprType, "type", "", "new type (word/phrase)")
+	a.registerCompletions(cmd)
+	return cmd
+}
+
+func newDeleteCmd(a *app) *cobra.Command {
+	var yes bool
+	cmd := &cobra.Command{
+		Use:               "delete <expression>",
+		Short:             "Delete an expression and its occurrences",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: a.completeExpressions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			repo, err := a.repository()
+			if err != nil {
+				return err
+			}
+
+			expr, err := lookupExpression(ctx, repo, strings.Join(args, " "))
+			if err != nil {
+				return err
+			}
+
+			if !yes {
+				progressf("Delete '%s' and its %d occurrence(s)? [y/N]: ", expr.Expression, expr.OccurrenceCount)
+				answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+				answer = strings.ToLower(strings.TrimSpace(answer))
+				if answer != "y" && answer != "yes" {
+					progressf("Canceled.\n")
+					return nil
+				}
+			}
+
+			if err := repo.DeleteExpression(ctx, expr.ID); err != nil {
+				return fmt.Errorf("failed to delete expression: %w", err)
+			}
+
+			return a.emit(&changeResult{Action: "deleted", Expression: newExpressionResult(expr)})
+		},
+	}
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "delete without confirmation")
+	return cmd
+}
+
+func newAddCmd(a *app) *cobra.Command {
+	var meaning, partOfSpeech, category, exprType, exampleContext string
+	var tags []string
+	var priority int
+	cmd := &cobra.Command{
+		Use:   "add <expression>",
+		Short: "Add an expression manually",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if err := validatePriority(priority); err != nil {
+				return err
+			}
+
+			repo, err := a.repository()
+			if err != nil {
+				return err
+			}
+
+			expression := strings.Join(args, " ")
+			existing, err := repo.GetExpression(ctx, expression)
+			if err != nil {
+				return fmt.Errorf("failed to check existence: %w", err)
+			}
+			if existing != nil {
+				return fmt.Errorf("expression already exists: %s (use edit instead)", expression)
+			}
+
+			// 種類の指定がなければ語数から判定
+			if exprType == "" {
+				exprType = string(models.TypeWord)
+				if len(strings.Fields(expression)) > 1 {
+					exprType = string(models.TypePhrase)
+				}
+			}
+
+			categories, err := resolveCategories(ctx, repo, category)
+			if err != nil {
+				return err
+			}
+			tags, err := models.NormalizeTags(tags)
+			if err != nil {
+				return err
+			}
+
+			expr := &models.Expression{
+				Expression:     expression,
+				Type:           exprType,
+				Meaning:        meaning,
+				Priority:       priority,
+				Categories:     categories,
+				ManuallyEdited: true,
+			}
+			if err := repo.SaveExpression(ctx, expr); err != nil {
+				return fmt.Errorf("failed to add expression: %w", err)
+			}
+
+			// 意味を最初の語義として登録（以降の抽出で語義の判定の候補になる）
+			sense := &models.Sense{ExpressionID: expr.ID, PartOfSpeech: partOfSpeech, Gloss: meaning}
+			if meaning != "" {
+				if err := repo.AddSense(ctx, sense); err != nil {
+					return fmt.Errorf("failed to add sense: %w", err)
+				}
+			}
+
+			if exampleContext != "" {
+				if err := repo.AddOccurrence(ctx, &models.ExpressionOccurrence{
+					ExpressionID: expr.ID,
+					Context:      exampleContext,
+					Surface:      expr.Expression,
+					SenseID:      sense.ID,
+				}); err != nil {
+					return fmt.Errorf("failed to add occurrence: %w", err)
+				}
+			}
+			if len(tags) > 0 {
+				if err := repo.AddTags(ctx, expr.ID, models.TagSourceManual, tags...); err != nil {
+					return fmt.Errorf("failed to add tags: %w", err)
+				}
+			}
+
+			// 採番された日時・出現回数を含めて出力する
+			saved, err := repo.GetExpressionByID(ctx, expr.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get expression: %w", err)
+			}
+			return a.emit(&changeResult{Action: "added", Expression: newExpressionResult(saved)})
+		},
+	}
+	cmd.Flags().StringVar(&meaning, "meaning", "", "Japanese meaning")
+	cmd.Flags().StringVar(&partOfSpeech, "pos", "", "part of speech of the meaning (e.g. verb, noun, phrasal verb)")
+	cmd.Flags().IntVar(&priority, "priority", 3, "priority (1-5)")
+	cmd.Flags().StringVar(&category, "category", "", "categories, comma-separated and most relevant first (default: none)")
+	cmd.Flags().StringVar(&exprType, "type", "", "type (word/phrase, default: inferred from the expression)")
+	cmd.Flags().StringVar(&exampleContext, "context", "", "example sentence")
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "tags (repeatable or comma-separated)")
+	a.registerCompletions(cmd)
+	return cmd
+}
+
+func newMergeCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:               "merge <source> <target>",
+		Short:             "Merge source into target and record source as an alias (quote multi-word expressions)",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: a.completeExpressions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := a.repository()
+			if err != nil {
+				return err
+			}
+
+			merged, err := service.MergeExpressions(cmd.Context(), repo, args[0], args[1])
+			if err != nil {
+				return err
+			}
+
+			return a.emit(&mergeResult{Alias: args[0], Target: newExpressionResult(merged)})
+		},
+	}
+}
+
+func newSplitCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:               "split <source> <target>",
+		Short:             "Undo 'merge <source> <target>' and restore source with its occurrences",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: a.completeExpressions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			repo, err := a.repository()
+			if err != nil {
+				return err
+			}
+
+			source, target, err := service.SplitExpression(ctx, repo, args[0], args[1])
+			if err != nil {
+				return err
+			}
+
+			return a.emit(&splitResult{Source: newExpressionResult(source), Target: newExpressionResult(target)})
+		},
+	}
+}
+
+// showResult は表現の詳細（別名・語義・出現履歴を含む）
+type showResult struct {
+	expressionResult
+	Aliases     []string           `json:"aliases"`
+	Senses      []senseResult      `json:"senses"`
+	Usage       *usageResult       `json:"usage,omitempty"` // enrichで生成した使い方（未生成なら省略）
+	Occurrences []occurrenceResult `json:"occurrences"`
+}
+
+// senseNumber は出現履歴の語義の番号（1始まり。未判定なら0）
+func (r *showResult) senseNumber(senseID int) int {
+	for i, s := range r.Senses {
+		if s.ID == senseID {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func (r *showResult) writeText(w io.Writer) {
+	fmt.Fprintf(w, "\n%s (%s)\n", r.Expression, r.Type)
+	fmt.Fprintf(w, "  Meaning: %s\n", r.Meaning)
+	fmt.Fprintf(w, "  Priority: %d, Occurrences: %d\n", r.Priority, r.OccurrenceCount)
+	fmt.Fprintf(w, "  Category: %s\n", r.categoryText())
+	if len(r.Tags) > 0 {
+		fmt.Fprintf(w, "  Tags: %s\n", r.tagText())
+	}
+	fmt.Fprintf(w, "  First seen: %s, Last seen: %s\n",
+		r.FirstSeenAt.Format("2006-01-02 15:04:05"), r.LastSeenAt.Format("2006-01-02 15:04:05"))
+	if r.ManuallyEdited {
+		fmt.Fprintln(w, "  Manually edited: yes")
+	}
+	if len(r.Aliases) > 0 {
+		fmt.Fprintf(w, "  Aliases: %s\n", strings.Join(r.Aliases, ", "))
+	}
+
+	if len(r.Senses) > 0 {
+		fmt.Fprintf(w, "\n  Senses (%d):\n", len(r.Senses))
+		for i, s := range r.Senses {
+			fmt.Fprintf(w, "  %d. %s (%d)\n", i+1, s.text(), s.OccurrenceCount)
+		}
+	}
+
+	if r.Usage != nil {
+		r.Usage.writeText(w)
+	}
+
+	if len(r.Occurrences) > 0 {
+		fmt.Fprintf(w, "\n  Contexts (%d):\n", len(r.Occurrences))
+		for _, occ := range r.Occurrences {
+			sense := ""
+			if n := r.senseNumber(occ.SenseID); n > 0 {
+				sense = fmt.Sprintf(" (%d)", n)
+			}
+			fmt.Fprintf(w, "  - [%s]%s %s\n", occ.OccurredAt.Format("2006-01-02 15:04"), sense, occ.Context)
+		}
+	}
+}
+
+func (r *showResult) tableRows() [][]string {
+	rows := append(r.keyValueRows(), []string{"aliases", strings.Join(r.Aliases, ", ")})
+	for i, s := range r.Senses {
+		rows = append(rows, []string{fmt.Sprintf("sense_%d", i+1), s.text()})
+	}
+	if r.Usage != nil {
+		rows = append(rows, r.Usage.tableRows()...)
+	}
+	return rows
+}
+
+// contextsResult は表現の重複を除いた文脈の一覧
+type contextsResult struct {
+	Expression      string             `json:"expression"`
+	OccurrenceCount int                `json:"occurrence_count"`
+	Contexts        []occurrenceResult `json:"contexts"`
+}
+
+func (r *contextsResult) writeText(w io.Writer) {
+	fmt.Fprintf(w, "\n%s: %d occurrence(s), %d distinct context(s)\n\n", r.Expression, r.OccurrenceCount, len(r.Contexts))
+	for _, occ := range r.Contexts {
+		meeting := occ.Meeting
+		if meeting == "" {
+			meeting = "-"
+		}
+		fmt.Fprintf(w, "- [%s] %s\n", occ.OccurredAt.Format("2006-01-02 15:04"), meeting)
+		fmt.Fprintf(w, "  %s\n", occ.Context)
+		if occ.Surface != "" && !strings.EqualFold(occ.Surface, r.Expression) {
+			fmt.Fprintf(w, "  (as \"%s\")\n", occ.Surface)
+		}
+	}
+}
+
+func (r *contextsResult) tableRows() [][]string {
+	return occurrenceTableRows(r.Contexts)
+}
+
+// changeResult は表現の追加・編集・削除の結果
+type changeResult struct {
+	Action     string           `json:"action"` // "added" / "updated" / "deleted"
+	Expression expressionResult `json:"expression"`
+}
+
+func (r *changeResult) writeText(w io.Writer) {
+	e := r.Expression
+	switch r.Action {
+	case "added":
+		fmt.Fprintf(w, "\nAdded '%s' (%s, 優先度: %d, カテゴリ: %s)\n", e.Expression, e.Type, e.Priority, e.categoryText())
+	case "deleted":
+		fmt.Fprintf(w, "Deleted '%s'\n", e.Expression)
+	default:
+		fmt.Fprintf(w, "\nUpdated '%s'\n", e.Expression)
+		fmt.Fprintf(w, "  Meaning: %s\n", e.Meaning)
+		fmt.Fprintf(w, "  Priority: %d\n", e.Priority)
+		fmt.Fprintf(w, "  Category: %s\n", e.categoryText())
+		fmt.Fprintf(w, "  Tags: %s\n", e.tagText())
+	}
+}
+
+func (r *changeResult) tableRows() [][]string {
+	return [][]string{
+		append([]string{"ACTION"}, expressionTableHeader...),
+		append([]string{r.Action}, r.Expression.tableRow()...),
+	}
+}
+
+// mergeResult は表現の統合結果
+type mergeResult struct {
+	Alias  string           `json:"alias"`
+	Target expressionResult `json:"target"`
+}
+
+func (r *mergeResult) writeText(w io.Writer) {
+	fmt.Fprintf(w, "\nMerged '%s' into '%s'\n", r.Alias, r.Target.Expression)
+	fmt.Fprintf(w, "  Priority: %d, Occurrences: %d\n", r.Target.Priority, r.Target.OccurrenceCount)
+	fmt.Fprintf(w, "  '%s' is now recorded as an alias of '%s'\n", r.Alias, r.Target.Expression)
+}
+
+func (r *mergeResult) tableRows() [][]string {
+	return [][]string{
+		append([]string{"ALIAS"}, expressionTableHeader...),
+		append([]string{r.Alias}, r.Target.tableRow()...),
+	}
+}
+
+// splitResult は表現の分割結果
+type splitResult struct {
+	Source expressionResult `json:"source"`
+	Target expressionResult `json:"target"`
+}
+
+func (r *splitResult) writeText(w io.Writer) {
+	fmt.Fprintf(w, "\nSplit '%s' from '%s'\n", r.Source.Expression, r.Target.Expression)
+	for _, e := range []expressionResult{r.Source, r.Target} {
+		fmt.Fprintf(w, "  %s: Priority %d, Occurrences %d\n", e.Expression, e.Priority, e.OccurrenceCount)
+	}
+}
+
+func (r *splitResult) tableRows() [][]string {
+	return [][]string{expressionTableHeader, r.Source.tableRow(), r.Target.tableRow()}
+}
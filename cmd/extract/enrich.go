@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/extractor"
+	"github.com/mamyudapao/learn-by-transcript/internal/llm"
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/service"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+func newEnrichCmd(a *app) *cobra.Command {
+	var force, dryRun bool
+	var minPriority, limit int
+	cmd := &cobra.Command{
+		Use:   "enrich [expression]...",
+		Short: "Generate example sentences, usage notes, collocations and synonyms with the LLM",
+		Long: `Generate clean example sentences, register and usage notes, collocations, synonyms and antonyms
+for expressions that do not have them yet (quote multi-word expressions).
+Without arguments, all expressions are enriched in priority order.`,
+		Example: `  extract enrich --min-priority 4 --limit 50
+  extract enrich "circle back" --force`,
+		ValidArgsFunction: a.completeExpressions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if limit < 0 {
+				return fmt.Errorf("--limit must not be negative")
+			}
+
+			var repo storage.Repository
+			repo, err := a.repository()
+			if err != nil {
+				return err
+			}
+			// ドライランでは生成した内容を表示するだけで保存しない
+			if dryRun {
+				repo = storage.NewDryRunRepository(repo)
+			}
+
+			var expressions []*models.Expression
+			if len(args) > 0 {
+				for _, arg := range args {
+					expr, err := lookupExpression(ctx, repo, arg)
+					if err != nil {
+						return err
+					}
+					expressions = append(expressions, expr)
+				}
+			} else {
+				all, err := repo.ListExpressions(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to list expressions: %w", err)
+				}
+				for _, expr := range all {
+					if expr.Priority >= minPriority {
+						expressions = append(expressions, expr)
+					}
+				}
+			}
+
+			provider, err := a.llmProvider()
+			if err != nil {
+				return err
+			}
+			enricher, err := a.enricher(ctx, repo, provider)
+			if err != nil {
+				return err
+			}
+			result, err := service.Enrich(ctx, repo, enricher, expressions, service.EnrichOptions{
+				Force:   force,
+				Limit:   limit,
+				OnBatch: enrichProgress,
+			})
+			if err != nil {
+				return err
+			}
+			return a.emit(newEnrichResult(result, dryRun))
+		},
+	}
+	cmd.Flags().BoolVar(&force, "force", false, "regenerate usage for expressions that already have it")
+	cmd.Flags().IntVar(&minPriority, "min-priority", 0, "only enrich expressions with at least this priority (1-5; ignored with arguments)")
+	cmd.Flags().IntVar(&limit, "limit", 0, "maximum number of expressions to enrich (0 = no limit)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show the generated usage without writing to the database")
+	return cmd
+}
+
+// enricher は学習者のプロフィールを読み込んで使い方の生成に使うEnricherを作成
+func (a *app) enricher(ctx context.Context, repo storage.Repository, provider llm.Provider) (*extractor.Enricher, error) {
+	cfg, err := a.config()
+	if err != nil {
+		return nil, err
+	}
+	profile, err := service.LoadProfile(ctx, repo, cfg.Profile)
+	if err != nil {
+		return nil, err
+	}
+	return extractor.NewEnricher(provider, extractor.WithLogger(a.logger), extractor.WithProfile(profile)), nil
+}
+
+// enrichProgress は使い方の生成の進捗をバッチごとに表示
+func enrichProgress(done, total int) {
+	if done > 0 {
+		progressf("使い方を生成中... %d/%d\n", done, total)
+	}
+}
+
+// enrichedResult は使い方を生成した表現
+type enrichedResult struct {
+	ID         int          `json:"id"`
+	Expression string       `json:"expression"`
+	Usage      *usageResult `json:"usage"`
+}
+
+// enrichResult は使い方の生成結果（enrich）
+type enrichResult struct {
+	DryRun   bool             `json:"dry_run,omitempty"`
+	Enriched []enrichedResult `json:"enriched"`
+	Skipped  int              `json:"skipped"`
+	Missing  int              `json:"missing"`
+}
+
+func newEnrichResult(result *service.EnrichResult, dryRun bool) *enrichResult {
+	r := &enrichResult{DryRun: dryRun, Enriched: []enrichedResult{}, Skipped: result.Skipped, Missing: result.Missing}
+	for _, e := range result.Enriched {
+		r.Enriched = append(r.Enriched, enrichedResult{
+			ID:         e.Expression.ID,
+			Expression: e.Expression.Expression,
+			Usage:      newUsageResult(e.Usage),
+		})
+	}
+	return r
+}
+
+func (r *enrichResult) writeText(w io.Writer) {
+	if r.DryRun {
+		fmt.Fprintln(w, "ドライラン: データベースには保存していません")
+	}
+	fmt.Fprintf(w, "%d個の表現の使い方を生成しました", len(r.Enriched))
+	if r.Skipped > 0 {
+		fmt.Fprintf(w, "（生成済みの%d個は --force で作り直せます）", r.Skipped)
+	}
+	fmt.Fprintln(w)
+	if r.Missing > 0 {
+		fmt.Fprintf(w, "%d個の表現はLLMが使い方を返しませんでした\n", r.Missing)
+	}
+	for _, e := range r.Enriched {
+		fmt.Fprintf(w, "\n%s\n", e.Expression)
+		e.Usage.writeText(w)
+	}
+}
+
+func (r *enrichResult) tableRows() [][]string {
+	rows := [][]string{{"ID", "EXPRESSION", "REGISTER", "EXAMPLE", "COLLOCATIONS", "SYNONYMS"}}
+	for _, e := range r.Enriched {
+		example := ""
+		if len(e.Usage.Examples) > 0 {
+			example = e.Usage.Examples[0].Sentence
+		}
+		rows = append(rows, []string{
+			fmt.Sprint(e.ID), e.Expression, e.Usage.Register, example,
+			strings.Join(e.Usage.Collocations, ", "), strings.Join(e.Usage.Synonyms, ", "),
+		})
+	}
+	return rows
+}
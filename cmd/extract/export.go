@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/config"
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/notion"
+	"github.com/mamyudapao/learn-by-transcript/internal/output"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+func newExportCmd(a *app) *cobra.Command {
+	var opts output.ExportOptions
+	var format, since string
+	var sinceLast bool
+	cmd := &cobra.Command{
+		Use:   "export <output-file>",
+		Short: "Export expressions (CSV / JSON / JSONL / Markdown / Anki / template), or sync to Notion with --format notion",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if sinceLast && since != "" {
+				return fmt.Errorf("--since-last and --since cannot be used together")
+			}
+			// フラグの0は「全件」（ExportOptionsでは負数）
+			if opts.MaxContexts == 0 {
+				opts.MaxContexts = -1
+			}
+			tags, err := models.NormalizeTags(opts.Tags)
+			if err != nil {
+				return err
+			}
+			opts.Tags = tags
+			if since != "" {
+				opts.Since, err = parseSince(since)
+				if err != nil {
+					return err
+				}
+			}
+
+			// Notionは出力ファイルを取らない
+			if format == "notion" {
+				if len(args) != 0 {
+					return fmt.Errorf("export --format notion does not take an output file")
+				}
+				cfg, err := a.config()
+				if err != nil {
+					return err
+				}
+				if cfg.Notion.APIKey == "" || cfg.Notion.DatabaseID == "" {
+					return fmt.Errorf("NOTION_API_KEY and NOTION_DATABASE_ID are required for notion export")
+				}
+				repo, err := a.repository()
+				if err != nil {
+					return err
+				}
+				destination := "notion:" + cfg.Notion.DatabaseID
+				return withCheckpoint(cmd.Context(), repo, destination, sinceLast, &opts, func() error {
+					return syncNotion(cmd.Context(), a, repo, cfg.Notion, opts)
+				})
+			}
+
+			if len(args) != 1 {
+				return fmt.Errorf("requires an output file (or --format notion)")
+			}
+			return exportToFile(cmd.Context(), a, args[0], format, sinceLast, opts)
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&format, "format", "", "output format ("+strings.Join(output.Formats(), ", ")+", notion; default: from file extension, else csv)")
+	flags.IntVar(&opts.MinPriority, "min-priority", 0, "minimum priority (1-5)")
+	flags.StringVar(&opts.Category, "category", "", "filter by category")
+	flags.StringSliceVar(&opts.Tags, "tag", nil, "only expressions with all of these tags (repeatable)")
+	flags.StringVar(&opts.SortBy, "sort", "priority", "sort by priority, occurrence, expression or recent")
+	flags.BoolVar(&opts.IncludeContext, "context", true, "include context sentences")
+	flags.IntVar(&opts.MaxContexts, "max-contexts", 1, "maximum number of distinct contexts per expression (0 = all)")
+	flags.StringVar(&opts.ContextOrder, "context-order", "recent", "context order: recent or diverse (spread across meetings)")
+	flags.StringVar(&opts.DeckName, "deck", output.DefaultAnkiDeckName, "Anki deck name (anki only)")
+	flags.StringVar(&opts.TemplatePath, "template", "", "Go template file (text/template, or html/template for *.html[.tmpl]); implies --format template")
+	flags.BoolVar(&sinceLast, "since-last", false, "only export expressions added or updated since the last export to the same destination")
+	flags.StringVar(&since, "since", "", "only export expressions added or updated since this date (YYYY-MM-DD or RFC3339)")
+
+	cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(append(output.Formats(), "notion"), cobra.ShellCompDirectiveNoFileComp))
+	cmd.RegisterFlagCompletionFunc("sort", cobra.FixedCompletions([]string{"priority", "occurrence", "expression", "recent"}, cobra.ShellCompDirectiveNoFileComp))
+	cmd.RegisterFlagCompletionFunc("context-order", cobra.FixedCompletions([]string{"recent", "diverse"}, cobra.ShellCompDirectiveNoFileComp))
+	a.registerCompletions(cmd)
+	return cmd
+}
+
+// exportToFile は表現をファイルに出力
+func exportToFile(ctx context.Context, a *app, outputPath, format string, sinceLast bool, opts output.ExportOptions) error {
+	repo, err := a.repository()
+	if err != nil {
+		return err
+	}
+
+	// 形式はフラグ → テンプレート指定 → 拡張子 → CSVの順で決定
+	if format == "" && opts.TemplatePath != "" {
+		format = "template"
+	}
+	if format == "" {
+		if detected, ok := output.FormatForPath(outputPath); ok {
+			format = detected
+		} else {
+			format = "csv"
+		}
+	}
+	exporter, err := output.New(format, repo)
+	if err != nil {
+		return err
+	}
+
+	absPath, err := filepath.Abs(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve output path: %w", err)
+	}
+	destination := format + ":" + absPath
+
+	return withCheckpoint(ctx, repo, destination, sinceLast, &opts, func() error {
+		progressf("\nExporting expressions (%s): %s\n\n", format, outputPath)
+		if err := exporter.Export(ctx, outputPath, opts); err != nil {
+			return fmt.Errorf("failed to export: %w", err)
+		}
+
+		// 件数確認
+		expressions, err := output.SelectExpressions(ctx, repo, opts)
+		if err != nil {
+			return fmt.Errorf("failed to count expressions: %w", err)
+		}
+
+		r := &exportResult{Format: format, Output: outputPath, Count: len(expressions)}
+		if !opts.Since.IsZero() {
+			r.Since = &opts.Since
+		}
+		return a.emit(r)
+	})
+}
+
+// withCheckpoint は出力先のチェックポイントを読み込んでexportを実行し、成功した場合のみチェックポイントを進める
+// チェックポイントは開始時刻で記録する（出力中に更新された表現を次回の差分に含めるため）
+// updated_at は秒単位なので、同じ秒の更新を取りこぼさないよう秒未満を切り捨てる
+func withCheckpoint(ctx context.Context, repo storage.Repository, destination string, sinceLast bool, opts *output.ExportOptions, export func() error) error {
+	startedAt := time.Now().Truncate(time.Second)
+
+	if sinceLast {
+		checkpoint, ok, err := repo.GetExportCheckpoint(ctx, destination)
+		if err != nil {
+			return err
+		}
+		if ok {
+			opts.Since = checkpoint
+		} else {
+			progressf("No previous export to this destination; exporting everything.\n")
+		}
+	}
+
+	if err := export(); err != nil {
+		return err
+	}
+
+	return repo.SaveExportCheckpoint(ctx, destination, startedAt)
+}
+
+// parseSince は --since の日時をパース（日付のみならローカル時刻の0時）
+func parseSince(value string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since value: %s (expected YYYY-MM-DD or RFC3339)", value)
+}
+
+// syncNotion は表現をNotionデータベースに同期
+func syncNotion(ctx context.Context, a *app, repo storage.Repository, cfg config.NotionConfig, opts output.ExportOptions) error {
+	client, err := notion.NewClient(cfg.APIKey, cfg.BaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to create notion client: %w", err)
+	}
+
+	progressf("\nSyncing expressions to Notion database: %s\n\n", cfg.DatabaseID)
+	result, err := output.NewNotionExporter(repo, client, cfg.DatabaseID).Sync(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to sync to notion: %w", err)
+	}
+
+	return a.emit(&notionSyncResult{DatabaseID: cfg.DatabaseID, Created: result.Created, Updated: result.Updated})
+}
+
+// exportResult はファイルへのエクスポート結果
+type exportResult struct {
+	Format string     `json:"format"`
+	Output string     `json:"output"`
+	Count  int        `json:"count"`
+	Since  *time.Time `json:"since,omitempty"` // 差分エクスポートの基準日時
+}
+
+func (r *exportResult) writeText(w io.Writer) {
+	fmt.Fprintln(w, strings.Repeat("=", 50))
+	fmt.Fprintln(w, "エクスポート完了")
+	fmt.Fprintln(w, strings.Repeat("=", 50))
+	fmt.Fprintf(w, "出力ファイル: %s\n", r.Output)
+	fmt.Fprintf(w, "エクスポート件数: %d個\n", r.Count)
+	if r.Since != nil {
+		fmt.Fprintf(w, "差分の基準日時: %s\n", r.Since.Local().Format("2006-01-02 15:04:05"))
+	}
+	fmt.Fprintln(w, strings.Repeat("=", 50))
+}
+
+func (r *exportResult) tableRows() [][]string {
+	since := ""
+	if r.Since != nil {
+		since = formatTime(*r.Since)
+	}
+	return [][]string{{"FORMAT", "OUTPUT", "COUNT", "SINCE"}, {r.Format, r.Output, fmt.Sprint(r.Count), since}}
+}
+
+// notionSyncResult はNotionへの同期結果
+type notionSyncResult struct {
+	DatabaseID string `json:"database_id"`
+	Created    int    `json:"created"`
+	Updated    int    `json:"updated"`
+}
+
+func (r *notionSyncResult) writeText(w io.Writer) {
+	fmt.Fprintln(w, strings.Repeat("=", 50))
+	fmt.Fprintln(w, "Notion同期完了")
+	fmt.Fprintln(w, strings.Repeat("=", 50))
+	fmt.Fprintf(w, "新規作成: %d個\n", r.Created)
+	fmt.Fprintf(w, "更新: %d個\n", r.Updated)
+	fmt.Fprintln(w, strings.Repeat("=", 50))
+}
+
+func (r *notionSyncResult) tableRows() [][]string {
+	return [][]string{{"DATABASE", "CREATED", "UPDATED"}, {r.DatabaseID, fmt.Sprint(r.Created), fmt.Sprint(r.Updated)}}
+}
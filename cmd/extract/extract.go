@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/llm"
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/output"
+	"github.com/mamyudapao/learn-by-transcript/internal/service"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+	transcriptpkg "github.com/mamyudapao/learn-by-transcript/internal/transcript"
+)
+
+func newExtractCmd(a *app) *cobra.Command {
+	var meeting, reportPath, recordingURL string
+	var tags []string
+	var parallel int
+	var dryRun, enrich bool
+	cmd := &cobra.Command{
+		Use:   "extract <file|dir|glob>...",
+		Short: "Extract expressions from .txt/.vtt/.srt transcripts",
+		Args:  cobra.MinimumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return transcriptExtensions(), cobra.ShellCompDirectiveFilterFileExt
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if parallel < 1 {
+				return fmt.Errorf("--parallel must be at least 1")
+			}
+
+			files, err := expandTranscriptArgs(args)
+			if err != nil {
+				return err
+			}
+			if len(files) > 1 && (meeting != "" || reportPath != "") {
+				return fmt.Errorf("--meeting and --report can only be used with a single transcript file (got %d files)", len(files))
+			}
+			tags, err := models.NormalizeTags(tags)
+			if err != nil {
+				return err
+			}
+
+			var repo storage.Repository
+			repo, err = a.repository()
+			if err != nil {
+				return err
+			}
+			// ドライランでは書き込みをメモリ上で模擬し、データベースには反映しない
+			if dryRun {
+				repo = storage.NewDryRunRepository(repo)
+			}
+			provider, err := a.llmProvider()
+			if err != nil {
+				return err
+			}
+
+			jobs := make([]service.FileJob, len(files))
+			for i, f := range files {
+				jobs[i] = service.FileJob{Path: f, Meeting: meeting}
+				progressf("\nProcessing transcript file: %s\n", f)
+			}
+			progressf("\n")
+
+			// 同じプロンプトへのLLM呼び出しはファイル間で共有
+			cache := llm.NewCachingProvider(provider)
+			// ログを出す場合や並列処理では行の書き換えが混ざるので、段階ごとに1行で表示
+			inPlace := len(jobs) == 1 && !a.logger.Enabled(cmd.Context(), slog.LevelInfo)
+			opts, err := a.processorOptions(cmd.Context(), repo)
+			if err != nil {
+				return err
+			}
+			opts = append(opts, service.WithTags(tags), service.WithProgress(newProgressBar(inPlace).handle))
+			processor := service.NewTranscriptProcessor(cache, repo, opts...)
+			results := processor.ProcessFiles(cmd.Context(), jobs, parallel)
+			err = a.emitExtractResults(cmd.Context(), repo, results, dryRun, cache.Hits(), reportPath, recordingURL)
+
+			// 使い方の生成は結果の表示・レポートの後に行い、失敗しても抽出結果は保存済みなので警告に留める
+			if enrich {
+				if enrichErr := a.enrichNewExpressions(cmd.Context(), repo, cache, results); enrichErr != nil {
+					progressf("⚠ 新しい表現の使い方を生成できませんでした（extract enrich で再実行できます）: %v\n", enrichErr)
+				}
+			}
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&meeting, "meeting", "", "meeting name recorded with each occurrence (default: file name; single file only)")
+	cmd.Flags().StringVar(&reportPath, "report", "", "write an HTML study report of new and updated expressions to this file (single file only)")
+	cmd.Flags().StringVar(&recordingURL, "recording-url", "", "recording URL for timestamp links in the report (default: link to the transcript file)")
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "tag every expression found in the transcripts (repeatable, in addition to tag rules)")
+	cmd.Flags().IntVar(&parallel, "parallel", 1, "number of files to process concurrently")
+	cmd.Flags().BoolVar(&enrich, "enrich", false, "generate example sentences and usage notes for new expressions (see 'enrich')")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "run the full pipeline and show what would be saved without writing to the database")
+	a.registerCompletions(cmd)
+	return cmd
+}
+
+// emitExtractResults は抽出結果を表示（1ファイルならレポートも出力し、失敗したファイルがあればエラーを返す）
+func (a *app) emitExtractResults(ctx context.Context, repo storage.Repository, results []*service.FileResult, dryRun bool, cacheHits int, reportPath, recordingURL string) error {
+	if len(results) == 1 {
+		r := results[0]
+		if r.Err != nil {
+			return fmt.Errorf("failed to process transcript: %w", r.Err)
+		}
+		res := newProcessResult(r.Path, dryRun, r.Result)
+		if reportPath != "" {
+			taxonomy, err := repo.ListCategories(ctx)
+			if err != nil {
+				return err
+			}
+			if err := writeExtractReport(reportPath, r.Path, recordingURL, r.Result, taxonomy); err != nil {
+				return err
+			}
+			res.Report = reportPath
+		}
+		return a.emit(res)
+	}
+
+	batch := newBatchResult(results, dryRun, cacheHits)
+	if err := a.emit(batch); err != nil {
+		return err
+	}
+	if batch.Failed > 0 {
+		return fmt.Errorf("%d of %d file(s) failed", batch.Failed, len(results))
+	}
+	return nil
+}
+
+// enrichNewExpressions は処理に成功したtranscriptで新しく登録した表現の使い方を生成
+func (a *app) enrichNewExpressions(ctx context.Context, repo storage.Repository, provider llm.Provider, results []*service.FileResult) error {
+	var expressions []*models.Expression
+	for _, r := range results {
+		if r.Err != nil {
+			continue
+		}
+		for _, e := range r.Result.Expressions {
+			if e.New {
+				expressions = append(expressions, e.Expression)
+			}
+		}
+	}
+	if len(expressions) == 0 {
+		return nil
+	}
+
+	enricher, err := a.enricher(ctx, repo, provider)
+	if err != nil {
+		return err
+	}
+	result, err := service.Enrich(ctx, repo, enricher, expressions, service.EnrichOptions{OnBatch: enrichProgress})
+	if err != nil {
+		return err
+	}
+	progressf("✓ %d個の新しい表現の使い方を生成しました\n", len(result.Enriched))
+	return nil
+}
+
+// transcriptExtensions はシェル補完用の拡張子一覧（先頭の"."なし）
+func transcriptExtensions() []string {
+	exts := make([]string, len(transcriptpkg.Extensions))
+	for i, ext := range transcriptpkg.Extensions {
+		exts[i] = strings.TrimPrefix(ext, ".")
+	}
+	return exts
+}
+
+// expandTranscriptArgs はファイル・ディレクトリ・globパターンをtranscriptファイルの一覧に展開
+// ディレクトリとglobは対応する拡張子のファイルのみ対象とし、重複は除く
+func expandTranscriptArgs(args []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			files = append(files, path)
+		}
+	}
+
+	for _, arg := range args {
+		if info, err := os.Stat(arg); err == nil {
+			if !info.IsDir() {
+				add(arg)
+				continue
+			}
+			entries, err := os.ReadDir(arg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read directory: %w", err)
+			}
+			for _, e := range entries {
+				if !e.IsDir() && transcriptpkg.IsSupported(e.Name()) {
+					add(filepath.Join(arg, e.Name()))
+				}
+			}
+			continue
+		}
+
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", arg, err)
+		}
+		if matches == nil {
+			return nil, fmt.Errorf("no such file or matching files: %s", arg)
+		}
+		sort.Strings(matches)
+		for _, m := range matches {
+			if info, err := os.Stat(m); err == nil && !info.IsDir() && transcriptpkg.IsSupported(m) {
+				add(m)
+			}
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no transcript files (%s) found", strings.Join(transcriptpkg.Extensions, "/"))
+	}
+	return files, nil
+}
+
+// batchResult は複数ファイルの抽出結果
+type batchResult struct {
+	DryRun    bool              `json:"dry_run,omitempty"`
+	Files     []fileBatchResult `json:"files"`
+	Failed    int               `json:"failed"`
+	CacheHits int               `json:"cache_hits"` // LLMキャッシュから応答した回数
+}
+
+// fileBatchResult はファイルごとの抽出結果（失敗した場合はErrorのみ）
+type fileBatchResult struct {
+	Path   string         `json:"path"`
+	Error  string         `json:"error,omitempty"`
+	Result *processResult `json:"result,omitempty"`
+}
+
+func newBatchResult(results []*service.FileResult, dryRun bool, cacheHits int) *batchResult {
+	b := &batchResult{DryRun: dryRun, CacheHits: cacheHits}
+	for _, r := range results {
+		f := fileBatchResult{Path: r.Path}
+		if r.Err != nil {
+			b.Failed++
+			f.Error = r.Err.Error()
+		} else {
+			f.Result = newProcessResult(r.Path, dryRun, r.Result)
+		}
+		b.Files = append(b.Files, f)
+	}
+	return b
+}
+
+func (b *batchResult) writeText(w io.Writer) {
+	if b.DryRun {
+		fmt.Fprintln(w, "\nドライラン: データベースには保存していません")
+		for _, f := range b.Files {
+			if f.Result == nil {
+				continue
+			}
+			fmt.Fprintf(w, "\n%s\n", f.Path)
+			writeRows(w, f.Result.dryRunRows())
+		}
+	}
+
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 50))
+	fmt.Fprintln(w, "処理完了")
+	fmt.Fprintln(w, strings.Repeat("=", 50))
+	writeRows(w, b.tableRows())
+	fmt.Fprintln(w, strings.Repeat("=", 50))
+	if b.CacheHits > 0 {
+		fmt.Fprintf(w, "LLMキャッシュ: %d回\n", b.CacheHits)
+	}
+}
+
+func (b *batchResult) tableRows() [][]string {
+	rows := [][]string{{"FILE", "EXPRESSIONS", "NEW", "UPDATED", "ERROR"}}
+	var total, added, updated int
+	for _, f := range b.Files {
+		if f.Result == nil {
+			rows = append(rows, []string{f.Path, "-", "-", "-", f.Error})
+			continue
+		}
+		total += f.Result.TotalExpressions
+		added += f.Result.NewExpressions
+		updated += f.Result.UpdatedPriority
+		rows = append(rows, []string{f.Path, fmt.Sprint(f.Result.TotalExpressions), fmt.Sprint(f.Result.NewExpressions), fmt.Sprint(f.Result.UpdatedPriority), ""})
+	}
+	rows = append(rows, []string{fmt.Sprintf("TOTAL (%d files, %d failed)", len(b.Files), b.Failed), fmt.Sprint(total), fmt.Sprint(added), fmt.Sprint(updated), ""})
+	return rows
+}
+
+// writeExtractReport は処理結果のHTMLレポートを書き出す
+func writeExtractReport(reportPath, transcriptPath, recordingURL string, result *service.ProcessResult, taxonomy models.Taxonomy) error {
+	absPath, err := filepath.Abs(transcriptPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve transcript path: %w", err)
+	}
+	opts := output.ReportOptions{
+		TranscriptURL: (&url.URL{Scheme: "file", Path: filepath.ToSlash(absPath)}).String(),
+		RecordingURL:  recordingURL,
+		Categories:    taxonomy,
+	}
+	return output.WriteReportFile(reportPath, result, opts)
+}
@@ -3,180 +3,407 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"os"
 	"strings"
 
+	"github.com/spf13/cobra"
+
 	"github.com/mamyudapao/learn-by-transcript/internal/config"
 	"github.com/mamyudapao/learn-by-transcript/internal/llm"
-	"github.com/mamyudapao/learn-by-transcript/internal/output"
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
 	"github.com/mamyudapao/learn-by-transcript/internal/service"
 	"github.com/mamyudapao/learn-by-transcript/internal/storage"
 )
 
 func main() {
-	if err := run(); err != nil {
-		log.Fatal(err)
+	a := &app{}
+	err := newRootCmd(a).ExecuteContext(context.Background())
+	a.close()
+	if err != nil {
+		os.Exit(1)
 	}
 }
 
-func run() error {
-	ctx := context.Background()
+// app はコマンド間で共有する設定・リポジトリ（必要になった時点で初期化）
+type app struct {
+	// グローバルフラグ（空なら環境変数の値を使う）
+	dbPath       string
+	providerType string
+	model        string
+	output       string // 結果の出力形式（text / json / table）
+	logLevel     string
 
-	// コマンドライン引数チェック
-	if len(os.Args) < 2 {
-		return fmt.Errorf("usage: %s <command> [args]\n\nCommands:\n  extract <file> - Extract expressions from transcript file\n  export <output-file> - Export expressions to CSV file\n  test - Test LLM connection\n  list - List all expressions", os.Args[0])
-	}
+	cfg    *config.Config
+	repo   storage.Repository
+	logger *slog.Logger // 標準エラー出力へのログ（--log-level以上）
+}
 
-	command := os.Args[1]
+// logLevels は --log-level に指定できる値
+var logLevels = map[string]slog.Level{
+	"debug": slog.LevelDebug,
+	"info":  slog.LevelInfo,
+	"warn":  slog.LevelWarn,
+	"error": slog.LevelError,
+}
 
-	// 設定読み込み（コマンドに応じて使い分け）
-	var cfg *config.Config
-	var err error
-	if command == "export" || command == "list" {
-		// LLM不要なコマンドは基本設定のみ
-		cfg, err = config.LoadBasic()
-	} else {
-		// LLM必要なコマンドは完全な設定
-		cfg, err = config.Load()
-	}
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+// setupLogger は --log-level に応じたロガーを作成し、slogのデフォルトにも設定
+func (a *app) setupLogger() error {
+	level, ok := logLevels[strings.ToLower(a.logLevel)]
+	if !ok {
+		return fmt.Errorf("invalid --log-level: %s (expected debug, info, warn or error)", a.logLevel)
 	}
+	a.logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+	slog.SetDefault(a.logger)
+	return nil
+}
 
-	// ストレージ初期化（export, listで必要）
-	var repo storage.Repository
-	if command == "export" || command == "list" || command == "extract" {
-		repo, err = storage.NewSQLiteRepository(cfg.DBPath)
-		if err != nil {
-			return fmt.Errorf("failed to create repository: %w", err)
-		}
-		defer repo.Close()
-		fmt.Printf("Database: %s\n", cfg.DBPath)
+// config は設定を読み込み、グローバルフラグで上書き
+func (a *app) config() (*config.Config, error) {
+	if a.cfg != nil {
+		return a.cfg, nil
 	}
 
-	// LLMプロバイダー初期化（extract, testで必要）
-	var provider llm.Provider
-	if command == "extract" || command == "test" {
-		provider, err = llm.NewProvider(cfg.LLM)
-		if err != nil {
-			return fmt.Errorf("failed to create LLM provider: %w", err)
-		}
-		fmt.Printf("Using LLM: %s (%s)\n", cfg.LLM.Type, provider.GetModelName())
+	cfg, err := config.LoadBasic()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
-
-	switch command {
-	case "extract":
-		if len(os.Args) < 3 {
-			return fmt.Errorf("usage: %s extract <transcript-file>", os.Args[0])
-		}
-		return extractFromFile(ctx, provider, repo, os.Args[2])
-	case "export":
-		if len(os.Args) < 3 {
-			return fmt.Errorf("usage: %s export <output-file>", os.Args[0])
-		}
-		return exportToCSV(ctx, repo, os.Args[2])
-	case "test":
-		return testLLM(ctx, provider)
-	case "list":
-		return listExpressions(ctx, repo)
-	default:
-		return fmt.Errorf("unknown command: %s", command)
+	if a.dbPath != "" {
+		cfg.DBPath = a.dbPath
+	}
+	if a.providerType != "" {
+		cfg.LLM.Type = a.providerType
+	}
+	if a.model != "" {
+		cfg.LLM.Model = a.model
 	}
+
+	a.cfg = cfg
+	return cfg, nil
 }
 
-func extractFromFile(ctx context.Context, provider llm.Provider, repo storage.Repository, filePath string) error {
-	fmt.Printf("\nProcessing transcript file: %s\n\n", filePath)
+// repository はデータベースを開く（2回目以降は同じリポジトリを返す）
+func (a *app) repository() (storage.Repository, error) {
+	if a.repo != nil {
+		return a.repo, nil
+	}
 
-	// ファイル読み込み
-	content, err := os.ReadFile(filePath)
+	cfg, err := a.config()
 	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
+		return nil, err
 	}
+	repo, err := storage.NewSQLiteRepository(cfg.DBPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create repository: %w", err)
+	}
+	progressf("Database: %s\n", cfg.DBPath)
 
-	transcript := string(content)
-	fmt.Printf("Transcript length: %d characters\n\n", len(transcript))
-
-	// プロセッサ作成
-	processor := service.NewTranscriptProcessor(provider, repo)
+	a.repo = repo
+	return repo, nil
+}
 
-	// 処理実行
-	result, err := processor.Process(ctx, transcript)
+// llmProvider はLLMの設定を検証してプロバイダーを作成
+func (a *app) llmProvider() (llm.Provider, error) {
+	cfg, err := a.config()
 	if err != nil {
-		return fmt.Errorf("failed to process transcript: %w", err)
+		return nil, err
 	}
+	if err := cfg.ValidateLLM(); err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	provider, err := llm.NewProvider(cfg.LLM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create LLM provider: %w", err)
+	}
+	progressf("Using LLM: %s (%s)\n", cfg.LLM.Type, provider.GetModelName())
+	return provider, nil
+}
 
-	// 結果表示
-	fmt.Println("\n" + strings.Repeat("=", 50))
-	fmt.Println("処理完了")
-	fmt.Println(strings.Repeat("=", 50))
-	fmt.Printf("抽出した表現: %d個\n", result.TotalExpressions)
-	fmt.Printf("新規登録: %d個\n", result.NewExpressions)
-	fmt.Printf("優先度更新: %d個\n", result.UpdatedPriority)
-	fmt.Println(strings.Repeat("=", 50))
-
-	return nil
+// processorOptions は抽出に使う学習者のプロフィール（データベースの値に環境変数の設定を上書き）、
+// カテゴリの分類体系、タグのルールを読み込み、TranscriptProcessorのオプションにする
+func (a *app) processorOptions(ctx context.Context, repo storage.Repository) ([]service.ProcessorOption, error) {
+	cfg, err := a.config()
+	if err != nil {
+		return nil, err
+	}
+	profile, err := service.LoadProfile(ctx, repo, cfg.Profile)
+	if err != nil {
+		return nil, err
+	}
+	a.logger.Info("learner profile", "role", profile.Role, "domain", profile.Domain, "level", profile.Level,
+		"goals", profile.Goals, "native_language", profile.NativeLanguage)
+	taxonomy, err := repo.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	a.logger.Info("category taxonomy", "categories", len(taxonomy))
+	rules, err := repo.ListTagRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	a.logger.Info("tag rules", "rules", len(rules))
+	return []service.ProcessorOption{
+		service.WithLogger(a.logger),
+		service.WithProfile(profile),
+		service.WithCategories(taxonomy),
+		service.WithTagRules(rules),
+	}, nil
 }
 
-func testLLM(ctx context.Context, provider llm.Provider) error {
-	fmt.Println("\nTesting LLM connection...")
+// close は開いたリソースをクリーンアップ
+func (a *app) close() {
+	if a.repo != nil {
+		a.repo.Close()
+	}
+}
 
-	response, err := provider.Generate(ctx, "Say 'Hello, World!' in Japanese.")
+// completeExpressions は登録済みの表現をシェル補完の候補として返す
+func (a *app) completeExpressions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := a.config()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	// 補完中は "Database: ..." を出力しないよう直接開く
+	repo, err := storage.NewSQLiteRepository(cfg.DBPath)
 	if err != nil {
-		return fmt.Errorf("LLM test failed: %w", err)
+		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
+	defer repo.Close()
 
-	fmt.Printf("Response: %s\n", response)
-	return nil
+	expressions, err := repo.GetAllExpressions(cmd.Context())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var candidates []string
+	for _, expr := range expressions {
+		if strings.HasPrefix(strings.ToLower(expr.Expression), strings.ToLower(toComplete)) {
+			candidates = append(candidates, expr.Expression)
+		}
+	}
+	return candidates, cobra.ShellCompDirectiveNoFileComp
 }
 
-func listExpressions(ctx context.Context, repo storage.Repository) error {
-	fmt.Println("\nListing expressions...")
+// completeCategories は分類体系のカテゴリをシェル補完の候補として返す
+func (a *app) completeCategories(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := a.config()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	repo, err := storage.NewSQLiteRepository(cfg.DBPath)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer repo.Close()
+
+	taxonomy, err := repo.ListCategories(cmd.Context())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	candidates := make([]string, 0, len(taxonomy))
+	for _, c := range taxonomy {
+		candidates = append(candidates, c.Name+"\t"+c.Label())
+	}
+	return candidates, cobra.ShellCompDirectiveNoFileComp
+}
 
-	expressions, err := repo.GetAllExpressions(ctx)
+// completeTags は使われているタグをシェル補完の候補として返す
+func (a *app) completeTags(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := a.config()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	repo, err := storage.NewSQLiteRepository(cfg.DBPath)
 	if err != nil {
-		return fmt.Errorf("failed to get expressions: %w", err)
+		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
+	defer repo.Close()
 
-	if len(expressions) == 0 {
-		fmt.Println("No expressions found.")
-		return nil
+	tags, err := repo.ListTags(cmd.Context())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	candidates := make([]string, 0, len(tags))
+	for _, t := range tags {
+		candidates = append(candidates, t.Name)
 	}
+	return candidates, cobra.ShellCompDirectiveNoFileComp
+}
 
-	fmt.Printf("Found %d expression(s):\n\n", len(expressions))
-	for _, expr := range expressions {
-		fmt.Printf("- %s (%s)\n", expr.Expression, expr.Type)
-		fmt.Printf("  Meaning: %s\n", expr.Meaning)
-		fmt.Printf("  Priority: %d, Occurrences: %d\n", expr.Priority, expr.OccurrenceCount)
-		fmt.Printf("  Category: %s\n\n", expr.Category)
+func newRootCmd(a *app) *cobra.Command {
+	root := &cobra.Command{
+		Use:          "extract",
+		Short:        "Extract and study English expressions from meeting transcripts",
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := a.setupLogger(); err != nil {
+				return err
+			}
+			for _, f := range outputFormats {
+				if a.output == f {
+					return nil
+				}
+			}
+			return fmt.Errorf("invalid --output: %s (expected %s)", a.output, strings.Join(outputFormats, ", "))
+		},
 	}
+	root.PersistentFlags().StringVar(&a.dbPath, "db", "", "SQLite database path (default: $DB_PATH or ./expressions.db)")
+	root.PersistentFlags().StringVar(&a.providerType, "provider", "", "LLM provider: anthropic or vertexai (default: $LLM_PROVIDER or anthropic)")
+	root.PersistentFlags().StringVar(&a.model, "model", "", "LLM model name (default: $MODEL_NAME)")
+	root.PersistentFlags().StringVarP(&a.output, "output", "o", outputText, "result format: text, json or table (progress messages go to stderr)")
+	root.PersistentFlags().StringVar(&a.logLevel, "log-level", "warn", "log level for stderr: debug, info, warn or error")
+	root.RegisterFlagCompletionFunc("log-level", cobra.FixedCompletions([]string{"debug", "info", "warn", "error"}, cobra.ShellCompDirectiveNoFileComp))
+	root.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(outputFormats, cobra.ShellCompDirectiveNoFileComp))
+	root.RegisterFlagCompletionFunc("provider", cobra.FixedCompletions([]string{"anthropic", "vertexai"}, cobra.ShellCompDirectiveNoFileComp))
 
-	return nil
+	root.AddCommand(
+		newExtractCmd(a),
+		newExportCmd(a),
+		newTestCmd(a),
+		newListCmd(a),
+		newSearchCmd(a),
+		newShowCmd(a),
+		newContextsCmd(a),
+		newEditCmd(a),
+		newDeleteCmd(a),
+		newAddCmd(a),
+		newMergeCmd(a),
+		newSplitCmd(a),
+		newWatchCmd(a),
+		newServeCmd(a),
+		newReviewCmd(a),
+		newStatsCmd(a),
+		newRescoreCmd(a),
+		newProfileCmd(a),
+		newCategoryCmd(a),
+		newTagCmd(a),
+		newEnrichCmd(a),
+	)
+
+	return root
 }
 
-func exportToCSV(ctx context.Context, repo storage.Repository, outputPath string) error {
-	fmt.Printf("\nExporting expressions to CSV: %s\n\n", outputPath)
+func newTestCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "test",
+		Short: "Test LLM connection",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			provider, err := a.llmProvider()
+			if err != nil {
+				return err
+			}
+
+			progressf("\nTesting LLM connection...\n")
 
-	// CSV Exporter作成
-	exporter := output.NewCSVExporter(repo)
+			response, err := provider.Generate(cmd.Context(), "Say 'Hello, World!' in Japanese.")
+			if err != nil {
+				return fmt.Errorf("LLM test failed: %w", err)
+			}
 
-	// エクスポート実行
-	if err := exporter.Export(ctx, outputPath); err != nil {
-		return fmt.Errorf("failed to export: %w", err)
+			return a.emit(&testResult{Model: provider.GetModelName(), Response: response})
+		},
 	}
+}
 
-	// 件数確認
-	expressions, err := repo.ListExpressions(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to count expressions: %w", err)
+func newListCmd(a *app) *cobra.Command {
+	var tags []string
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List all expressions",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tags, err := models.NormalizeTags(tags)
+			if err != nil {
+				return err
+			}
+			repo, err := a.repository()
+			if err != nil {
+				return err
+			}
+
+			progressf("\nListing expressions...\n")
+
+			all, err := repo.GetAllExpressions(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to get expressions: %w", err)
+			}
+			expressions := make([]*models.Expression, 0, len(all))
+			for _, expr := range all {
+				if expr.HasTags(tags...) {
+					expressions = append(expressions, expr)
+				}
+			}
+
+			return a.emit(newExpressionListResult("", expressions))
+		},
 	}
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "only expressions with all of these tags (repeatable)")
+	a.registerCompletions(cmd)
+	return cmd
+}
 
-	fmt.Println(strings.Repeat("=", 50))
-	fmt.Println("エクスポート完了")
-	fmt.Println(strings.Repeat("=", 50))
-	fmt.Printf("出力ファイル: %s\n", outputPath)
-	fmt.Printf("エクスポート件数: %d個\n", len(expressions))
-	fmt.Println(strings.Repeat("=", 50))
+func newSearchCmd(a *app) *cobra.Command {
+	var opts storage.SearchOptions
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Search expressions, meanings and contexts",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := a.repository()
+			if err != nil {
+				return err
+			}
 
-	return nil
+			tags, err := models.NormalizeTags(opts.Tags)
+			if err != nil {
+				return err
+			}
+			opts.Tags = tags
+			opts.Query = strings.Join(args, " ")
+			progressf("\nSearching expressions: %s\n", opts.Query)
+
+			expressions, err := repo.Search(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+
+			return a.emit(newExpressionListResult(opts.Query, expressions))
+		},
+	}
+	cmd.Flags().StringVar(&opts.Type, "type", "", "filter by type (word/phrase)")
+	cmd.Flags().StringVar(&opts.Category, "category", "", "filter by category")
+	cmd.Flags().StringSliceVar(&opts.Tags, "tag", nil, "only expressions with all of these tags (repeatable)")
+	cmd.Flags().IntVar(&opts.MinPriority, "min-priority", 0, "minimum priority (1-5)")
+	cmd.Flags().IntVar(&opts.Limit, "limit", 50, "maximum number of results (0 = unlimited)")
+	a.registerCompletions(cmd)
+	return cmd
+}
+
+// testResult はLLM接続テストの結果
+type testResult struct {
+	Model    string `json:"model"`
+	Response string `json:"response"`
+}
+
+func (r *testResult) writeText(w io.Writer) {
+	fmt.Fprintf(w, "Response: %s\n", r.Response)
+}
+
+func (r *testResult) tableRows() [][]string {
+	return [][]string{{"MODEL", "RESPONSE"}, {r.Model, r.Response}}
+}
+
+// registerCompletions は共通のフラグ値の補完候補を登録
+func (a *app) registerCompletions(cmd *cobra.Command) {
+	if cmd.Flags().Lookup("type") != nil {
+		types := []string{string(models.TypeWord), string(models.TypePhrase)}
+		cmd.RegisterFlagCompletionFunc("type", cobra.FixedCompletions(types, cobra.ShellCompDirectiveNoFileComp))
+	}
+	if cmd.Flags().Lookup("category") != nil {
+		cmd.RegisterFlagCompletionFunc("category", a.completeCategories)
+	}
+	if cmd.Flags().Lookup("tag") != nil {
+		cmd.RegisterFlagCompletionFunc("tag", a.completeTags)
+	}
 }
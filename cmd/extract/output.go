@@ -0,0 +1,440 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/service"
+)
+
+// --output の値
+const (
+	outputText  = "text"
+	outputJSON  = "json"
+	outputTable = "table"
+)
+
+// outputFormats は --output に指定できる値
+var outputFormats = []string{outputText, outputJSON, outputTable}
+
+// result はコマンドの結果（--output に応じてテキスト・JSON・表で標準出力に書き出す）
+// JSONはresultそのものをエンコードする
+type result interface {
+	// writeText は人が読むための文章で出力
+	writeText(w io.Writer)
+	// tableRows は表の見出し行とデータ行を返す
+	tableRows() [][]string
+}
+
+// emit は結果を --output の形式で標準出力に書き出す
+func (a *app) emit(r result) error {
+	switch a.output {
+	case outputJSON:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(r); err != nil {
+			return fmt.Errorf("failed to write JSON: %w", err)
+		}
+	case outputTable:
+		if err := writeRows(os.Stdout, r.tableRows()); err != nil {
+			return fmt.Errorf("failed to write table: %w", err)
+		}
+	default:
+		r.writeText(os.Stdout)
+	}
+	return nil
+}
+
+// writeRows は行を列を揃えた表として書き出す
+func writeRows(w io.Writer, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(sanitizeCells(row), "\t"))
+	}
+	return tw.Flush()
+}
+
+// sanitizeCells は表が崩れないようセル内のタブ・改行を空白に置き換える
+func sanitizeCells(row []string) []string {
+	cells := make([]string, len(row))
+	for i, cell := range row {
+		cells[i] = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ").Replace(cell)
+	}
+	return cells
+}
+
+// progressf は進捗などの人向けメッセージを標準エラー出力に書き出す（標準出力は結果のみ）
+func progressf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format, args...)
+}
+
+// expressionResult は表現の全項目
+type expressionResult struct {
+	ID              int       `json:"id"`
+	Expression      string    `json:"expression"`
+	Type            string    `json:"type"`
+	Meaning         string    `json:"meaning"`
+	Priority        int       `json:"priority"`
+	BasePriority    int       `json:"base_priority"`
+	Score           float64   `json:"score"`
+	Category        string    `json:"category"`   // 主カテゴリ
+	Categories      []string  `json:"categories"` // 関連の強い順（主カテゴリが先頭）
+	Tags            []string  `json:"tags"`
+	OccurrenceCount int       `json:"occurrence_count"`
+	FirstSeenAt     time.Time `json:"first_seen_at"`
+	LastSeenAt      time.Time `json:"last_seen_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	ManuallyEdited  bool      `json:"manually_edited"`
+}
+
+func newExpressionResult(expr *models.Expression) expressionResult {
+	return expressionResult{
+		ID:              expr.ID,
+		Expression:      expr.Expression,
+		Type:            expr.Type,
+		Meaning:         expr.Meaning,
+		Priority:        expr.Priority,
+		BasePriority:    expr.BasePriority,
+		Score:           expr.Score,
+		Category:        expr.Category,
+		Categories:      append([]string{}, expr.Categories...),
+		Tags:            append([]string{}, expr.Tags...),
+		OccurrenceCount: expr.OccurrenceCount,
+		FirstSeenAt:     expr.FirstSeenAt,
+		LastSeenAt:      expr.LastSeenAt,
+		UpdatedAt:       expr.UpdatedAt,
+		ManuallyEdited:  expr.ManuallyEdited,
+	}
+}
+
+// categoryText はカテゴリをカンマ区切りにする（未分類は "-"）
+func (e expressionResult) categoryText() string {
+	if len(e.Categories) == 0 {
+		return "-"
+	}
+	return strings.Join(e.Categories, ", ")
+}
+
+// tagText はタグをカンマ区切りにする（タグなしは "-"）
+func (e expressionResult) tagText() string {
+	if len(e.Tags) == 0 {
+		return "-"
+	}
+	return strings.Join(e.Tags, ", ")
+}
+
+// expressionTableHeader は表現一覧の見出し行
+var expressionTableHeader = []string{"ID", "EXPRESSION", "TYPE", "PRIORITY", "OCCURRENCES", "CATEGORY", "MEANING"}
+
+func (e expressionResult) tableRow() []string {
+	return []string{fmt.Sprint(e.ID), e.Expression, e.Type, fmt.Sprint(e.Priority), fmt.Sprint(e.OccurrenceCount), e.categoryText(), e.Meaning}
+}
+
+// keyValueRows は1件の表現をFIELD/VALUEの表にする
+func (e expressionResult) keyValueRows() [][]string {
+	return [][]string{
+		{"FIELD", "VALUE"},
+		{"id", fmt.Sprint(e.ID)},
+		{"expression", e.Expression},
+		{"type", e.Type},
+		{"meaning", e.Meaning},
+		{"priority", fmt.Sprint(e.Priority)},
+		{"base_priority", fmt.Sprint(e.BasePriority)},
+		{"score", fmt.Sprintf("%.2f", e.Score)},
+		{"categories", e.categoryText()},
+		{"tags", e.tagText()},
+		{"occurrence_count", fmt.Sprint(e.OccurrenceCount)},
+		{"first_seen_at", formatTime(e.FirstSeenAt)},
+		{"last_seen_at", formatTime(e.LastSeenAt)},
+		{"updated_at", formatTime(e.UpdatedAt)},
+		{"manually_edited", fmt.Sprint(e.ManuallyEdited)},
+	}
+}
+
+// expressionListResult は表現の一覧（list, search）
+type expressionListResult struct {
+	Query       string             `json:"query,omitempty"`
+	Expressions []expressionResult `json:"expressions"`
+}
+
+func newExpressionListResult(query string, expressions []*models.Expression) *expressionListResult {
+	r := &expressionListResult{Query: query, Expressions: make([]expressionResult, 0, len(expressions))}
+	for _, expr := range expressions {
+		r.Expressions = append(r.Expressions, newExpressionResult(expr))
+	}
+	return r
+}
+
+func (r *expressionListResult) writeText(w io.Writer) {
+	if len(r.Expressions) == 0 {
+		fmt.Fprintln(w, "No expressions found.")
+		return
+	}
+
+	fmt.Fprintf(w, "Found %d expression(s):\n\n", len(r.Expressions))
+	for _, expr := range r.Expressions {
+		fmt.Fprintf(w, "- %s (%s)\n", expr.Expression, expr.Type)
+		fmt.Fprintf(w, "  Meaning: %s\n", expr.Meaning)
+		fmt.Fprintf(w, "  Priority: %d, Occurrences: %d\n", expr.Priority, expr.OccurrenceCount)
+		fmt.Fprintf(w, "  Category: %s\n", expr.categoryText())
+		if len(expr.Tags) > 0 {
+			fmt.Fprintf(w, "  Tags: %s\n", expr.tagText())
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func (r *expressionListResult) tableRows() [][]string {
+	rows := [][]string{expressionTableHeader}
+	for _, expr := range r.Expressions {
+		rows = append(rows, expr.tableRow())
+	}
+	return rows
+}
+
+// senseResult は表現の語義
+type senseResult struct {
+	ID              int    `json:"id"`
+	PartOfSpeech    string `json:"part_of_speech"`
+	Gloss           string `json:"gloss"`
+	Definition      string `json:"definition"`
+	OccurrenceCount int    `json:"occurrence_count"`
+}
+
+func newSenseResults(senses []*models.Sense) []senseResult {
+	results := make([]senseResult, 0, len(senses))
+	for _, s := range senses {
+		results = append(results, senseResult{
+			ID:              s.ID,
+			PartOfSpeech:    s.PartOfSpeech,
+			Gloss:           s.Gloss,
+			Definition:      s.Definition,
+			OccurrenceCount: s.OccurrenceCount,
+		})
+	}
+	return results
+}
+
+// text は表示用の語義
+func (s senseResult) text() string {
+	return (&models.Sense{PartOfSpeech: s.PartOfSpeech, Gloss: s.Gloss, Definition: s.Definition}).String()
+}
+
+// usageResult は表現の使い方（enrichで生成した例文・使用上の注意・関連語）
+type usageResult struct {
+	Examples     []models.Example `json:"examples"`
+	Register     string           `json:"register,omitempty"`
+	Note         string           `json:"note,omitempty"`
+	Collocations []string         `json:"collocations"`
+	Synonyms     []string         `json:"synonyms"`
+	Antonyms     []string         `json:"antonyms"`
+	GeneratedAt  time.Time        `json:"generated_at"`
+}
+
+// newUsageResult は使い方を出力用にする（生成していなければnil）
+func newUsageResult(u *models.Usage) *usageResult {
+	if u == nil {
+		return nil
+	}
+	return &usageResult{
+		Examples:     append([]models.Example{}, u.Examples...),
+		Register:     u.Register,
+		Note:         u.Note,
+		Collocations: append([]string{}, u.Collocations...),
+		Synonyms:     append([]string{}, u.Synonyms...),
+		Antonyms:     append([]string{}, u.Antonyms...),
+		GeneratedAt:  u.GeneratedAt,
+	}
+}
+
+// writeText は使い方を字下げして出力
+func (u *usageResult) writeText(w io.Writer) {
+	if len(u.Examples) > 0 {
+		fmt.Fprintf(w, "\n  Examples (%d):\n", len(u.Examples))
+		for _, e := range u.Examples {
+			fmt.Fprintf(w, "  - %s\n", e.Sentence)
+			if e.Translation != "" {
+				fmt.Fprintf(w, "    %s\n", e.Translation)
+			}
+		}
+	}
+	fmt.Fprintln(w)
+	if u.Register != "" {
+		fmt.Fprintf(w, "  Register: %s\n", u.Register)
+	}
+	if u.Note != "" {
+		fmt.Fprintf(w, "  Usage note: %s\n", u.Note)
+	}
+	for _, rel := range u.relatedWords() {
+		if len(rel.words) > 0 {
+			fmt.Fprintf(w, "  %s: %s\n", rel.label, strings.Join(rel.words, ", "))
+		}
+	}
+}
+
+// relatedWords は関連語の種類ごとの表示名と語句
+func (u *usageResult) relatedWords() []struct {
+	label string
+	words []string
+} {
+	return []struct {
+		label string
+		words []string
+	}{
+		{"Collocations", u.Collocations},
+		{"Synonyms", u.Synonyms},
+		{"Antonyms", u.Antonyms},
+	}
+}
+
+// tableRows は使い方をキーと値の行にする
+func (u *usageResult) tableRows() [][]string {
+	var rows [][]string
+	for i, e := range u.Examples {
+		rows = append(rows, []string{fmt.Sprintf("example_%d", i+1), e.Sentence})
+	}
+	rows = append(rows, []string{"register", u.Register}, []string{"usage_note", u.Note})
+	for _, rel := range u.relatedWords() {
+		rows = append(rows, []string{strings.ToLower(rel.label), strings.Join(rel.words, ", ")})
+	}
+	return rows
+}
+
+// occurrenceResult は出現履歴
+type occurrenceResult struct {
+	ID         int       `json:"id"`
+	Context    string    `json:"context"`
+	Surface    string    `json:"surface,omitempty"`
+	Meeting    string    `json:"meeting,omitempty"`
+	SenseID    int       `json:"sense_id,omitempty"` // 文脈で使われた語義（未判定なら省略）
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func newOccurrenceResults(occs []*models.ExpressionOccurrence) []occurrenceResult {
+	results := make([]occurrenceResult, 0, len(occs))
+	for _, occ := range occs {
+		results = append(results, occurrenceResult{
+			ID:         occ.ID,
+			Context:    occ.Context,
+			Surface:    occ.Surface,
+			Meeting:    occ.Meeting,
+			SenseID:    occ.SenseID,
+			OccurredAt: occ.OccurredAt,
+		})
+	}
+	return results
+}
+
+// occurrenceTableRows は出現履歴の表
+func occurrenceTableRows(occs []occurrenceResult) [][]string {
+	rows := [][]string{{"OCCURRED_AT", "MEETING", "SURFACE", "CONTEXT"}}
+	for _, occ := range occs {
+		rows = append(rows, []string{occ.OccurredAt.Format("2006-01-02 15:04"), occ.Meeting, occ.Surface, occ.Context})
+	}
+	return rows
+}
+
+// processResult は1つのtranscriptの抽出結果
+type processResult struct {
+	Path             string                      `json:"path,omitempty"`
+	Meeting          string                      `json:"meeting"`
+	DryRun           bool                        `json:"dry_run,omitempty"`
+	Tags             []string                    `json:"tags,omitempty"` // 出現したすべての表現につけたタグ
+	TotalExpressions int                         `json:"total_expressions"`
+	NewExpressions   int                         `json:"new_expressions"`
+	UpdatedPriority  int                         `json:"updated_priority"`
+	Expressions      []processedExpressionResult `json:"expressions"`
+	Report           string                      `json:"report,omitempty"`
+}
+
+// processedExpressionResult は抽出で登録・更新された表現
+type processedExpressionResult struct {
+	expressionResult
+	New              bool     `json:"new"`
+	PreviousPriority int      `json:"previous_priority"`
+	Contexts         []string `json:"contexts,omitempty"`
+}
+
+func newProcessResult(path string, dryRun bool, result *service.ProcessResult) *processResult {
+	r := &processResult{
+		Path:             path,
+		Meeting:          result.Meeting,
+		DryRun:           dryRun,
+		Tags:             result.Tags,
+		TotalExpressions: result.TotalExpressions,
+		NewExpressions:   result.NewExpressions,
+		UpdatedPriority:  result.UpdatedPriority,
+		Expressions:      make([]processedExpressionResult, 0, len(result.Expressions)),
+	}
+	for _, e := range result.Expressions {
+		res := processedExpressionResult{
+			expressionResult: newExpressionResult(e.Expression),
+			New:              e.New,
+			PreviousPriority: e.PreviousPriority,
+		}
+		for _, c := range e.Contexts {
+			res.Contexts = append(res.Contexts, c.Text)
+		}
+		r.Expressions = append(r.Expressions, res)
+	}
+	return r
+}
+
+func (r *processResult) writeText(w io.Writer) {
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 50))
+	fmt.Fprintln(w, "処理完了")
+	fmt.Fprintln(w, strings.Repeat("=", 50))
+	fmt.Fprintf(w, "抽出した表現: %d個\n", r.TotalExpressions)
+	fmt.Fprintf(w, "新規登録: %d個\n", r.NewExpressions)
+	fmt.Fprintf(w, "優先度更新: %d個\n", r.UpdatedPriority)
+	if len(r.Tags) > 0 {
+		fmt.Fprintf(w, "タグ: %s\n", strings.Join(r.Tags, ", "))
+	}
+	fmt.Fprintln(w, strings.Repeat("=", 50))
+
+	if r.DryRun {
+		fmt.Fprintln(w, "\nドライラン: データベースには保存していません")
+		writeRows(w, r.dryRunRows())
+	}
+	if r.Report != "" {
+		fmt.Fprintf(w, "レポート: %s\n", r.Report)
+	}
+}
+
+func (r *processResult) tableRows() [][]string {
+	return r.dryRunRows()
+}
+
+// dryRunRows は表現ごとの新規／既存・優先度の変化・意味の表
+func (r *processResult) dryRunRows() [][]string {
+	rows := [][]string{{"STATUS", "EXPRESSION", "TYPE", "PRIORITY", "CATEGORY", "OCCURRENCES", "MEANING"}}
+	for _, e := range r.Expressions {
+		status := "existing"
+		if e.New {
+			status = "new"
+		}
+		priority := fmt.Sprint(e.Priority)
+		if !e.New && e.PreviousPriority != e.Priority {
+			priority = fmt.Sprintf("%d -> %d", e.PreviousPriority, e.Priority)
+		}
+		if e.ManuallyEdited {
+			priority += " (manual)"
+		}
+		rows = append(rows, []string{status, e.Expression, e.Type, priority, e.categoryText(), fmt.Sprint(e.OccurrenceCount), e.Meaning})
+	}
+	return rows
+}
+
+// formatTime は表示用の日時（ゼロ値は空）
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/service"
+)
+
+func newProfileCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Show the learner profile used to prioritize and categorize expressions",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return showProfile(cmd, a)
+		},
+	}
+	cmd.AddCommand(newProfileSetCmd(a), newProfileResetCmd(a))
+	return cmd
+}
+
+func newProfileSetCmd(a *app) *cobra.Command {
+	var role, domain, level, goals, nativeLanguage string
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "Update the learner profile stored in the database (only the given fields)",
+		Example: `  extract profile set --role "backend engineer" --domain payments
+  extract profile set --level "TOEIC 750" --goals "speak up in design reviews"
+  extract profile set --domain ""    # clear a field`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags := cmd.Flags()
+			if flags.NFlag() == 0 {
+				return fmt.Errorf("nothing to update: specify --role, --domain, --level, --goals or --native-language")
+			}
+			repo, err := a.repository()
+			if err != nil {
+				return err
+			}
+
+			profile, err := repo.GetLearnerProfile(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if flags.Changed("role") {
+				profile.Role = role
+			}
+			if flags.Changed("domain") {
+				profile.Domain = domain
+			}
+			if flags.Changed("level") {
+				profile.Level = level
+			}
+			if flags.Changed("goals") {
+				profile.Goals = goals
+			}
+			if flags.Changed("native-language") {
+				profile.NativeLanguage = nativeLanguage
+			}
+			if err := repo.SaveLearnerProfile(cmd.Context(), profile); err != nil {
+				return err
+			}
+			progressf("✓ 学習者のプロフィールを更新しました\n")
+			return showProfile(cmd, a)
+		},
+	}
+	cmd.Flags().StringVar(&role, "role", "", "your role (e.g. \"backend engineer\", \"product manager\")")
+	cmd.Flags().StringVar(&domain, "domain", "", "your business or technical domain (e.g. payments, ads)")
+	cmd.Flags().StringVar(&level, "level", "", "your current English level (e.g. \"TOEIC 750\", \"intermediate\")")
+	cmd.Flags().StringVar(&goals, "goals", "", "what you want to be able to do in English")
+	cmd.Flags().StringVar(&nativeLanguage, "native-language", "", "language for meanings (default: 日本語)")
+	return cmd
+}
+
+func newProfileResetCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "reset",
+		Short: "Clear the learner profile stored in the database",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := a.repository()
+			if err != nil {
+				return err
+			}
+			if err := repo.SaveLearnerProfile(cmd.Context(), &models.LearnerProfile{}); err != nil {
+				return err
+			}
+			progressf("✓ 学習者のプロフィールを初期化しました\n")
+			return showProfile(cmd, a)
+		},
+	}
+}
+
+// showProfile は保存されたプロフィールと抽出に使うプロフィールを出力
+func showProfile(cmd *cobra.Command, a *app) error {
+	repo, err := a.repository()
+	if err != nil {
+		return err
+	}
+	stored, err := repo.GetLearnerProfile(cmd.Context())
+	if err != nil {
+		return err
+	}
+	cfg, err := a.config()
+	if err != nil {
+		return err
+	}
+	effective, err := service.LoadProfile(cmd.Context(), repo, cfg.Profile)
+	if err != nil {
+		return err
+	}
+
+	r := &profileResult{Stored: newProfileFields(*stored), Effective: newProfileFields(effective)}
+	if !stored.UpdatedAt.IsZero() {
+		r.UpdatedAt = &stored.UpdatedAt
+	}
+	return a.emit(r)
+}
+
+// profileFields は学習者のプロフィールの各項目
+type profileFields struct {
+	Role           string `json:"role"`
+	Domain         string `json:"domain"`
+	Level          string `json:"level"`
+	Goals          string `json:"goals"`
+	NativeLanguage string `json:"native_language"`
+}
+
+func newProfileFields(p models.LearnerProfile) profileFields {
+	return profileFields{Role: p.Role, Domain: p.Domain, Level: p.Level, Goals: p.Goals, NativeLanguage: p.NativeLanguage}
+}
+
+// profileResult は学習者のプロフィール（profile）
+// Storedはデータベースの値、Effectiveは環境変数（LEARNER_*）とデフォルト値を反映して抽出に使う値
+type profileResult struct {
+	Stored    profileFields `json:"stored"`
+	Effective profileFields `json:"effective"`
+	UpdatedAt *time.Time    `json:"updated_at,omitempty"`
+}
+
+// profileFieldNames は表示する項目（キー、見出し、値の取り出し）
+var profileFieldNames = []struct {
+	key, label string
+	get        func(profileFields) string
+}{
+	{"role", "役割", func(f profileFields) string { return f.Role }},
+	{"domain", "分野", func(f profileFields) string { return f.Domain }},
+	{"level", "レベル", func(f profileFields) string { return f.Level }},
+	{"goals", "目的", func(f profileFields) string { return f.Goals }},
+	{"native_language", "母語", func(f profileFields) string { return f.NativeLanguage }},
+}
+
+func (r *profileResult) writeText(w io.Writer) {
+	fmt.Fprintln(w, "学習者のプロフィール（抽出時の優先度・カテゴリの判定に使用）")
+	for _, f := range profileFieldNames {
+		value := f.get(r.Effective)
+		if value == "" {
+			value = "-"
+		}
+		// 保存値と異なるものは環境変数かデフォルト値
+		note := ""
+		if f.get(r.Effective) != f.get(r.Stored) {
+			note = "  (環境変数またはデフォルト)"
+		}
+		fmt.Fprintf(w, "  %s: %s%s\n", f.label, value, note)
+	}
+	if r.UpdatedAt != nil {
+		fmt.Fprintf(w, "更新日時: %s\n", formatTime(*r.UpdatedAt))
+	}
+}
+
+func (r *profileResult) tableRows() [][]string {
+	rows := [][]string{{"FIELD", "EFFECTIVE", "STORED"}}
+	for _, f := range profileFieldNames {
+		rows = append(rows, []string{f.key, f.get(r.Effective), f.get(r.Stored)})
+	}
+	return rows
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/service"
+)
+
+// stageLabels は進捗に表示する段階名
+var stageLabels = map[service.Stage]string{
+	service.StageExtractWords:   "単語抽出",
+	service.StageExtractPhrases: "熟語抽出",
+	service.StagePrioritize:     "優先度判定",
+	service.StageSave:           "保存",
+	service.StageDone:           "完了",
+}
+
+// barWidth はプログレスバーの幅（文字数）
+const barWidth = 30
+
+// progressBar は抽出の進捗を標準エラー出力に表示する
+// inPlaceなら1行を書き換えるプログレスバー、それ以外は段階の終了（優先度判定はバッチ）ごとに1行
+type progressBar struct {
+	mu      sync.Mutex
+	w       io.Writer
+	inPlace bool
+}
+
+// newProgressBar は進捗の表示を作成
+// 行の書き換えは標準エラー出力が端末で、他の出力（複数ファイルの並列処理・ログ）と混ざらない場合のみ
+func newProgressBar(inPlace bool) *progressBar {
+	return &progressBar{w: os.Stderr, inPlace: inPlace && isTerminal(os.Stderr)}
+}
+
+// handle はservice.ProgressFuncとして進捗を表示（並行に呼ばれても安全）
+func (b *progressBar) handle(ev service.ProgressEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	label := stageLabels[ev.Stage]
+	if b.inPlace {
+		if ev.Stage == service.StageDone {
+			fmt.Fprint(b.w, "\r\x1b[K")
+			return
+		}
+		fmt.Fprintf(b.w, "\r\x1b[K%s %s %d/%d%s", bar(ev.Current, ev.Total), label, ev.Current, ev.Total, found(ev))
+		return
+	}
+
+	if ev.Current == 0 || (ev.Current != ev.Total && ev.Stage != service.StagePrioritize) {
+		return
+	}
+	if ev.Stage == service.StageDone {
+		fmt.Fprintf(b.w, "[%s] %s\n", ev.Meeting, label)
+		return
+	}
+	fmt.Fprintf(b.w, "[%s] %s %d/%d%s\n", ev.Meeting, label, ev.Current, ev.Total, found(ev))
+}
+
+// bar は進捗を [=====>    ] の形で表す
+func bar(current, total int) string {
+	filled := barWidth
+	if total > 0 {
+		filled = barWidth * current / total
+	}
+	head := ""
+	if filled < barWidth {
+		head = ">"
+	}
+	return "[" + strings.Repeat("=", filled) + head + strings.Repeat(" ", barWidth-filled-len(head)) + "]"
+}
+
+// found は抽出段階で見つかった表現の数の表示
+func found(ev service.ProgressEvent) string {
+	if ev.Found == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d個)", ev.Found)
+}
+
+// isTerminal はファイルが端末か判定
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
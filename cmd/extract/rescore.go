@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/scoring"
+	"github.com/mamyudapao/learn-by-transcript/internal/service"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+func newRescoreCmd(a *app) *cobra.Command {
+	var dryRun bool
+	var halfLifeDays float64
+	cmd := &cobra.Command{
+		Use:   "rescore",
+		Short: "Recompute scores and priorities of all expressions from occurrence and review history",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if halfLifeDays <= 0 {
+				return fmt.Errorf("--half-life must be positive")
+			}
+			var repo storage.Repository
+			repo, err := a.repository()
+			if err != nil {
+				return err
+			}
+			// ドライランでは変わる表現を表示するだけで保存しない
+			if dryRun {
+				repo = storage.NewDryRunRepository(repo)
+			}
+
+			params := scoring.DefaultParams()
+			params.HalfLife = time.Duration(halfLifeDays * float64(24*time.Hour))
+			result, err := service.Rescore(cmd.Context(), repo, params, time.Now())
+			if err != nil {
+				return err
+			}
+			return a.emit(newRescoreResult(result, dryRun))
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show which priorities would change without writing to the database")
+	cmd.Flags().Float64Var(&halfLifeDays, "half-life", scoring.DefaultParams().HalfLife.Hours()/24, "days after which an occurrence counts half")
+	return cmd
+}
+
+// scoreChangeResult は再計算で優先度が変わった表現
+type scoreChangeResult struct {
+	ID               int               `json:"id"`
+	Expression       string            `json:"expression"`
+	PreviousPriority int               `json:"previous_priority"`
+	Priority         int               `json:"priority"`
+	PreviousScore    float64           `json:"previous_score"`
+	Score            float64           `json:"score"`
+	Breakdown        scoring.Breakdown `json:"breakdown"`
+}
+
+// rescoreResult はスコアの再計算結果（rescore）
+type rescoreResult struct {
+	DryRun      bool                `json:"dry_run,omitempty"`
+	Expressions int                 `json:"expressions"`
+	Changes     []scoreChangeResult `json:"changes"`
+}
+
+func newRescoreResult(result *service.RescoreResult, dryRun bool) *rescoreResult {
+	r := &rescoreResult{DryRun: dryRun, Expressions: result.Expressions, Changes: []scoreChangeResult{}}
+	for _, c := range result.Changes {
+		r.Changes = append(r.Changes, scoreChangeResult{
+			ID:               c.Expression.ID,
+			Expression:       c.Expression.Expression,
+			PreviousPriority: c.PreviousPriority,
+			Priority:         c.Expression.Priority,
+			PreviousScore:    c.PreviousScore,
+			Score:            c.Expression.Score,
+			Breakdown:        c.Breakdown,
+		})
+	}
+	return r
+}
+
+func (r *rescoreResult) writeText(w io.Writer) {
+	if r.DryRun {
+		fmt.Fprintln(w, "ドライラン: データベースには保存していません")
+	}
+	fmt.Fprintf(w, "%d個の表現を再計算し、%d個の優先度が変わりました\n", r.Expressions, len(r.Changes))
+	if len(r.Changes) > 0 {
+		fmt.Fprintln(w)
+		writeRows(w, r.tableRows())
+	}
+}
+
+func (r *rescoreResult) tableRows() [][]string {
+	rows := [][]string{{"ID", "EXPRESSION", "PRIORITY", "SCORE", "BASE", "FREQUENCY", "DIVERSITY", "REVIEW"}}
+	for _, c := range r.Changes {
+		b := c.Breakdown
+		rows = append(rows, []string{
+			fmt.Sprint(c.ID), c.Expression,
+			fmt.Sprintf("%d → %d", c.PreviousPriority, c.Priority),
+			fmt.Sprintf("%.2f → %.2f", c.PreviousScore, c.Score),
+			fmt.Sprintf("%.0f", b.Base), fmt.Sprintf("%+.2f", b.Frequency), fmt.Sprintf("%+.2f", b.Diversity), fmt.Sprintf("%+.2f", b.Review),
+		})
+	}
+	return rows
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/review"
+	"github.com/mamyudapao/learn-by-transcript/internal/scoring"
+	"github.com/mamyudapao/learn-by-transcript/internal/service"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+func newReviewCmd(a *app) *cobra.Command {
+	var limit, newLimit int
+	var schedulerName string
+	cmd := &cobra.Command{
+		Use:   "review",
+		Short: "Review due expressions with spaced repetition",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// スケジューラの指定がなければREVIEW_SCHEDULERを使う
+			if schedulerName == "" {
+				cfg, err := a.config()
+				if err != nil {
+					return err
+				}
+				schedulerName = cfg.ReviewScheduler
+			}
+			repo, err := a.repository()
+			if err != nil {
+				return err
+			}
+			return reviewExpressions(cmd.Context(), a, repo, schedulerName, limit, newLimit)
+		},
+	}
+	cmd.Flags().IntVar(&limit, "limit", 50, "maximum number of cards in this session")
+	cmd.Flags().IntVar(&newLimit, "new", 10, "maximum number of new cards in this session")
+	cmd.Flags().StringVar(&schedulerName, "scheduler", "", "scheduler: sm2 or fsrs (default: $REVIEW_SCHEDULER or sm2)")
+	cmd.RegisterFlagCompletionFunc("scheduler", cobra.FixedCompletions([]string{"sm2", "fsrs"}, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+func reviewExpressions(ctx context.Context, a *app, repo storage.Repository, schedulerName string, limit, newLimit int) error {
+	scheduler, err := review.NewScheduler(schedulerName)
+	if err != nil {
+		return err
+	}
+
+	cards, err := repo.GetDueCards(ctx, time.Now(), limit, newLimit)
+	if err != nil {
+		return fmt.Errorf("failed to get due cards: %w", err)
+	}
+	if len(cards) == 0 {
+		progressf("\nNo cards due. Great job!\n")
+		return a.emit(&reviewResult{Scheduler: scheduler.Name(), Grades: make(map[string]int)})
+	}
+
+	progressf("\n復習開始: %d枚 (scheduler: %s)\n", len(cards), scheduler.Name())
+	reader := bufio.NewReader(os.Stdin)
+	reviewed := 0
+	graded := make(map[review.Grade]int)
+
+	// Againのカードはセッションの最後にもう一度出題する
+	queue := cards
+	requeued := make(map[int]bool)
+	for len(queue) > 0 {
+		card := queue[0]
+		queue = queue[1:]
+
+		grade, quit, err := askCard(ctx, repo, reader, card, reviewed+1, reviewed+1+len(queue))
+		if err != nil {
+			return err
+		}
+		if quit {
+			break
+		}
+
+		now := time.Now()
+		if card.IsNew() && card.Ease == 0 {
+			card = withNewState(card, now)
+		}
+		scheduler.Schedule(card, grade, now)
+
+		log := &models.ReviewLog{
+			ExpressionID: card.ExpressionID,
+			Grade:        int(grade),
+			Scheduler:    scheduler.Name(),
+			IntervalDays: card.IntervalDays,
+			ReviewedAt:   now,
+		}
+		if err := repo.SaveReview(ctx, card, log); err != nil {
+			return fmt.Errorf("failed to save review: %w", err)
+		}
+		// 復習成績をスコアに反映（苦手な表現ほど優先度が上がる）
+		if card.Expression != nil {
+			if _, err := service.RescoreExpression(ctx, repo, card.Expression, scoring.DefaultParams(), now); err != nil {
+				return err
+			}
+		}
+
+		reviewed++
+		graded[grade]++
+		progressf("  → 次回: %s\n", formatDue(card.DueAt, now))
+
+		if grade == review.GradeAgain && !requeued[card.ExpressionID] {
+			requeued[card.ExpressionID] = true
+			queue = append(queue, card)
+		}
+	}
+
+	result := &reviewResult{Scheduler: scheduler.Name(), Reviewed: reviewed, Grades: make(map[string]int)}
+	for _, g := range reviewGrades {
+		result.Grades[gradeKey(g)] = graded[g]
+	}
+	return a.emit(result)
+}
+
+// reviewGrades は評価の表示順
+var reviewGrades = []review.Grade{review.GradeAgain, review.GradeHard, review.GradeGood, review.GradeEasy}
+
+// gradeKey はJSONでの評価名（"again" など）
+func gradeKey(g review.Grade) string {
+	return strings.ToLower(g.String())
+}
+
+// reviewResult は復習セッションの結果
+type reviewResult struct {
+	Scheduler string         `json:"scheduler"`
+	Reviewed  int            `json:"reviewed"`
+	Grades    map[string]int `json:"grades"` // 評価ごとの枚数
+}
+
+func (r *reviewResult) writeText(w io.Writer) {
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 50))
+	fmt.Fprintln(w, "復習完了")
+	fmt.Fprintln(w, strings.Repeat("=", 50))
+	fmt.Fprintf(w, "復習した枚数: %d枚\n", r.Reviewed)
+	for _, g := range reviewGrades {
+		fmt.Fprintf(w, "  %s: %d\n", g, r.Grades[gradeKey(g)])
+	}
+	fmt.Fprintln(w, strings.Repeat("=", 50))
+}
+
+func (r *reviewResult) tableRows() [][]string {
+	header := []string{"SCHEDULER", "REVIEWED"}
+	row := []string{r.Scheduler, fmt.Sprint(r.Reviewed)}
+	for _, g := range reviewGrades {
+		header = append(header, strings.ToUpper(gradeKey(g)))
+		row = append(row, fmt.Sprint(r.Grades[gradeKey(g)]))
+	}
+	return [][]string{header, row}
+}
+
+// withNewState は未復習カードに初期状態を設定
+func withNewState(card *models.ReviewCard, now time.Time) *models.ReviewCard {
+	initial := review.NewCard(card.ExpressionID, now)
+	initial.Expression = card.Expression
+	return initial
+}
+
+// askCard はカードを表示して評価を入力させる（qで中断）
+func askCard(ctx context.Context, repo storage.Repository, reader *bufio.Reader, card *models.ReviewCard, index, total int) (review.Grade, bool, error) {
+	expr := card.Expression
+	label := "review"
+	if card.IsNew() {
+		label = "new"
+	}
+
+	progressf("\n[%d/%d] (%s) %s\n", index, total, label, expr.Expression)
+	progressf("  %s / %s\n", expr.Type, strings.Join(expr.Categories, ", "))
+	progressf("  Enterで答えを表示 (qで終了): ")
+	line, err := reader.ReadString('\n')
+	if err != nil && line == "" {
+		return 0, true, nil
+	}
+	if strings.TrimSpace(line) == "q" {
+		return 0, true, nil
+	}
+
+	progressf("  Meaning: %s\n", expr.Meaning)
+	occurrences, err := repo.GetOccurrences(ctx, expr.ID)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get occurrences: %w", err)
+	}
+	if len(occurrences) > 0 {
+		progressf("  Context: %s\n", occurrences[len(occurrences)-1].Context)
+	}
+
+	for {
+		progressf("  評価 [1]Again [2]Hard [3]Good [4]Easy (qで終了): ")
+		line, err := reader.ReadString('\n')
+		input := strings.TrimSpace(line)
+		if input == "q" || (err != nil && input == "") {
+			return 0, true, nil
+		}
+		var n int
+		if _, scanErr := fmt.Sscanf(input, "%d", &n); scanErr == nil && review.Grade(n).Valid() {
+			return review.Grade(n), false, nil
+		}
+		progressf("  1〜4を入力してください\n")
+	}
+}
+
+// formatDue は次回復習日時を相対表示
+func formatDue(due, now time.Time) string {
+	d := due.Sub(now)
+	if d < 24*time.Hour {
+		return fmt.Sprintf("%d分後", int(d.Minutes()+0.5))
+	}
+	return fmt.Sprintf("%d日後 (%s)", int(d.Hours()/24+0.5), due.Format("2006-01-02"))
+}
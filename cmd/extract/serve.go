@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/llm"
+	"github.com/mamyudapao/learn-by-transcript/internal/server"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+func newServeCmd(a *app) *cobra.Command {
+	var addr, corsOrigin string
+	var noExtract bool
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve a JSON REST API over the expression database",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := a.repository()
+			if err != nil {
+				return err
+			}
+
+			// 抽出APIはLLMの設定がある場合のみ有効
+			var provider llm.Provider
+			if !noExtract {
+				provider, err = a.llmProvider()
+				if err != nil {
+					progressf("Extraction disabled: %v\n", err)
+					provider = nil
+				}
+			}
+
+			cfg, err := a.config()
+			if err != nil {
+				return err
+			}
+			opts := []server.Option{server.WithProfile(cfg.Profile)}
+			if corsOrigin != "" {
+				opts = append(opts, server.WithCORSOrigin(corsOrigin))
+			}
+			return serveAPI(cmd.Context(), repo, provider, addr, opts)
+		},
+	}
+	cmd.Flags().StringVar(&addr, "addr", "127.0.0.1:8080", "listen address")
+	cmd.Flags().StringVar(&corsOrigin, "cors-origin", "", "allowed CORS origin (e.g. http://localhost:3000)")
+	cmd.Flags().BoolVar(&noExtract, "no-extract", false, "disable the extraction endpoint")
+	return cmd
+}
+
+func serveAPI(ctx context.Context, repo storage.Repository, provider llm.Provider, addr string, opts []server.Option) error {
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           server.New(repo, provider, opts...),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		progressf("Listening on http://%s\n", addr)
+		errCh <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	progressf("\nShutting down...\n")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to shut down server: %w", err)
+	}
+	return nil
+}
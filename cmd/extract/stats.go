@@ -0,0 +1,289 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+func newStatsCmd(a *app) *cobra.Command {
+	var intervalName string
+	var periods, top int
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show learning statistics: new expressions and occurrences over time, categories, frequent expressions",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			interval, err := storage.ParseInterval(intervalName)
+			if err != nil {
+				return err
+			}
+			if periods < 1 {
+				return fmt.Errorf("--periods must be at least 1")
+			}
+			repo, err := a.repository()
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			now := time.Now()
+			rng := storage.PeriodRange{
+				Interval: interval,
+				Since:    interval.Add(interval.Truncate(now), 1-periods),
+				Until:    now,
+			}
+			r := &statsResult{
+				Interval:        string(interval),
+				TopSince:        storage.IntervalMonth.Truncate(now),
+				Categories:      []categoryCountResult{},
+				TopExpressions:  []frequentExpressionResult{},
+				MissingMeanings: []expressionResult{},
+			}
+
+			totals, err := repo.GetStatsTotals(ctx)
+			if err != nil {
+				return err
+			}
+			r.Totals = statsTotals(*totals)
+
+			newCounts, err := repo.CountNewExpressions(ctx, rng)
+			if err != nil {
+				return err
+			}
+			occCounts, err := repo.CountOccurrences(ctx, rng)
+			if err != nil {
+				return err
+			}
+			r.NewExpressions = newPeriodCountResults(newCounts)
+			r.Occurrences = newPeriodCountResults(occCounts)
+
+			categories, err := repo.CountByCategory(ctx)
+			if err != nil {
+				return err
+			}
+			for _, c := range categories {
+				r.Categories = append(r.Categories, categoryCountResult{Category: c.Name, Expressions: c.Count})
+			}
+
+			frequent, err := repo.GetFrequentExpressions(ctx, r.TopSince, top)
+			if err != nil {
+				return err
+			}
+			for _, f := range frequent {
+				r.TopExpressions = append(r.TopExpressions, frequentExpressionResult{
+					expressionResult:  newExpressionResult(f.Expression),
+					PeriodOccurrences: f.Count,
+				})
+			}
+
+			missing, err := repo.GetExpressionsWithoutMeaning(ctx, top)
+			if err != nil {
+				return err
+			}
+			for _, expr := range missing {
+				r.MissingMeanings = append(r.MissingMeanings, newExpressionResult(expr))
+			}
+
+			return a.emit(r)
+		},
+	}
+	cmd.Flags().StringVar(&intervalName, "interval", string(storage.IntervalWeek), "time bucket: day, week or month")
+	cmd.Flags().IntVar(&periods, "periods", 12, "number of periods to show (including the current one)")
+	cmd.Flags().IntVar(&top, "top", 10, "number of frequent expressions and expressions without meaning to list")
+	intervals := make([]string, len(storage.Intervals))
+	for i, interval := range storage.Intervals {
+		intervals[i] = string(interval)
+	}
+	cmd.RegisterFlagCompletionFunc("interval", cobra.FixedCompletions(intervals, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+// statsTotals はデータベース全体の件数
+type statsTotals struct {
+	Expressions    int `json:"expressions"`
+	Words          int `json:"words"`
+	Phrases        int `json:"phrases"`
+	Occurrences    int `json:"occurrences"`
+	MissingMeaning int `json:"missing_meaning"`
+	ManuallyEdited int `json:"manually_edited"`
+}
+
+// periodCountResult は1つの期間の件数（startは期間の開始日）
+type periodCountResult struct {
+	Start string `json:"start"`
+	Count int    `json:"count"`
+}
+
+func newPeriodCountResults(counts []storage.PeriodCount) []periodCountResult {
+	results := make([]periodCountResult, 0, len(counts))
+	for _, c := range counts {
+		results = append(results, periodCountResult{Start: c.Start.Format("2006-01-02"), Count: c.Count})
+	}
+	return results
+}
+
+// categoryCountResult はカテゴリごとの表現数
+type categoryCountResult struct {
+	Category    string `json:"category"`
+	Expressions int    `json:"expressions"`
+}
+
+// frequentExpressionResult は期間内の出現回数付きの表現
+type frequentExpressionResult struct {
+	expressionResult
+	PeriodOccurrences int `json:"period_occurrences"`
+}
+
+// statsResult は学習の統計（stats）
+type statsResult struct {
+	Interval        string                     `json:"interval"`
+	Totals          statsTotals                `json:"totals"`
+	NewExpressions  []periodCountResult        `json:"new_expressions"`
+	Occurrences     []periodCountResult        `json:"occurrences"`
+	Categories      []categoryCountResult      `json:"categories"`
+	TopSince        time.Time                  `json:"top_since"` // TopExpressionsを数えた期間の開始（今月1日）
+	TopExpressions  []frequentExpressionResult `json:"top_expressions"`
+	MissingMeanings []expressionResult         `json:"missing_meanings"`
+}
+
+// intervalLabels は推移の見出し
+var intervalLabels = map[string]string{
+	string(storage.IntervalDay):   "日ごと",
+	string(storage.IntervalWeek):  "週ごと",
+	string(storage.IntervalMonth): "月ごと",
+}
+
+func (r *statsResult) writeText(w io.Writer) {
+	t := r.Totals
+	fmt.Fprintf(w, "表現: %d（単語 %d / 熟語 %d）  出現: %d  意味なし: %d  手動編集: %d\n",
+		t.Expressions, t.Words, t.Phrases, t.Occurrences, t.MissingMeaning, t.ManuallyEdited)
+
+	if n := len(r.NewExpressions); n > 0 {
+		fmt.Fprintf(w, "\n%sの推移（%s 〜 %s）\n", intervalLabels[r.Interval], r.NewExpressions[0].Start, r.NewExpressions[n-1].Start)
+		// 全角の見出しはtabwriterでは揃わないので空白で揃える
+		fmt.Fprintf(w, "  新規表現  %s  計 %d\n", sparkline(r.NewExpressions), sumCounts(r.NewExpressions))
+		fmt.Fprintf(w, "  出現      %s  計 %d\n", sparkline(r.Occurrences), sumCounts(r.Occurrences))
+		fmt.Fprintln(w)
+		writeRows(w, r.periodRows())
+	}
+
+	if len(r.Categories) > 0 {
+		fmt.Fprintln(w, "\nカテゴリ")
+		rows := [][]string{{"CATEGORY", "EXPRESSIONS"}}
+		for _, c := range r.Categories {
+			rows = append(rows, []string{categoryName(c.Category), fmt.Sprint(c.Expressions)})
+		}
+		writeRows(w, rows)
+	}
+
+	fmt.Fprintf(w, "\n今月よく出た表現（%s 〜）\n", r.TopSince.Format("2006-01-02"))
+	if len(r.TopExpressions) == 0 {
+		fmt.Fprintln(w, "  なし")
+	} else {
+		rows := [][]string{{"EXPRESSION", "OCCURRENCES", "PRIORITY", "MEANING"}}
+		for _, e := range r.TopExpressions {
+			rows = append(rows, []string{e.Expression, fmt.Sprint(e.PeriodOccurrences), fmt.Sprint(e.Priority), e.Meaning})
+		}
+		writeRows(w, rows)
+	}
+
+	fmt.Fprintf(w, "\n意味が未登録の表現（全%d個）\n", t.MissingMeaning)
+	if len(r.MissingMeanings) == 0 {
+		fmt.Fprintln(w, "  なし")
+	} else {
+		rows := [][]string{{"ID", "EXPRESSION", "TYPE", "PRIORITY", "OCCURRENCES"}}
+		for _, e := range r.MissingMeanings {
+			rows = append(rows, []string{fmt.Sprint(e.ID), e.Expression, e.Type, fmt.Sprint(e.Priority), fmt.Sprint(e.OccurrenceCount)})
+		}
+		writeRows(w, rows)
+	}
+}
+
+// periodRows は期間ごとの新規表現数と出現回数の表
+func (r *statsResult) periodRows() [][]string {
+	rows := [][]string{{"PERIOD", "NEW", "OCCURRENCES"}}
+	for i, c := range r.NewExpressions {
+		occurrences := 0
+		if i < len(r.Occurrences) {
+			occurrences = r.Occurrences[i].Count
+		}
+		rows = append(rows, []string{c.Start, fmt.Sprint(c.Count), fmt.Sprint(occurrences)})
+	}
+	return rows
+}
+
+// tableRows はすべての集計を SECTION / KEY / VALUE の1つの表にする
+func (r *statsResult) tableRows() [][]string {
+	t := r.Totals
+	rows := [][]string{
+		{"SECTION", "KEY", "VALUE"},
+		{"totals", "expressions", fmt.Sprint(t.Expressions)},
+		{"totals", "words", fmt.Sprint(t.Words)},
+		{"totals", "phrases", fmt.Sprint(t.Phrases)},
+		{"totals", "occurrences", fmt.Sprint(t.Occurrences)},
+		{"totals", "missing_meaning", fmt.Sprint(t.MissingMeaning)},
+		{"totals", "manually_edited", fmt.Sprint(t.ManuallyEdited)},
+	}
+	for _, c := range r.NewExpressions {
+		rows = append(rows, []string{"new_expressions", c.Start, fmt.Sprint(c.Count)})
+	}
+	for _, c := range r.Occurrences {
+		rows = append(rows, []string{"occurrences", c.Start, fmt.Sprint(c.Count)})
+	}
+	for _, c := range r.Categories {
+		rows = append(rows, []string{"categories", categoryName(c.Category), fmt.Sprint(c.Expressions)})
+	}
+	for _, e := range r.TopExpressions {
+		rows = append(rows, []string{"top_expressions", e.Expression, fmt.Sprint(e.PeriodOccurrences)})
+	}
+	for _, e := range r.MissingMeanings {
+		rows = append(rows, []string{"missing_meanings", e.Expression, fmt.Sprint(e.Priority)})
+	}
+	return rows
+}
+
+// categoryName は表示用のカテゴリ名（未設定は "(none)"）
+func categoryName(category string) string {
+	if category == "" {
+		return "(none)"
+	}
+	return category
+}
+
+// sparkLevels はスパークラインの文字（低い順）
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline は件数の推移を1行のグラフにする（0件は最も低い文字、1件以上は2段目以上）
+func sparkline(counts []periodCountResult) string {
+	max := 0
+	for _, c := range counts {
+		if c.Count > max {
+			max = c.Count
+		}
+	}
+
+	var b strings.Builder
+	for _, c := range counts {
+		level := 0
+		if max > 0 && c.Count > 0 {
+			level = 1 + c.Count*(len(sparkLevels)-2)/max
+		}
+		b.WriteRune(sparkLevels[level])
+	}
+	return b.String()
+}
+
+// sumCounts は件数の合計
+func sumCounts(counts []periodCountResult) int {
+	total := 0
+	for _, c := range counts {
+		total += c.Count
+	}
+	return total
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+func newTagCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tag",
+		Short: "Show tags and the number of expressions with each tag",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := a.repository()
+			if err != nil {
+				return err
+			}
+			tags, err := repo.ListTags(cmd.Context())
+			if err != nil {
+				return err
+			}
+			r := &tagListResult{Tags: make([]tagCountResult, 0, len(tags))}
+			for _, t := range tags {
+				r.Tags = append(r.Tags, tagCountResult{Name: t.Name, Expressions: t.Count})
+			}
+			return a.emit(r)
+		},
+	}
+	cmd.AddCommand(newTagAddCmd(a), newTagRemoveCmd(a), newTagRuleCmd(a))
+	return cmd
+}
+
+func newTagAddCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "add <expression> <tag>...",
+		Short: "Tag an expression (quote multi-word expressions)",
+		Example: `  extract tag add "circle back" project-x
+  extract tag add deprecate interview,infra`,
+		Args: cobra.MinimumNArgs(2),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return a.completeExpressions(cmd, args, toComplete)
+			}
+			return a.completeTags(cmd, args, toComplete)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			tags, err := models.NormalizeTags(args[1:])
+			if err != nil {
+				return err
+			}
+			repo, err := a.repository()
+			if err != nil {
+				return err
+			}
+			expr, err := lookupExpression(ctx, repo, args[0])
+			if err != nil {
+				return err
+			}
+
+			if err := repo.AddTags(ctx, expr.ID, models.TagSourceManual, tags...); err != nil {
+				return fmt.Errorf("failed to add tags: %w", err)
+			}
+			updated, err := repo.GetExpressionByID(ctx, expr.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get expression: %w", err)
+			}
+			return a.emit(&changeResult{Action: "updated", Expression: newExpressionResult(updated)})
+		},
+	}
+}
+
+func newTagRemoveCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <expression> <tag>...",
+		Short: "Remove tags from an expression (quote multi-word expressions)",
+		Args:  cobra.MinimumNArgs(2),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return a.completeExpressions(cmd, args, toComplete)
+			}
+			return a.completeTags(cmd, args, toComplete)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			tags, err := models.NormalizeTags(args[1:])
+			if err != nil {
+				return err
+			}
+			repo, err := a.repository()
+			if err != nil {
+				return err
+			}
+			expr, err := lookupExpression(ctx, repo, args[0])
+			if err != nil {
+				return err
+			}
+
+			removed, err := repo.RemoveTags(ctx, expr.ID, tags...)
+			if err != nil {
+				return fmt.Errorf("failed to remove tags: %w", err)
+			}
+			if removed == 0 {
+				return fmt.Errorf("'%s' has none of the tags: %s", expr.Expression, strings.Join(tags, ", "))
+			}
+			updated, err := repo.GetExpressionByID(ctx, expr.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get expression: %w", err)
+			}
+			return a.emit(&changeResult{Action: "updated", Expression: newExpressionResult(updated)})
+		},
+	}
+}
+
+func newTagRuleCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rule",
+		Short: "Show rules that tag expressions by meeting name during extraction",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return showTagRules(cmd, a)
+		},
+	}
+	cmd.AddCommand(newTagRuleAddCmd(a), newTagRuleRemoveCmd(a))
+	return cmd
+}
+
+func newTagRuleAddCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "add <tag> <meeting-pattern>",
+		Short: "Tag expressions found in meetings whose name matches the pattern (glob, case-insensitive)",
+		Example: `  extract tag rule add project-x "project x*"
+  extract tag rule add interview "*interview*"`,
+		Args: cobra.ExactArgs(2),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return a.completeTags(cmd, args, toComplete)
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := a.repository()
+			if err != nil {
+				return err
+			}
+			rule := models.TagRule{Tag: models.NormalizeTag(args[0]), Pattern: args[1]}
+			if err := repo.SaveTagRule(cmd.Context(), rule); err != nil {
+				return err
+			}
+			progressf("✓ 会議名が '%s' に一致する会議の表現に '%s' をつけます（次の抽出から）\n", rule.Pattern, rule.Tag)
+			return showTagRules(cmd, a)
+		},
+	}
+}
+
+func newTagRuleRemoveCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <tag> <meeting-pattern>",
+		Short: "Remove a tag rule (tags already added stay on the expressions)",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := a.repository()
+			if err != nil {
+				return err
+			}
+			rule := models.TagRule{Tag: models.NormalizeTag(args[0]), Pattern: args[1]}
+			if err := repo.DeleteTagRule(cmd.Context(), rule); err != nil {
+				return err
+			}
+			progressf("✓ タグのルールを削除しました\n")
+			return showTagRules(cmd, a)
+		},
+	}
+}
+
+// showTagRules はタグのルールを出力
+func showTagRules(cmd *cobra.Command, a *app) error {
+	repo, err := a.repository()
+	if err != nil {
+		return err
+	}
+	rules, err := repo.ListTagRules(cmd.Context())
+	if err != nil {
+		return err
+	}
+	r := &tagRuleListResult{Rules: make([]tagRuleResult, 0, len(rules))}
+	for _, rule := range rules {
+		r.Rules = append(r.Rules, tagRuleResult{Tag: rule.Tag, Pattern: rule.Pattern})
+	}
+	return a.emit(r)
+}
+
+// tagCountResult はタグと、そのタグがついた表現の数
+type tagCountResult struct {
+	Name        string `json:"name"`
+	Expressions int    `json:"expressions"`
+}
+
+// tagListResult はタグの一覧（tag）
+type tagListResult struct {
+	Tags []tagCountResult `json:"tags"`
+}
+
+func (r *tagListResult) writeText(w io.Writer) {
+	if len(r.Tags) == 0 {
+		fmt.Fprintln(w, "No tags yet. Add one with 'tag add <expression> <tag>' or 'extract --tag'.")
+		return
+	}
+	for _, t := range r.Tags {
+		fmt.Fprintf(w, "- %s (%d)\n", t.Name, t.Expressions)
+	}
+}
+
+func (r *tagListResult) tableRows() [][]string {
+	rows := [][]string{{"TAG", "EXPRESSIONS"}}
+	for _, t := range r.Tags {
+		rows = append(rows, []string{t.Name, fmt.Sprint(t.Expressions)})
+	}
+	return rows
+}
+
+// tagRuleResult は会議名から自動でタグをつけるルール
+type tagRuleResult struct {
+	Tag     string `json:"tag"`
+	Pattern string `json:"pattern"`
+}
+
+// tagRuleListResult はタグのルールの一覧（tag rule）
+type tagRuleListResult struct {
+	Rules []tagRuleResult `json:"rules"`
+}
+
+func (r *tagRuleListResult) writeText(w io.Writer) {
+	if len(r.Rules) == 0 {
+		fmt.Fprintln(w, "No tag rules. Add one with 'tag rule add <tag> <meeting-pattern>'.")
+		return
+	}
+	fmt.Fprintln(w, "タグのルール（会議名がパターンに一致すると、抽出時にその会議の表現へタグをつけます）")
+	for _, rule := range r.Rules {
+		fmt.Fprintf(w, "  - %s: %s\n", rule.Tag, rule.Pattern)
+	}
+}
+
+func (r *tagRuleListResult) tableRows() [][]string {
+	rows := [][]string{{"TAG", "PATTERN"}}
+	for _, rule := range r.Rules {
+		rows = append(rows, []string{rule.Tag, rule.Pattern})
+	}
+	return rows
+}
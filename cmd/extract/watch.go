@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/llm"
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/service"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+	"github.com/mamyudapao/learn-by-transcript/internal/watcher"
+)
+
+func newWatchCmd(a *app) *cobra.Command {
+	var archiveDir string
+	var tags []string
+	var interval, settle time.Duration
+	var once bool
+	cmd := &cobra.Command{
+		Use:   "watch <dir>",
+		Short: "Process new .txt/.vtt/.srt files in a folder once each and archive them",
+		Args:  cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return nil, cobra.ShellCompDirectiveFilterDirs
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := args[0]
+			if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+				return fmt.Errorf("not a directory: %s", dir)
+			}
+			tags, err := models.NormalizeTags(tags)
+			if err != nil {
+				return err
+			}
+			if settle == 0 {
+				settle = -1 // Optionsのゼロ値はデフォルト値なので、0指定は「待たない」に変換
+			}
+
+			repo, err := a.repository()
+			if err != nil {
+				return err
+			}
+			provider, err := a.llmProvider()
+			if err != nil {
+				return err
+			}
+			return watchFolder(cmd.Context(), a, provider, repo, dir, watcher.Options{
+				ArchiveDir: archiveDir,
+				Interval:   interval,
+				Settle:     settle,
+				Logger:     a.logger,
+			}, tags, once)
+		},
+	}
+	cmd.Flags().StringVar(&archiveDir, "archive", "", "move processed files here (default: <dir>/"+watcher.DefaultArchiveDirName+")")
+	cmd.Flags().DurationVar(&interval, "interval", watcher.DefaultInterval, "polling interval")
+	cmd.Flags().DurationVar(&settle, "settle", watcher.DefaultSettle, "wait until a file has not changed for this long")
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "tag every expression found in the transcripts (repeatable, in addition to tag rules)")
+	cmd.Flags().BoolVar(&once, "once", false, "scan the folder once and exit")
+	a.registerCompletions(cmd)
+	return cmd
+}
+
+func watchFolder(ctx context.Context, a *app, provider llm.Provider, repo storage.Repository, dir string, opts watcher.Options, tags []string, once bool) error {
+	// プロフィール・カテゴリ・タグのルールは監視の開始時に読み込む（変更は再起動後に反映）
+	processorOpts, err := a.processorOptions(ctx, repo)
+	if err != nil {
+		return err
+	}
+	processorOpts = append(processorOpts, service.WithTags(tags))
+	processor := service.NewTranscriptProcessor(provider, repo, processorOpts...)
+	w := watcher.New(dir, repo, processor, opts)
+
+	if once {
+		result, err := w.Scan(ctx)
+		if err != nil {
+			return err
+		}
+		return a.emit(&scanResult{Processed: result.Processed, Duplicates: result.Duplicates, Failed: result.Failed})
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	progressf("\nWatching %s every %s (Ctrl+C to stop)\n", dir, opts.Interval.Round(time.Second))
+	return w.Run(ctx)
+}
+
+// scanResult はフォルダを1回確認した結果（watch --once）
+type scanResult struct {
+	Processed  int `json:"processed"`
+	Duplicates int `json:"duplicates"` // 処理済みの内容だったファイル
+	Failed     int `json:"failed"`
+}
+
+func (r *scanResult) writeText(w io.Writer) {
+	fmt.Fprintf(w, "\n処理: %d件, 処理済みの内容: %d件, 失敗: %d件\n", r.Processed, r.Duplicates, r.Failed)
+}
+
+func (r *scanResult) tableRows() [][]string {
+	return [][]string{{"PROCESSED", "DUPLICATES", "FAILED"}, {fmt.Sprint(r.Processed), fmt.Sprint(r.Duplicates), fmt.Sprint(r.Failed)}}
+}
@@ -5,48 +5,84 @@ import (
 	"os"
 
 	"github.com/mamyudapao/learn-by-transcript/internal/llm"
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
 )
 
 // Config はアプリケーション設定
 type Config struct {
-	LLM    llm.Config
-	DBPath string
+	LLM             llm.Config
+	DBPath          string
+	ReviewScheduler string // 復習スケジューラ（"sm2" / "fsrs"）
+	Notion          NotionConfig
+	Profile         models.LearnerProfile // 環境変数で指定した学習者のプロフィール（空でない項目がデータベースの値より優先）
+}
+
+// NotionConfig はNotion同期の設定
+type NotionConfig struct {
+	APIKey     string
+	DatabaseID string
+	BaseURL    string // 空ならNotion API（テスト用に差し替え可能）
 }
 
 // Load は環境変数から設定を読み込む
 func Load() (*Config, error) {
-	llmType := getEnvOrDefault("LLM_PROVIDER", "anthropic")
+	cfg, err := LoadBasic()
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.ValidateLLM(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
 
-	llmCfg := llm.Config{
-		Type:      llmType,
-		APIKey:    os.Getenv("ANTHROPIC_API_KEY"),
-		ProjectID: os.Getenv("VERTEX_PROJECT_ID"),
-		Location:  getEnvOrDefault("VERTEX_LOCATION", "us-central1"),
-		Model:     getEnvOrDefault("MODEL_NAME", "claude-sonnet-4-20250514"),
+// LoadBasic はLLM設定を検証せずに設定を読み込む（export, list, reviewなどLLM不要なコマンド用）
+func LoadBasic() (*Config, error) {
+	cfg := &Config{
+		LLM: llm.Config{
+			Type:      getEnvOrDefault("LLM_PROVIDER", "anthropic"),
+			APIKey:    os.Getenv("ANTHROPIC_API_KEY"),
+			ProjectID: os.Getenv("VERTEX_PROJECT_ID"),
+			Location:  getEnvOrDefault("VERTEX_LOCATION", "us-central1"),
+			Model:     getEnvOrDefault("MODEL_NAME", "claude-sonnet-4-20250514"),
+		},
+		DBPath:          getEnvOrDefault("DB_PATH", "./expressions.db"),
+		ReviewScheduler: getEnvOrDefault("REVIEW_SCHEDULER", "sm2"),
+		Notion:          loadNotionConfig(),
+		Profile:         loadProfile(),
 	}
+	return cfg, nil
+}
 
-	// 基本的なバリデーション
-	if llmType == "anthropic" && llmCfg.APIKey == "" {
-		return nil, fmt.Errorf("ANTHROPIC_API_KEY is required when using anthropic provider")
+// ValidateLLM はLLMプロバイダーに必要な設定が揃っているか確認
+func (c *Config) ValidateLLM() error {
+	if c.LLM.Type == "anthropic" && c.LLM.APIKey == "" {
+		return fmt.Errorf("ANTHROPIC_API_KEY is required when using anthropic provider")
 	}
-	if llmType == "vertexai" && llmCfg.ProjectID == "" {
-		return nil, fmt.Errorf("VERTEX_PROJECT_ID is required when using vertexai provider")
+	if c.LLM.Type == "vertexai" && c.LLM.ProjectID == "" {
+		return fmt.Errorf("VERTEX_PROJECT_ID is required when using vertexai provider")
 	}
+	return nil
+}
 
-	cfg := &Config{
-		LLM:    llmCfg,
-		DBPath: getEnvOrDefault("DB_PATH", "./expressions.db"),
+// loadNotionConfig は環境変数からNotion同期の設定を読み込む（未設定でもエラーにしない）
+func loadNotionConfig() NotionConfig {
+	return NotionConfig{
+		APIKey:     os.Getenv("NOTION_API_KEY"),
+		DatabaseID: os.Getenv("NOTION_DATABASE_ID"),
+		BaseURL:    os.Getenv("NOTION_BASE_URL"),
 	}
-
-	return cfg, nil
 }
 
-// LoadBasic はLLM設定なしで基本設定のみを読み込む（export, listコマンド用）
-func LoadBasic() (*Config, error) {
-	cfg := &Config{
-		DBPath: getEnvOrDefault("DB_PATH", "./expressions.db"),
+// loadProfile は環境変数から学習者のプロフィールを読み込む（未設定の項目は空）
+func loadProfile() models.LearnerProfile {
+	return models.LearnerProfile{
+		Role:           os.Getenv("LEARNER_ROLE"),
+		Domain:         os.Getenv("LEARNER_DOMAIN"),
+		Level:          os.Getenv("LEARNER_LEVEL"),
+		Goals:          os.Getenv("LEARNER_GOALS"),
+		NativeLanguage: os.Getenv("LEARNER_NATIVE_LANGUAGE"),
 	}
-	return cfg, nil
 }
 
 // getEnvOrDefault は環境変数を取得、なければデフォルト値を返す
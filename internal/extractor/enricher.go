@@ -0,0 +1,166 @@
+package extractor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/llm"
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/pkg/prompt"
+)
+
+// 生成した使い方に残す件数の上限（LLMが多く返した場合は先頭から）
+const (
+	maxExamples     = 3
+	maxRelatedWords = 5
+)
+
+// Enricher は表現の例文・使用上の注意・関連語をLLMで生成する
+type Enricher struct {
+	llmProvider llm.Provider
+	logger      *slog.Logger
+	profile     models.LearnerProfile
+}
+
+// NewEnricher は新しいEnricherを作成
+func NewEnricher(provider llm.Provider, opts ...Option) *Enricher {
+	o := newOptions(opts)
+	return &Enricher{
+		llmProvider: provider,
+		logger:      o.logger,
+		profile:     o.profile,
+	}
+}
+
+// Enrich は表現の使い方を生成（expr.Senses・expr.Meaning・expr.Contextを例文の手がかりにする）
+// 戻り値はLLMが返した表現の使い方（ExpressionIDはexpr.ID、expressionsの順）
+func (e *Enricher) Enrich(ctx context.Context, expressions []*models.Expression) ([]*models.Usage, error) {
+	return e.EnrichWithProgress(ctx, expressions, nil)
+}
+
+// EnrichWithProgress はEnrichと同じ処理で、最初のバッチの前（done=0）と各バッチの後にonBatchを呼ぶ
+func (e *Enricher) EnrichWithProgress(ctx context.Context, expressions []*models.Expression, onBatch BatchProgressFunc) ([]*models.Usage, error) {
+	if len(expressions) == 0 {
+		return nil, nil
+	}
+	if onBatch == nil {
+		onBatch = func(done, total int) {}
+	}
+
+	// 1表現あたりの出力が長いため、優先度判定よりバッチを小さくする
+	const batchSize = 10
+	batches := (len(expressions) + batchSize - 1) / batchSize
+	onBatch(0, batches)
+
+	var usages []*models.Usage
+	for i := 0; i < len(expressions); i += batchSize {
+		end := i + batchSize
+		if end > len(expressions) {
+			end = len(expressions)
+		}
+		batch := expressions[i:end]
+
+		response, err := e.llmProvider.Generate(ctx, prompt.EnrichExpressionsPrompt(e.profile, batch))
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate response for batch %d: %w", i/batchSize+1, err)
+		}
+		usageMap := parseUsageResponse(response, e.logger)
+
+		matched := 0
+		for _, expr := range batch {
+			data, ok := usageMap[expr.Expression]
+			if !ok {
+				continue
+			}
+			usage := data.usage(expr.ID)
+			if usage.Empty() {
+				continue
+			}
+			usages = append(usages, usage)
+			matched++
+		}
+		if matched < len(batch) {
+			e.logger.Warn("LLM did not generate usage for some expressions", "batch", i/batchSize+1, "count", len(batch)-matched)
+		}
+		onBatch(i/batchSize+1, batches)
+	}
+
+	e.logger.Info("enriched expressions", "generated", len(usages), "total", len(expressions))
+	return usages, nil
+}
+
+// UsageJSON はLLMからのレスポンスのJSON形式
+type UsageJSON struct {
+	Expression   string           `json:"expression"`
+	Examples     []models.Example `json:"examples"`
+	Register     string           `json:"register"`
+	Note         string           `json:"note"`
+	Collocations []string         `json:"collocations"`
+	Synonyms     []string         `json:"synonyms"`
+	Antonyms     []string         `json:"antonyms"`
+}
+
+// usage はレスポンスを保存用の使い方にする（空の項目・重複を除き、件数を制限）
+func (d UsageJSON) usage(expressionID int) *models.Usage {
+	usage := &models.Usage{
+		ExpressionID: expressionID,
+		Register:     models.NormalizeRegister(d.Register),
+		Note:         strings.TrimSpace(d.Note),
+		Collocations: cleanWords(d.Collocations),
+		Synonyms:     cleanWords(d.Synonyms),
+		Antonyms:     cleanWords(d.Antonyms),
+	}
+	for _, ex := range d.Examples {
+		ex.Sentence = strings.TrimSpace(ex.Sentence)
+		ex.Translation = strings.TrimSpace(ex.Translation)
+		if ex.Sentence == "" || len(usage.Examples) == maxExamples {
+			continue
+		}
+		usage.Examples = append(usage.Examples, ex)
+	}
+	return usage
+}
+
+// cleanWords は空の語句と重複（大文字小文字を区別しない）を除き、最大maxRelatedWords件にする
+func cleanWords(words []string) []string {
+	seen := make(map[string]bool)
+	var cleaned []string
+	for _, w := range words {
+		w = strings.TrimSpace(w)
+		key := strings.ToLower(w)
+		if w == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, w)
+		if len(cleaned) == maxRelatedWords {
+			break
+		}
+	}
+	return cleaned
+}
+
+// parseUsageResponse はLLMのレスポンスをパース（パースできない行は警告して読み飛ばす）
+func parseUsageResponse(response string, logger *slog.Logger) map[string]UsageJSON {
+	result := make(map[string]UsageJSON)
+	for _, line := range strings.Split(strings.TrimSpace(response), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "```") {
+			continue
+		}
+
+		var data UsageJSON
+		if err := json.Unmarshal([]byte(line), &data); err != nil {
+			logger.Warn("failed to parse usage line", "line", line, "error", err)
+			continue
+		}
+		if data.Expression == "" {
+			continue
+		}
+		result[data.Expression] = data
+	}
+	return result
+}
@@ -0,0 +1,48 @@
+package extractor
+
+import (
+	"log/slog"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+// Option はPhraseExtractor・Prioritizer・Enricherの設定オプション
+type Option func(*options)
+
+type options struct {
+	logger     *slog.Logger
+	profile    models.LearnerProfile
+	categories models.Taxonomy
+}
+
+// WithLogger はLLMのレスポンスのパース失敗などを記録するロガーを設定（デフォルトは記録しない）
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithProfile はプロンプトに埋め込む学習者のプロフィールを設定（デフォルトはmodels.DefaultLearnerProfile）
+func WithProfile(profile models.LearnerProfile) Option {
+	return func(o *options) {
+		o.profile = profile
+	}
+}
+
+// WithCategories は優先度判定で選ばせるカテゴリの分類体系を設定（空ならmodels.DefaultCategories）
+func WithCategories(taxonomy models.Taxonomy) Option {
+	return func(o *options) {
+		o.categories = taxonomy
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{logger: slog.New(slog.DiscardHandler)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if len(o.categories) == 0 {
+		o.categories = models.DefaultCategories()
+	}
+	return o
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"strings"
 
 	"github.com/mamyudapao/learn-by-transcript/internal/llm"
@@ -14,19 +15,24 @@ import (
 // PhraseExtractor は熟語・慣用表現を抽出する
 type PhraseExtractor struct {
 	llmProvider llm.Provider
+	logger      *slog.Logger
+	profile     models.LearnerProfile
 }
 
 // NewPhraseExtractor は新しいPhraseExtractorを作成
-func NewPhraseExtractor(provider llm.Provider) *PhraseExtractor {
+func NewPhraseExtractor(provider llm.Provider, opts ...Option) *PhraseExtractor {
+	o := newOptions(opts)
 	return &PhraseExtractor{
 		llmProvider: provider,
+		logger:      o.logger,
+		profile:     o.profile,
 	}
 }
 
 // Extract はテキストから熟語・慣用表現を抽出
 func (e *PhraseExtractor) Extract(ctx context.Context, text string) ([]*models.Expression, error) {
 	// プロンプト生成
-	promptText := prompt.ExtractPhrasesPrompt(text)
+	promptText := prompt.ExtractPhrasesPrompt(e.profile, text)
 
 	// LLM呼び出し
 	response, err := e.llmProvider.Generate(ctx, promptText)
@@ -35,7 +41,7 @@ func (e *PhraseExtractor) Extract(ctx context.Context, text string) ([]*models.E
 	}
 
 	// レスポンスをパース
-	phrases, err := parsePhraseResponse(response)
+	phrases, err := parsePhraseResponse(response, e.logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
@@ -50,7 +56,7 @@ type PhraseJSON struct {
 }
 
 // parsePhraseResponse はLLMのレスポンスをパース
-func parsePhraseResponse(response string) ([]*models.Expression, error) {
+func parsePhraseResponse(response string, logger *slog.Logger) ([]*models.Expression, error) {
 	lines := strings.Split(strings.TrimSpace(response), "\n")
 	expressions := make([]*models.Expression, 0, len(lines))
 
@@ -68,7 +74,7 @@ func parsePhraseResponse(response string) ([]*models.Expression, error) {
 		var phraseData PhraseJSON
 		if err := json.Unmarshal([]byte(line), &phraseData); err != nil {
 			// パースエラーは警告して続行
-			fmt.Printf("Warning: failed to parse line: %s (error: %v)\n", line, err)
+			logger.Warn("failed to parse phrase line", "line", line, "error", err)
 			continue
 		}
 
@@ -0,0 +1,25 @@
+package extractor
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestParsePhraseResponseLogsInvalidLines(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	response := "```json\n{\"phrase\": \"circle back\", \"context\": \"Let's circle back\"}\nnot json\n```"
+	phrases, err := parsePhraseResponse(response, logger)
+	if err != nil {
+		t.Fatalf("parsePhraseResponse: %v", err)
+	}
+	if len(phrases) != 1 || phrases[0].Expression != "circle back" {
+		t.Errorf("unexpected phrases: %+v", phrases)
+	}
+	if !strings.Contains(buf.String(), "level=WARN") || !strings.Contains(buf.String(), `line="not json"`) {
+		t.Errorf("invalid line not logged: %s", buf.String())
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"strings"
 
 	"github.com/mamyudapao/learn-by-transcript/internal/llm"
@@ -11,26 +12,47 @@ import (
 	"github.com/mamyudapao/learn-by-transcript/pkg/prompt"
 )
 
-// Prioritizer は表現に優先度とカテゴリを付ける
+// Prioritizer は表現に優先度・カテゴリ・文脈での語義を付ける
 type Prioritizer struct {
 	llmProvider llm.Provider
+	logger      *slog.Logger
+	profile     models.LearnerProfile
+	categories  models.Taxonomy
 }
 
 // NewPrioritizer は新しいPrioritizerを作成
-func NewPrioritizer(provider llm.Provider) *Prioritizer {
+func NewPrioritizer(provider llm.Provider, opts ...Option) *Prioritizer {
+	o := newOptions(opts)
 	return &Prioritizer{
 		llmProvider: provider,
+		logger:      o.logger,
+		profile:     o.profile,
+		categories:  o.categories,
 	}
 }
 
-// Prioritize は表現に優先度・カテゴリ・意味を付ける
+// BatchProgressFunc はバッチの進捗（処理済みバッチ数／全バッチ数）を受け取る関数
+type BatchProgressFunc func(done, total int)
+
+// Prioritize は表現に優先度・カテゴリ・意味を付け、文脈での語義を判定する
+// expr.Sensesに登録済みの語義があれば、同じ意味の語義をexpr.Senseに選ぶ（なければIDが0の新しい語義）
 func (p *Prioritizer) Prioritize(ctx context.Context, expressions []*models.Expression, transcript string) error {
+	return p.PrioritizeWithProgress(ctx, expressions, transcript, nil)
+}
+
+// PrioritizeWithProgress はPrioritizeと同じ処理で、最初のバッチの前（done=0）と各バッチの後にonBatchを呼ぶ
+func (p *Prioritizer) PrioritizeWithProgress(ctx context.Context, expressions []*models.Expression, transcript string, onBatch BatchProgressFunc) error {
 	if len(expressions) == 0 {
 		return nil
 	}
+	if onBatch == nil {
+		onBatch = func(done, total int) {}
+	}
 
 	// バッチサイズ（一度に処理する表現数）
 	const batchSize = 50
+	batches := (len(expressions) + batchSize - 1) / batchSize
+	onBatch(0, batches)
 
 	// 全表現をマップ化（高速ルックアップ用）
 	exprMap := make(map[string]*models.Expression)
@@ -49,17 +71,10 @@ func (p *Prioritizer) Prioritize(ctx context.Context, expressions []*models.Expr
 		}
 		batch := expressions[i:end]
 
-		fmt.Printf("  バッチ %d/%d を処理中 (%d-%d件目)...\n",
-			i/batchSize+1, (len(expressions)+batchSize-1)/batchSize, i+1, end)
-
-		// バッチの表現リストを作成
-		exprList := make([]string, len(batch))
-		for j, expr := range batch {
-			exprList[j] = expr.Expression
-		}
+		p.logger.Debug("prioritizing batch", "batch", i/batchSize+1, "batches", batches, "from", i+1, "to", end)
 
 		// プロンプト生成
-		promptText := prompt.PrioritizeExpressionsPrompt(exprList, transcript)
+		promptText := prompt.PrioritizeExpressionsPrompt(p.profile, p.categories, batch, transcript)
 
 		// LLM呼び出し
 		response, err := p.llmProvider.Generate(ctx, promptText)
@@ -68,7 +83,7 @@ func (p *Prioritizer) Prioritize(ctx context.Context, expressions []*models.Expr
 		}
 
 		// レスポンスをパース
-		priorityMap, err := parsePriorityResponse(response)
+		priorityMap, err := parsePriorityResponse(response, p.logger)
 		if err != nil {
 			return fmt.Errorf("failed to parse response for batch %d: %w", i/batchSize+1, err)
 		}
@@ -79,41 +94,86 @@ func (p *Prioritizer) Prioritize(ctx context.Context, expressions []*models.Expr
 		for _, expr := range batch {
 			if data, ok := priorityMap[expr.Expression]; ok {
 				expr.Priority = data.Priority
-				expr.Category = data.Category
+				expr.Categories = p.resolveCategories(expr.Expression, data.categoryNames())
+				expr.Category = ""
 				expr.Meaning = data.Meaning
+				expr.Sense = p.resolveSense(expr, data)
 				batchMatched++
 			} else {
-				// デフォルト値
+				// デフォルト値（カテゴリは未分類）
 				expr.Priority = 3
-				expr.Category = string(models.CategoryBusiness)
+				expr.Category = ""
+				expr.Categories = nil
 				expr.Meaning = ""
+				expr.Sense = nil
 				batchUnmatched++
 			}
 		}
 
 		totalMatched += batchMatched
 		totalUnmatched += batchUnmatched
-		fmt.Printf("    マッチ: %d/%d\n", batchMatched, len(batch))
+		p.logger.Debug("prioritized batch", "batch", i/batchSize+1, "matched", batchMatched, "size", len(batch))
+		onBatch(i/batchSize+1, batches)
 	}
 
-	fmt.Printf("  全体マッチ: %d/%d expressions\n", totalMatched, len(expressions))
+	p.logger.Info("prioritized expressions", "matched", totalMatched, "total", len(expressions))
 	if totalUnmatched > 0 {
-		fmt.Printf("  警告: %d個の表現にデフォルト値を設定しました\n", totalUnmatched)
+		p.logger.Warn("LLM did not judge some expressions; using default priority without category", "count", totalUnmatched)
 	}
 
 	return nil
 }
 
+// resolveCategories はLLMが選んだカテゴリを分類体系で検証し、識別名に揃える（親カテゴリも追加）
+// 分類体系にないカテゴリは記録して捨てる
+func (p *Prioritizer) resolveCategories(expression string, names []string) []string {
+	resolved, unknown := p.categories.Resolve(names)
+	if len(unknown) > 0 {
+		p.logger.Warn("LLM returned unknown categories; ignoring them", "expression", expression, "categories", unknown)
+	}
+	return resolved
+}
+
+// resolveSense はLLMが判定した語義を返す（登録済みの語義の番号ならその語義、意味がなければnil）
+func (p *Prioritizer) resolveSense(expr *models.Expression, data PriorityJSON) *models.Sense {
+	if data.Sense > 0 {
+		if data.Sense <= len(expr.Senses) {
+			return expr.Senses[data.Sense-1]
+		}
+		p.logger.Warn("LLM returned an unknown sense number; treating it as a new sense", "expression", expr.Expression, "sense", data.Sense)
+	}
+	if data.Meaning == "" && data.Definition == "" {
+		return nil
+	}
+	return &models.Sense{
+		PartOfSpeech: models.NormalizePartOfSpeech(data.PartOfSpeech),
+		Gloss:        data.Meaning,
+		Definition:   data.Definition,
+	}
+}
+
 // PriorityJSON はLLMからのレスポンスのJSON形式
 type PriorityJSON struct {
-	Expression string `json:"expression"`
-	Meaning    string `json:"meaning"`
-	Priority   int    `json:"priority"`
-	Category   string `json:"category"`
+	Expression   string   `json:"expression"`
+	PartOfSpeech string   `json:"pos"`
+	Meaning      string   `json:"meaning"`
+	Definition   string   `json:"definition"`
+	Sense        int      `json:"sense"` // 登録済みの語義の番号（1始まり。0なら新しい語義）
+	Priority     int      `json:"priority"`
+	Categories   []string `json:"categories"`
+	Category     string   `json:"category"` // 1つだけ返された場合（以前の形式）
+}
+
+// categoryNames はLLMが選んだカテゴリ（関連の強い順）
+func (d PriorityJSON) categoryNames() []string {
+	if len(d.Categories) == 0 && d.Category != "" {
+		return []string{d.Category}
+	}
+	return d.Categories
 }
 
 // parsePriorityResponse はLLMのレスポンスをパース
-func parsePriorityResponse(response string) (map[string]PriorityJSON, error) {
+func parsePriorityResponse(response string, logger *slog.Logger) (map[string]PriorityJSON, error) {
 	lines := strings.Split(strings.TrimSpace(response), "\n")
 	result := make(map[string]PriorityJSON)
 
@@ -131,7 +191,7 @@ func parsePriorityResponse(response string) (map[string]PriorityJSON, error) {
 		var data PriorityJSON
 		if err := json.Unmarshal([]byte(line), &data); err != nil {
 			// パースエラーは警告して続行
-			fmt.Printf("Warning: failed to parse line: %s (error: %v)\n", line, err)
+			logger.Warn("failed to parse priority line", "line", line, "error", err)
 			continue
 		}
 
@@ -144,20 +204,3 @@ func parsePriorityResponse(response string) (map[string]PriorityJSON, error) {
 
 	return result, nil
 }
-
-// UpdatePriorityBasedOnOccurrence は出現回数に基づいて優先度を更新
-func UpdatePriorityBasedOnOccurrence(basePriority int, occurrenceCount int) int {
-	boost := 0
-	if occurrenceCount >= 5 {
-		boost = 2 // 5回以上出現 → +2
-	} else if occurrenceCount >= 3 {
-		boost = 1 // 3回以上出現 → +1
-	}
-
-	newPriority := basePriority + boost
-	if newPriority > 5 {
-		newPriority = 5 // 最大5
-	}
-
-	return newPriority
-}
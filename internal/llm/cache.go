@@ -0,0 +1,82 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// CachingProvider は同じプロンプトへの応答をメモリにキャッシュするProvider
+// 複数のgoroutineから同時に使用でき、処理中の同じプロンプトはLLMを1回だけ呼び出す
+type CachingProvider struct {
+	provider Provider
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	hits    int
+}
+
+// cacheEntry はキャッシュされた応答（doneが閉じるまでは処理中）
+type cacheEntry struct {
+	done     chan struct{}
+	response string
+	err      error
+}
+
+// NewCachingProvider はproviderの応答をキャッシュするProviderを作成
+func NewCachingProvider(provider Provider) *CachingProvider {
+	return &CachingProvider{
+		provider: provider,
+		entries:  make(map[string]*cacheEntry),
+	}
+}
+
+// Generate はキャッシュ済みの応答を返し、なければLLMに問い合わせる（エラーはキャッシュしない）
+func (c *CachingProvider) Generate(ctx context.Context, prompt string) (string, error) {
+	sum := sha256.Sum256([]byte(prompt))
+	key := hex.EncodeToString(sum[:])
+
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok {
+		c.mu.Unlock()
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		if entry.err == nil {
+			c.mu.Lock()
+			c.hits++
+			c.mu.Unlock()
+			return entry.response, nil
+		}
+		// 先行した呼び出しが失敗した場合は改めて問い合わせる
+		return c.Generate(ctx, prompt)
+	}
+	entry := &cacheEntry{done: make(chan struct{})}
+	c.entries[key] = entry
+	c.mu.Unlock()
+
+	entry.response, entry.err = c.provider.Generate(ctx, prompt)
+	if entry.err != nil {
+		c.mu.Lock()
+		delete(c.entries, key)
+		c.mu.Unlock()
+	}
+	close(entry.done)
+
+	return entry.response, entry.err
+}
+
+// GetModelName は使用中のモデル名を取得
+func (c *CachingProvider) GetModelName() string {
+	return c.provider.GetModelName()
+}
+
+// Hits はキャッシュから応答した回数を返す
+func (c *CachingProvider) Hits() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits
+}
@@ -0,0 +1,65 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+type countingProvider struct {
+	mu    sync.Mutex
+	calls map[string]int
+	err   error
+}
+
+func (p *countingProvider) Generate(ctx context.Context, prompt string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls[prompt]++
+	if p.err != nil {
+		return "", p.err
+	}
+	return "response: " + prompt, nil
+}
+
+func (p *countingProvider) GetModelName() string { return "counting" }
+
+func TestCachingProvider(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingProvider{calls: make(map[string]int)}
+	cache := NewCachingProvider(inner)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := cache.Generate(ctx, "hello")
+			if err != nil || got != "response: hello" {
+				t.Errorf("Generate = %q, %v", got, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if inner.calls["hello"] != 1 {
+		t.Errorf("provider called %d times for the same prompt, want 1", inner.calls["hello"])
+	}
+	if cache.Hits() != 9 {
+		t.Errorf("Hits = %d, want 9", cache.Hits())
+	}
+	if _, err := cache.Generate(ctx, "other"); err != nil || inner.calls["other"] != 1 {
+		t.Errorf("different prompt should reach the provider: calls=%d err=%v", inner.calls["other"], err)
+	}
+
+	// エラーはキャッシュしない
+	inner.err = errors.New("rate limited")
+	if _, err := cache.Generate(ctx, "fail"); err == nil {
+		t.Fatal("expected error")
+	}
+	inner.err = nil
+	if got, err := cache.Generate(ctx, "fail"); err != nil || got != "response: fail" || inner.calls["fail"] != 2 {
+		t.Errorf("failed prompt should be retried: got=%q calls=%d err=%v", got, inner.calls["fail"], err)
+	}
+}
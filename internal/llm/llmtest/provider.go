@@ -0,0 +1,157 @@
+// Package llmtest はテスト用のLLMプロバイダーを提供する
+package llmtest
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+// Phrase は熟語抽出で返す熟語と文脈
+type Phrase struct {
+	Phrase  string `json:"phrase"`
+	Context string `json:"context"`
+}
+
+// Judgement は優先度判定で返す意味・優先度・カテゴリ・語義
+type Judgement struct {
+	Meaning      string
+	Priority     int
+	Category     string   // Categoriesが空の場合に1つだけ返すカテゴリ
+	Categories   []string // 関連の強い順のカテゴリ
+	PartOfSpeech string
+	Definition   string
+	Sense        int // 登録済みの語義の番号（0なら新しい語義）
+}
+
+// Provider は固定の応答を返すllm.Provider
+// 熟語抽出のプロンプトにはPhrasesを、優先度判定のプロンプト（"# 表現リスト" を含む）には
+// リスト中の表現ごとにJudgementsの内容を返す（未登録の表現は優先度3・business）
+// JudgeFuncを設定すると、Judgementsの代わりに表現と文脈からJudgementを決める
+// 使い方の生成のプロンプト（"# 使い方を生成する表現" を含む）には、リスト中の表現ごとにUsagesの内容を返す（未登録の表現は返さない）
+type Provider struct {
+	Phrases    []Phrase
+	Judgements map[string]Judgement
+	JudgeFunc  func(expression, context string) Judgement
+	Usages     map[string]models.Usage
+	Err        error // 設定するとすべての呼び出しでエラーを返す
+
+	mu      sync.Mutex
+	prompts []string
+}
+
+// listItemPattern は優先度判定プロンプトの表現リストの行（"1. deprecate"）
+var listItemPattern = regexp.MustCompile(`(?m)^\d+\. (.+)$`)
+
+// Generate はプロンプトの種類に応じた固定の応答を返す
+func (p *Provider) Generate(ctx context.Context, prompt string) (string, error) {
+	p.mu.Lock()
+	p.prompts = append(p.prompts, prompt)
+	p.mu.Unlock()
+
+	if p.Err != nil {
+		return "", p.Err
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	if start := strings.Index(prompt, "# 使い方を生成する表現"); start >= 0 {
+		return p.usages(prompt[start:]), nil
+	}
+
+	var lines []string
+	start := strings.Index(prompt, "# 表現リスト")
+	if start < 0 {
+		for _, phrase := range p.Phrases {
+			line, _ := json.Marshal(phrase)
+			lines = append(lines, string(line))
+		}
+		return strings.Join(lines, "\n"), nil
+	}
+
+	list := prompt[start:]
+	if end := strings.Index(list, "# 文脈"); end >= 0 {
+		list = list[:end]
+	}
+	for _, m := range listItemPattern.FindAllStringSubmatchIndex(list, -1) {
+		expression := strings.TrimSpace(list[m[2]:m[3]])
+		j, ok := p.Judgements[expression]
+		if p.JudgeFunc != nil {
+			j, ok = p.JudgeFunc(expression, itemContext(list[m[1]:])), true
+		}
+		if !ok {
+			j = Judgement{Priority: 3, Category: "business"}
+		}
+		categories := j.Categories
+		if len(categories) == 0 && j.Category != "" {
+			categories = []string{j.Category}
+		}
+		line, _ := json.Marshal(map[string]interface{}{
+			"expression": expression,
+			"pos":        j.PartOfSpeech,
+			"meaning":    j.Meaning,
+			"definition": j.Definition,
+			"sense":      j.Sense,
+			"priority":   j.Priority,
+			"categories": categories,
+		})
+		lines = append(lines, string(line))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// usages はリスト中の表現ごとにUsagesの内容を返す
+func (p *Provider) usages(list string) string {
+	if end := strings.Index(list, "# 出力形式"); end >= 0 {
+		list = list[:end]
+	}
+	var lines []string
+	for _, m := range listItemPattern.FindAllStringSubmatch(list, -1) {
+		expression := strings.TrimSpace(m[1])
+		u, ok := p.Usages[expression]
+		if !ok {
+			continue
+		}
+		line, _ := json.Marshal(map[string]interface{}{
+			"expression":   expression,
+			"examples":     u.Examples,
+			"register":     u.Register,
+			"note":         u.Note,
+			"collocations": u.Collocations,
+			"synonyms":     u.Synonyms,
+			"antonyms":     u.Antonyms,
+		})
+		lines = append(lines, string(line))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// itemContext は表現リストの項目の直後にある文脈の行（"   文脈: ..."）の内容
+func itemContext(rest string) string {
+	for _, line := range strings.Split(rest, "\n")[1:] {
+		if !strings.HasPrefix(line, "   ") {
+			break
+		}
+		if c, ok := strings.CutPrefix(strings.TrimSpace(line), "文脈:"); ok {
+			return strings.TrimSpace(c)
+		}
+	}
+	return ""
+}
+
+// GetModelName はモデル名を返す
+func (p *Provider) GetModelName() string {
+	return "llmtest"
+}
+
+// Prompts は受け取ったプロンプトの一覧
+func (p *Provider) Prompts() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.prompts...)
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Category はカテゴリの定義（分類体系はチームごとに設定できる）
+type Category struct {
+	Name        string `db:"name"`         // 識別名（小文字、空白・カンマなし。例: "infra", "small-talk"）
+	DisplayName string `db:"display_name"` // 表示名
+	Description string `db:"description"`  // LLMへの説明（どんな表現を含めるか）
+	Parent      string `db:"parent"`       // 親カテゴリ（階層化しない場合は空）
+	Position    int    `db:"position"`     // 表示順
+}
+
+// Label は表示名（未設定なら識別名）
+func (c *Category) Label() string {
+	if c.DisplayName != "" {
+		return c.DisplayName
+	}
+	return c.Name
+}
+
+// DefaultCategories は初期のカテゴリ
+func DefaultCategories() []Category {
+	return []Category{
+		{Name: "engineering", DisplayName: "エンジニアリング", Description: "学習者の専門分野の技術・専門用語", Position: 1},
+		{Name: "business", DisplayName: "ビジネス", Description: "仕事の進め方・調整・ビジネス関連", Position: 2},
+		{Name: "casual", DisplayName: "日常会話", Description: "雑談・日常会話", Position: 3},
+	}
+}
+
+// NormalizeCategoryName はカテゴリ名を正規化（小文字にし、空白を "-" にする）
+func NormalizeCategoryName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), "-")
+}
+
+// ValidateCategoryName はカテゴリ名として使えるか確認（正規化後の名前を渡す）
+func ValidateCategoryName(name string) error {
+	if name == "" {
+		return fmt.Errorf("category name is empty")
+	}
+	for _, r := range name {
+		if r == ',' || r == '/' || unicode.IsSpace(r) || unicode.IsUpper(r) {
+			return fmt.Errorf("invalid category name: %q (use lowercase letters, digits, '-' or '_')", name)
+		}
+	}
+	return nil
+}
+
+// Taxonomy はカテゴリの一覧（分類体系）
+type Taxonomy []Category
+
+// Find は識別名または表示名（大文字小文字を区別しない）でカテゴリを探す
+func (t Taxonomy) Find(name string) *Category {
+	normalized := NormalizeCategoryName(name)
+	for i := range t {
+		if t[i].Name == normalized {
+			return &t[i]
+		}
+	}
+	for i := range t {
+		if t[i].DisplayName != "" && strings.EqualFold(t[i].DisplayName, strings.TrimSpace(name)) {
+			return &t[i]
+		}
+	}
+	return nil
+}
+
+// Label はカテゴリの表示名（分類体系にないカテゴリは識別名のまま）
+func (t Taxonomy) Label(name string) string {
+	for _, c := range t {
+		if c.Name == name {
+			return c.Label()
+		}
+	}
+	return name
+}
+
+// Children は親カテゴリparentの直下のカテゴリ（parentが空ならトップレベル）
+func (t Taxonomy) Children(parent string) []Category {
+	var children []Category
+	for _, c := range t {
+		if c.Parent == parent {
+			children = append(children, c)
+		}
+	}
+	return children
+}
+
+// Resolve はカテゴリ名の一覧を分類体系の識別名に変換する
+// 順序を保って重複を除き、指定されたカテゴリの祖先を後ろに追加する（親カテゴリでも絞り込めるように）
+// 分類体系にない名前はunknownに返す
+func (t Taxonomy) Resolve(names []string) (resolved, unknown []string) {
+	seen := make(map[string]bool)
+	var ancestors []string
+	for _, name := range names {
+		c := t.Find(name)
+		if c == nil {
+			if strings.TrimSpace(name) != "" {
+				unknown = append(unknown, name)
+			}
+			continue
+		}
+		if !seen[c.Name] {
+			seen[c.Name] = true
+			resolved = append(resolved, c.Name)
+		}
+		// 循環した定義でも止まるよう深さを分類体系の大きさまでに制限
+		for parent, depth := c.Parent, 0; parent != "" && depth < len(t); depth++ {
+			ancestors = append(ancestors, parent)
+			p := t.Find(parent)
+			if p == nil {
+				break
+			}
+			parent = p.Parent
+		}
+	}
+	for _, name := range ancestors {
+		if !seen[name] {
+			seen[name] = true
+			resolved = append(resolved, name)
+		}
+	}
+	return resolved, unknown
+}
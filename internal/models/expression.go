@@ -8,15 +8,22 @@ type Expression struct {
 	Expression      string    `db:"expression"`
 	Type            string    `db:"type"` // "word" または "phrase"
 	Meaning         string    `db:"meaning"`
-	Priority        int       `db:"priority"` // 1(低) ~ 5(高)
-	Category        string    `db:"category"` // "engineering" / "business" / "casual"
+	Priority        int       `db:"priority"`      // 1(低) ~ 5(高)。スコアから算出（手動編集した場合はその値）
+	BasePriority    int       `db:"base_priority"` // LLMが判定した（または手動で編集した）基準の優先度
+	Score           float64   `db:"score"`         // 基準の優先度に出現頻度・会議の多様さ・復習成績を加味したスコア
+	Category        string    `db:"category"`      // 主カテゴリ（Categoriesの先頭）
+	Categories      []string  `db:"-"`             // 所属するカテゴリ（関連の強い順。expression_categoriesに保存）
+	Tags            []string  `db:"-"`             // タグ（名前順。expression_tagsに保存し、AddTags/RemoveTagsで変更）
 	OccurrenceCount int       `db:"occurrence_count"`
 	FirstSeenAt     time.Time `db:"first_seen_at"`
 	LastSeenAt      time.Time `db:"last_seen_at"`
 	UpdatedAt       time.Time `db:"updated_at"`
+	ManuallyEdited  bool      `db:"manually_edited"` // 手動で編集・登録された（自動更新で上書きしない）
 
 	// 一時的なフィールド（DBには保存されない）
-	Context string `db:"-"` // 使用された文脈（処理中のみ使用）
+	Context string   `db:"-"` // 使用された文脈（処理中のみ使用）
+	Senses  []*Sense `db:"-"` // 登録済みの語義（抽出時に語義の判定の候補としてPrioritizerに渡す）
+	Sense   *Sense   `db:"-"` // Contextでの語義（Prioritizerが判定。IDが0なら新しい語義）
 }
 
 // ExpressionOccurrence は表現の出現履歴を表す
@@ -24,6 +31,9 @@ type ExpressionOccurrence struct {
 	ID           int       `db:"id"`
 	ExpressionID int       `db:"expression_id"`
 	Context      string    `db:"context"`
+	Surface      string    `db:"surface"`  // 抽出時の表記（別名経由で統合先に記録された場合など）
+	Meeting      string    `db:"meeting"`  // 出現した会議名
+	SenseID      int       `db:"sense_id"` // 文脈で使われた語義（0なら未判定）
 	OccurredAt   time.Time `db:"occurred_at"`
 }
 
@@ -35,11 +45,32 @@ const (
 	TypePhrase ExpressionType = "phrase"
 )
 
-// Category は表現のカテゴリ
-type Category string
+// HasCategory は表現がカテゴリに属するか判定
+func (e *Expression) HasCategory(name string) bool {
+	if len(e.Categories) == 0 {
+		return e.Category == name
+	}
+	for _, c := range e.Categories {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
 
-const (
-	CategoryEngineering Category = "engineering"
-	CategoryBusiness    Category = "business"
-	CategoryCasual      Category = "casual"
-)
+// HasTags は表現にtagsのすべてのタグがついているか判定
+func (e *Expression) HasTags(tags ...string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, t := range e.Tags {
+			if t == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
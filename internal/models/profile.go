@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// LearnerProfile は学習者のプロフィール（プロンプトに埋め込み、優先度・カテゴリの判定に使う）
+type LearnerProfile struct {
+	Role           string    `db:"role"`            // 役割（例: "バックエンドエンジニア", "プロダクトマネージャー"）
+	Domain         string    `db:"domain"`          // 業務の分野（例: "決済", "広告配信"）
+	Level          string    `db:"level"`           // 現在の英語のレベル（例: "TOEIC 750", "会議の聞き取りが苦手"）
+	Goals          string    `db:"goals"`           // 学習の目的（例: "設計レビューで意見を言えるようになる"）
+	NativeLanguage string    `db:"native_language"` // 母語（意味の説明に使う）
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
+// DefaultLearnerProfile はプロフィールが未設定の場合の値
+func DefaultLearnerProfile() LearnerProfile {
+	return LearnerProfile{
+		Role:           "ソフトウェアエンジニア",
+		NativeLanguage: "日本語",
+	}
+}
+
+// Merge はoverrideの空でない項目でpを上書きしたプロフィールを返す
+func (p LearnerProfile) Merge(override LearnerProfile) LearnerProfile {
+	if override.Role != "" {
+		p.Role = override.Role
+	}
+	if override.Domain != "" {
+		p.Domain = override.Domain
+	}
+	if override.Level != "" {
+		p.Level = override.Level
+	}
+	if override.Goals != "" {
+		p.Goals = override.Goals
+	}
+	if override.NativeLanguage != "" {
+		p.NativeLanguage = override.NativeLanguage
+	}
+	return p
+}
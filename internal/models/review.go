@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// ReviewCard は表現ごとの復習スケジュール状態
+type ReviewCard struct {
+	ExpressionID   int       `db:"expression_id"`
+	Ease           float64   `db:"ease"`          // SM-2の易しさ係数（初期値2.5）
+	IntervalDays   float64   `db:"interval_days"` // 現在の復習間隔（日）
+	DueAt          time.Time `db:"due_at"`        // 次回の復習日時
+	Reps           int       `db:"reps"`          // 連続正解回数
+	Lapses         int       `db:"lapses"`        // 忘れた回数
+	Stability      float64   `db:"stability"`     // FSRSの記憶安定性
+	Difficulty     float64   `db:"difficulty"`    // FSRSの難易度（1〜10）
+	LastReviewedAt time.Time `db:"last_reviewed_at"`
+
+	// 一時的なフィールド（DBには保存されない）
+	Expression *Expression `db:"-"` // 復習対象の表現
+}
+
+// IsNew はまだ一度も復習していないカードか判定
+func (c *ReviewCard) IsNew() bool {
+	return c.LastReviewedAt.IsZero()
+}
+
+// ReviewLog は復習履歴を表す
+type ReviewLog struct {
+	ID           int       `db:"id"`
+	ExpressionID int       `db:"expression_id"`
+	Grade        int       `db:"grade"`     // 1:Again 2:Hard 3:Good 4:Easy
+	Scheduler    string    `db:"scheduler"` // "sm2" / "fsrs"
+	IntervalDays float64   `db:"interval_days"`
+	ReviewedAt   time.Time `db:"reviewed_at"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Sense は表現の語義（同じ表現でも会議の文脈によって品詞・意味が異なる）
+type Sense struct {
+	ID              int       `db:"id"`
+	ExpressionID    int       `db:"expression_id"`
+	PartOfSpeech    string    `db:"part_of_speech"` // 品詞（"verb", "noun", "phrasal verb" など）
+	Gloss           string    `db:"gloss"`          // 学習者の母語での意味
+	Definition      string    `db:"definition"`     // 英語での定義
+	OccurrenceCount int       `db:"-"`              // この語義で使われた出現履歴の数
+	CreatedAt       time.Time `db:"created_at"`
+}
+
+// NormalizePartOfSpeech は品詞を正規化（小文字にし、連続する空白を1つにする）
+func NormalizePartOfSpeech(pos string) string {
+	return strings.Join(strings.Fields(strings.ToLower(pos)), " ")
+}
+
+// Same は品詞と意味が同じ語義か判定（意味の前後の空白・英字の大文字小文字は区別しない）
+func (s *Sense) Same(other *Sense) bool {
+	return NormalizePartOfSpeech(s.PartOfSpeech) == NormalizePartOfSpeech(other.PartOfSpeech) &&
+		strings.EqualFold(strings.TrimSpace(s.Gloss), strings.TrimSpace(other.Gloss))
+}
+
+// String は表示用の語義（"(verb) 出荷する — to send goods to a customer"）
+func (s *Sense) String() string {
+	var b strings.Builder
+	if s.PartOfSpeech != "" {
+		b.WriteString("(" + s.PartOfSpeech + ") ")
+	}
+	b.WriteString(s.Gloss)
+	if s.Definition != "" {
+		b.WriteString(" — " + s.Definition)
+	}
+	return b.String()
+}
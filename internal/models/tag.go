@@ -0,0 +1,88 @@
+package models
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+)
+
+// タグのつけ方（expression_tags.source）
+const (
+	TagSourceManual  = "manual"  // 手動でつけたタグ
+	TagSourceMeeting = "meeting" // 会議の設定・ルールから自動でつけたタグ
+)
+
+// NormalizeTag はタグを正規化（小文字にし、空白を "-" にする）
+func NormalizeTag(tag string) string {
+	return strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+}
+
+// ValidateTag はタグとして使えるか確認（正規化後のタグを渡す）
+func ValidateTag(tag string) error {
+	if tag == "" {
+		return fmt.Errorf("tag is empty")
+	}
+	for _, r := range tag {
+		if r == ',' || unicode.IsSpace(r) || unicode.IsUpper(r) {
+			return fmt.Errorf("invalid tag: %q (use lowercase letters, digits, '-' or '_')", tag)
+		}
+	}
+	return nil
+}
+
+// NormalizeTags はカンマ区切り・複数指定のタグを正規化し、重複を除く（空のタグは無視）
+func NormalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var normalized []string
+	for _, value := range tags {
+		for _, tag := range strings.Split(value, ",") {
+			tag = NormalizeTag(tag)
+			if tag == "" || seen[tag] {
+				continue
+			}
+			if err := ValidateTag(tag); err != nil {
+				return nil, err
+			}
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized, nil
+}
+
+// TagRule は会議名がPatternに一致する会議で出現した表現にTagを自動でつけるルール
+type TagRule struct {
+	Tag     string `db:"tag"`
+	Pattern string `db:"pattern"` // 会議名のglob（大文字小文字を区別しない。例: "q3 planning*", "*interview*"）
+}
+
+// ValidateTagPattern は会議名のパターンとして使えるか確認
+func ValidateTagPattern(pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return fmt.Errorf("meeting pattern is empty")
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid meeting pattern %q: %w", pattern, err)
+	}
+	return nil
+}
+
+// Match は会議名がパターンに一致するか判定
+func (r TagRule) Match(meeting string) bool {
+	matched, err := path.Match(strings.ToLower(r.Pattern), strings.ToLower(meeting))
+	return err == nil && matched
+}
+
+// MeetingTags は会議名に一致するルールのタグ（重複なし、ルールの順）
+func MeetingTags(rules []TagRule, meeting string) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, rule := range rules {
+		if rule.Match(meeting) && !seen[rule.Tag] {
+			seen[rule.Tag] = true
+			tags = append(tags, rule.Tag)
+		}
+	}
+	return tags
+}
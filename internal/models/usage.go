@@ -0,0 +1,53 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// 表現の使用域（Usage.Register）
+const (
+	RegisterFormal   = "formal"
+	RegisterNeutral  = "neutral"
+	RegisterInformal = "informal"
+	RegisterSlang    = "slang"
+)
+
+// Registers は使用域の一覧（フォーマルな順）
+var Registers = []string{RegisterFormal, RegisterNeutral, RegisterInformal, RegisterSlang}
+
+// Usage は表現の使い方（LLMが生成した例文・使用上の注意・コロケーション・類義語・対義語）
+// transcriptの文脈は話し言葉の断片であることが多いため、学習用に整った例文を別に持つ
+type Usage struct {
+	ExpressionID int       `db:"expression_id"`
+	Examples     []Example `db:"-"`        // 例文（expression_examplesに保存）
+	Register     string    `db:"register"` // 使用域（Registersのいずれか。判定できなければ空）
+	Note         string    `db:"note"`     // 使用上の注意（学習者の母語）
+	Collocations []string  `db:"-"`        // よく一緒に使われる語句（expression_related_wordsに保存）
+	Synonyms     []string  `db:"-"`
+	Antonyms     []string  `db:"-"`
+	GeneratedAt  time.Time `db:"generated_at"`
+}
+
+// Example は例文と学習者の母語での訳
+type Example struct {
+	Sentence    string `json:"sentence"`
+	Translation string `json:"translation"`
+}
+
+// NormalizeRegister は使用域を正規化（Registersにない値は空）
+func NormalizeRegister(register string) string {
+	register = strings.ToLower(strings.TrimSpace(register))
+	for _, r := range Registers {
+		if r == register {
+			return r
+		}
+	}
+	return ""
+}
+
+// Empty は生成された内容がないか判定
+func (u *Usage) Empty() bool {
+	return len(u.Examples) == 0 && u.Register == "" && u.Note == "" &&
+		len(u.Collocations) == 0 && len(u.Synonyms) == 0 && len(u.Antonyms) == 0
+}
@@ -0,0 +1,190 @@
+package notion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultBaseURL はNotion APIのベースURL
+	DefaultBaseURL = "https://api.notion.com/v1"
+	// APIVersion はNotion-Versionヘッダーの値
+	APIVersion = "2022-06-28"
+
+	// defaultMaxRetries はレート制限（429）時の最大リトライ回数
+	defaultMaxRetries = 5
+	// defaultRetryWait はRetry-Afterヘッダーがない場合の初回待機時間
+	defaultRetryWait = time.Second
+	// queryPageSize はデータベースクエリ1回あたりの取得件数（APIの上限）
+	queryPageSize = 100
+)
+
+// Client はNotion REST APIクライアント
+type Client struct {
+	apiKey     string
+	baseURL    string
+	client     *http.Client
+	maxRetries int
+	sleep      func(ctx context.Context, d time.Duration) error
+}
+
+// NewClient は新しいClientを作成（baseURLが空ならNotion APIを使用）
+func NewClient(apiKey, baseURL string) (*Client, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is required")
+	}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{
+		apiKey:     apiKey,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		client:     &http.Client{Timeout: 30 * time.Second},
+		maxRetries: defaultMaxRetries,
+		sleep:      sleepContext,
+	}, nil
+}
+
+// QueryDatabase はデータベースのページを1ページ分取得（cursorが空なら先頭から）
+func (c *Client) QueryDatabase(ctx context.Context, databaseID, cursor string) (*QueryResponse, error) {
+	body := map[string]interface{}{"page_size": queryPageSize}
+	if cursor != "" {
+		body["start_cursor"] = cursor
+	}
+
+	var resp QueryResponse
+	if err := c.do(ctx, http.MethodPost, "/databases/"+databaseID+"/query", body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to query database: %w", err)
+	}
+	return &resp, nil
+}
+
+// QueryAllPages はページネーションをたどってデータベースの全ページを取得
+func (c *Client) QueryAllPages(ctx context.Context, databaseID string) ([]Page, error) {
+	var pages []Page
+	cursor := ""
+	for {
+		resp, err := c.QueryDatabase(ctx, databaseID, cursor)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, resp.Results...)
+		if !resp.HasMore || resp.NextCursor == "" {
+			return pages, nil
+		}
+		cursor = resp.NextCursor
+	}
+}
+
+// CreatePage はデータベースに新しいページを作成
+func (c *Client) CreatePage(ctx context.Context, databaseID string, properties map[string]Property) (*Page, error) {
+	body := map[string]interface{}{
+		"parent":     map[string]string{"database_id": databaseID},
+		"properties": properties,
+	}
+
+	var page Page
+	if err := c.do(ctx, http.MethodPost, "/pages", body, &page); err != nil {
+		return nil, fmt.Errorf("failed to create page: %w", err)
+	}
+	return &page, nil
+}
+
+// UpdatePage は既存ページのプロパティを更新
+func (c *Client) UpdatePage(ctx context.Context, pageID string, properties map[string]Property) (*Page, error) {
+	body := map[string]interface{}{"properties": properties}
+
+	var page Page
+	if err := c.do(ctx, http.MethodPatch, "/pages/"+pageID, body, &page); err != nil {
+		return nil, fmt.Errorf("failed to update page: %w", err)
+	}
+	return &page, nil
+}
+
+// APIError はNotion APIのエラーレスポンス
+type APIError struct {
+	StatusCode int
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("notion API request failed with status %d: %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// do はリクエストを送信し、429の場合はRetry-Afterに従って待機・リトライする
+func (c *Client) do(ctx context.Context, method, path string, body, result interface{}) error {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	wait := defaultRetryWait
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(jsonData))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+		req.Header.Set("Notion-Version", APIVersion)
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to send request: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < c.maxRetries {
+			resp.Body.Close()
+			d := wait
+			if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+				d = time.Duration(seconds) * time.Second
+			}
+			if err := c.sleep(ctx, d); err != nil {
+				return err
+			}
+			wait *= 2
+			continue
+		}
+
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			apiErr := &APIError{StatusCode: resp.StatusCode}
+			if json.Unmarshal(data, apiErr) != nil || apiErr.Message == "" {
+				apiErr.Message = string(data)
+			}
+			return apiErr
+		}
+
+		if err := json.Unmarshal(data, result); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		return nil
+	}
+}
+
+// sleepContext はコンテキストのキャンセルを考慮して待機
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,135 @@
+package notion_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/notion"
+	"github.com/mamyudapao/learn-by-transcript/internal/notion/notiontest"
+)
+
+const testDatabaseID = "db-1"
+
+func newTestClient(t *testing.T) (*notion.Client, *notiontest.Server) {
+	t.Helper()
+	server := notiontest.NewServer()
+	t.Cleanup(server.Close)
+
+	client, err := notion.NewClient(notiontest.APIKey, server.BaseURL())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client, server
+}
+
+func TestQueryAllPagesFollowsCursor(t *testing.T) {
+	ctx := context.Background()
+	client, server := newTestClient(t)
+	server.MaxPageSize = 2
+
+	for i := 0; i < 5; i++ {
+		server.AddPage(testDatabaseID, map[string]notion.Property{
+			"Expression": notion.TitleProperty(fmt.Sprintf("expr-%d", i)),
+		})
+	}
+	server.AddPage("other-db", map[string]notion.Property{"Expression": notion.TitleProperty("other")})
+
+	pages, err := client.QueryAllPages(ctx, testDatabaseID)
+	if err != nil {
+		t.Fatalf("QueryAllPages: %v", err)
+	}
+	if len(pages) != 5 {
+		t.Fatalf("expected 5 pages, got %d", len(pages))
+	}
+	if got := pages[4].Properties["Expression"].PlainText(); got != "expr-4" {
+		t.Errorf("last page title = %q, expected expr-4", got)
+	}
+	if n := server.Requests(http.MethodPost, "/v1/databases/"+testDatabaseID+"/query"); n != 3 {
+		t.Errorf("expected 3 query requests, got %d", n)
+	}
+}
+
+func TestRetriesOnRateLimit(t *testing.T) {
+	ctx := context.Background()
+	client, server := newTestClient(t)
+	server.RateLimitNext(2)
+
+	page, err := client.CreatePage(ctx, testDatabaseID, map[string]notion.Property{
+		"Expression": notion.TitleProperty("circle back"),
+	})
+	if err != nil {
+		t.Fatalf("CreatePage: %v", err)
+	}
+	if n := server.Requests(http.MethodPost, "/v1/pages"); n != 3 {
+		t.Errorf("expected 3 attempts, got %d", n)
+	}
+	if len(server.Pages(testDatabaseID)) != 1 {
+		t.Errorf("expected exactly one page to be created, got %d", len(server.Pages(testDatabaseID)))
+	}
+
+	// 更新も同じページに反映される
+	updated, err := client.UpdatePage(ctx, page.ID, map[string]notion.Property{"Meaning": notion.TextProperty("後で話し合う")})
+	if err != nil {
+		t.Fatalf("UpdatePage: %v", err)
+	}
+	if got := updated.Properties["Meaning"].PlainText(); got != "後で話し合う" {
+		t.Errorf("Meaning = %q", got)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	ctx := context.Background()
+	client, server := newTestClient(t)
+
+	pageID := server.AddPage(testDatabaseID, map[string]notion.Property{"Expression": notion.TitleProperty("x")})
+	server.ArchivePage(pageID)
+
+	_, err := client.UpdatePage(ctx, pageID, map[string]notion.Property{})
+	var apiErr *notion.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 APIError, got %v", err)
+	}
+
+	badClient, err := notion.NewClient("wrong-key", server.BaseURL())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := badClient.QueryDatabase(ctx, testDatabaseID, ""); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 APIError, got %v", err)
+	}
+}
+
+// 空の値は省略せずに送り、Notion上の値を消去する（型のないプロパティは実際のAPIと同様に拒否される）
+func TestUpdatePageClearsValues(t *testing.T) {
+	ctx := context.Background()
+	client, server := newTestClient(t)
+
+	pageID := server.AddPage(testDatabaseID, map[string]notion.Property{
+		"Expression": notion.TitleProperty("circle back"),
+		"Meaning":    notion.TextProperty("後で話し合う"),
+		"Category":   notion.SelectProperty("business"),
+	})
+
+	updated, err := client.UpdatePage(ctx, pageID, map[string]notion.Property{
+		"Meaning":  notion.TextProperty(""),
+		"Category": notion.SelectProperty(""),
+	})
+	if err != nil {
+		t.Fatalf("UpdatePage: %v", err)
+	}
+	if got := updated.Properties["Meaning"].PlainText(); got != "" {
+		t.Errorf("Meaning = %q, expected it to be cleared", got)
+	}
+	if got := updated.Properties["Category"].Select; got != nil {
+		t.Errorf("Category = %+v, expected it to be cleared", got)
+	}
+
+	_, err = client.UpdatePage(ctx, pageID, map[string]notion.Property{"Meaning": {}})
+	var apiErr *notion.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 APIError for untyped property, got %v", err)
+	}
+}
@@ -0,0 +1,320 @@
+// Package notiontest はテスト用のNotion APIの代替サーバーを提供する
+package notiontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/notion"
+)
+
+// APIKey はServerが受け付けるAPIキー
+const APIKey = "secret_test"
+
+// Server はNotion APIのデータベースクエリ・ページ作成・更新をメモリ上で再現するhttptestサーバー
+type Server struct {
+	*httptest.Server
+
+	// MaxPageSize はクエリ1回で返す最大件数（ページネーションの確認用、0ならリクエストのpage_size）
+	MaxPageSize int
+
+	mu          sync.Mutex
+	pages       []*storedPage
+	nextID      int
+	rateLimited int
+	requests    map[string]int
+}
+
+// storedPage はサーバー上のページ
+type storedPage struct {
+	databaseID string
+	page       notion.Page
+}
+
+// NewServer は代替サーバーを起動（終了はt.Cleanupなどで Close を呼ぶ）
+func NewServer() *Server {
+	s := &Server{requests: make(map[string]int)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/databases/{id}/query", s.handleQuery)
+	mux.HandleFunc("POST /v1/pages", s.handleCreate)
+	mux.HandleFunc("PATCH /v1/pages/{id}", s.handleUpdate)
+	s.Server = httptest.NewServer(s.middleware(mux))
+
+	return s
+}
+
+// BaseURL はnotion.NewClientに渡すベースURL
+func (s *Server) BaseURL() string {
+	return s.URL + "/v1"
+}
+
+// RateLimitNext は次のn回のリクエストに429（Retry-After: 0）を返す
+func (s *Server) RateLimitNext(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rateLimited = n
+}
+
+// Requests は"METHOD /path"ごとのリクエスト回数（429を返したものを含む）
+func (s *Server) Requests(method, path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[method+" "+path]
+}
+
+// AddPage はデータベースにページを直接追加（既存データの用意に使用）
+func (s *Server) AddPage(databaseID string, properties map[string]notion.Property) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addPage(databaseID, properties).ID
+}
+
+// ArchivePage はページをアーカイブ（Notion上での削除に相当）
+func (s *Server) ArchivePage(pageID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p := s.find(pageID); p != nil {
+		p.page.Archived = true
+	}
+}
+
+// Pages はデータベースのアーカイブされていないページ一覧（作成順）
+func (s *Server) Pages(databaseID string) []notion.Page {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.livePages(databaseID)
+}
+
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests[r.Method+" "+r.URL.Path]++
+		limited := s.rateLimited > 0
+		if limited {
+			s.rateLimited--
+		}
+		s.mu.Unlock()
+
+		if limited {
+			w.Header().Set("Retry-After", "0")
+			writeError(w, http.StatusTooManyRequests, "rate_limited", "Rate limited")
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer "+APIKey {
+			writeError(w, http.StatusUnauthorized, "unauthorized", "API token is invalid.")
+			return
+		}
+		if r.Header.Get("Notion-Version") == "" {
+			writeError(w, http.StatusBadRequest, "missing_version", "Notion-Version header failed validation.")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleQuery(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		StartCursor string `json:"start_cursor"`
+		PageSize    int    `json:"page_size"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pages := s.livePages(r.PathValue("id"))
+	start := 0
+	if req.StartCursor != "" {
+		n, err := strconv.Atoi(req.StartCursor)
+		if err != nil || n < 0 || n > len(pages) {
+			writeError(w, http.StatusBadRequest, "validation_error", "start_cursor is invalid.")
+			return
+		}
+		start = n
+	}
+	size := req.PageSize
+	if size <= 0 || size > 100 {
+		size = 100
+	}
+	if s.MaxPageSize > 0 && size > s.MaxPageSize {
+		size = s.MaxPageSize
+	}
+	end := min(start+size, len(pages))
+
+	resp := notion.QueryResponse{Results: pages[start:end]}
+	if end < len(pages) {
+		resp.HasMore = true
+		resp.NextCursor = strconv.Itoa(end)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Parent struct {
+			DatabaseID string `json:"database_id"`
+		} `json:"parent"`
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", err.Error())
+		return
+	}
+	if req.Parent.DatabaseID == "" {
+		writeError(w, http.StatusBadRequest, "validation_error", "parent.database_id is required.")
+		return
+	}
+	properties, err := decodeProperties(req.Properties)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "validation_error", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, s.addPage(req.Parent.DatabaseID, properties))
+}
+
+func (s *Server) handleUpdate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", err.Error())
+		return
+	}
+	properties, err := decodeProperties(req.Properties)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "validation_error", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.find(r.PathValue("id"))
+	if p == nil {
+		writeError(w, http.StatusNotFound, "object_not_found", "Could not find page.")
+		return
+	}
+	if p.page.Archived {
+		writeError(w, http.StatusBadRequest, "validation_error", "Can't edit block that is archived.")
+		return
+	}
+	for name, prop := range properties {
+		p.page.Properties[name] = withPlainText(prop)
+	}
+	writeJSON(w, http.StatusOK, p.page)
+}
+
+// addPage はページを追加（呼び出し側でロック済み）
+func (s *Server) addPage(databaseID string, properties map[string]notion.Property) notion.Page {
+	s.nextID++
+	page := notion.Page{
+		ID:         fmt.Sprintf("page-%04d", s.nextID),
+		Properties: make(map[string]notion.Property, len(properties)),
+	}
+	for name, prop := range properties {
+		page.Properties[name] = withPlainText(prop)
+	}
+	s.pages = append(s.pages, &storedPage{databaseID: databaseID, page: page})
+	return page
+}
+
+// find はIDでページを検索（呼び出し側でロック済み）
+func (s *Server) find(pageID string) *storedPage {
+	for _, p := range s.pages {
+		if p.page.ID == pageID {
+			return p
+		}
+	}
+	return nil
+}
+
+// livePages はアーカイブされていないページ一覧（呼び出し側でロック済み）
+func (s *Server) livePages(databaseID string) []notion.Page {
+	var pages []notion.Page
+	for _, p := range s.pages {
+		if p.databaseID == databaseID && !p.page.Archived {
+			pages = append(pages, p.page)
+		}
+	}
+	return pages
+}
+
+// decodeProperties はリクエストのプロパティを検証してデコード
+// 実際のAPIと同様に、typeがないものや値のキーがないもの（{"Meaning":{}}など）は受け付けない
+func decodeProperties(raw map[string]json.RawMessage) (map[string]notion.Property, error) {
+	properties := make(map[string]notion.Property, len(raw))
+	for name, data := range raw {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, fmt.Errorf("body.properties.%s should be an object.", name)
+		}
+		var typ string
+		if err := json.Unmarshal(fields["type"], &typ); err != nil || typ == "" {
+			return nil, fmt.Errorf("body.properties.%s.type should be defined.", name)
+		}
+		if _, ok := fields[typ]; !ok {
+			return nil, fmt.Errorf("body.properties.%s.%s should be defined.", name, typ)
+		}
+		var prop notion.Property
+		if err := json.Unmarshal(data, &prop); err != nil {
+			return nil, fmt.Errorf("body.properties.%s is invalid: %v", name, err)
+		}
+		if err := validateRichText(name, prop); err != nil {
+			return nil, err
+		}
+		properties[name] = prop
+	}
+	return properties, nil
+}
+
+// validateRichText は実際のAPIと同様にtitle/rich_textの要素数（100）と要素ごとの文字数（2000）の上限を検証
+func validateRichText(name string, prop notion.Property) error {
+	parts := prop.Title
+	if prop.Type == notion.PropertyTypeRichText {
+		parts = prop.RichText
+	}
+	if len(parts) > 100 {
+		return fmt.Errorf("body.properties.%s.%s.length should be ≤ `100`, instead was `%d`.", name, prop.Type, len(parts))
+	}
+	for i, rt := range parts {
+		if rt.Text != nil && utf8.RuneCountInString(rt.Text.Content) > 2000 {
+			return fmt.Errorf("body.properties.%s.%s[%d].text.content.length should be ≤ `2000`, instead was `%d`.",
+				name, prop.Type, i, utf8.RuneCountInString(rt.Text.Content))
+		}
+	}
+	return nil
+}
+
+// withPlainText は実際のAPIと同様にplain_textを補完
+func withPlainText(prop notion.Property) notion.Property {
+	for _, parts := range [][]notion.RichText{prop.Title, prop.RichText} {
+		for i := range parts {
+			if parts[i].Text != nil {
+				parts[i].PlainText = parts[i].Text.Content
+			}
+		}
+	}
+	return prop
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]string{"object": "error", "code": code, "message": message})
+}
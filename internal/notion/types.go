@@ -0,0 +1,156 @@
+package notion
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Page はNotionのページ（データベースの1行）
+type Page struct {
+	ID         string              `json:"id"`
+	Archived   bool                `json:"archived"`
+	Properties map[string]Property `json:"properties"`
+}
+
+// プロパティの型（Property.Type）
+const (
+	PropertyTypeTitle    = "title"
+	PropertyTypeRichText = "rich_text"
+	PropertyTypeNumber   = "number"
+	PropertyTypeSelect   = "select"
+	PropertyTypeDate     = "date"
+)
+
+// Property はページのプロパティ値（使用する型のみ対応）
+type Property struct {
+	Type     string        `json:"type,omitempty"`
+	Title    []RichText    `json:"title,omitempty"`
+	RichText []RichText    `json:"rich_text,omitempty"`
+	Number   *float64      `json:"number,omitempty"`
+	Select   *SelectOption `json:"select,omitempty"`
+	Date     *Date         `json:"date,omitempty"`
+}
+
+// RichText はtitle/rich_textの要素
+type RichText struct {
+	Type      string `json:"type,omitempty"`
+	Text      *Text  `json:"text,omitempty"`
+	PlainText string `json:"plain_text,omitempty"`
+}
+
+// Text はRichTextの本文
+type Text struct {
+	Content string `json:"content"`
+}
+
+// SelectOption はselectプロパティの選択肢
+type SelectOption struct {
+	Name string `json:"name"`
+}
+
+// Date はdateプロパティの値
+type Date struct {
+	Start string `json:"start"`
+}
+
+// QueryResponse はデータベースクエリの1ページ分の結果
+type QueryResponse struct {
+	Results    []Page `json:"results"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Notion APIの制限
+const (
+	maxTextLength    = 2000 // rich_text要素1つあたりの最大文字数
+	maxRichTextItems = 100  // title/rich_textプロパティの要素数の上限
+)
+
+// TitleProperty はtitleプロパティを作成
+func TitleProperty(s string) Property {
+	return Property{Type: PropertyTypeTitle, Title: richText(s)}
+}
+
+// TextProperty はrich_textプロパティを作成
+func TextProperty(s string) Property {
+	return Property{Type: PropertyTypeRichText, RichText: richText(s)}
+}
+
+// NumberProperty はnumberプロパティを作成
+func NumberProperty(n float64) Property {
+	return Property{Type: PropertyTypeNumber, Number: &n}
+}
+
+// SelectProperty はselectプロパティを作成（空文字列なら選択を解除）
+func SelectProperty(name string) Property {
+	if name == "" {
+		return Property{Type: PropertyTypeSelect}
+	}
+	return Property{Type: PropertyTypeSelect, Select: &SelectOption{Name: name}}
+}
+
+// DateProperty はdateプロパティを作成（日付のみ）
+func DateProperty(t time.Time) Property {
+	return Property{Type: PropertyTypeDate, Date: &Date{Start: t.Format("2006-01-02")}}
+}
+
+// MarshalJSON はTypeの値を空でも省略せずに出力（Notion APIは値のないプロパティを受け付けないため）
+// 空のtitle/rich_textは[]、空のselect/dateはnullになり、Notion上の値を消去する
+func (p Property) MarshalJSON() ([]byte, error) {
+	var value interface{}
+	switch p.Type {
+	case PropertyTypeTitle:
+		value = nonNilRichText(p.Title)
+	case PropertyTypeRichText:
+		value = nonNilRichText(p.RichText)
+	case PropertyTypeNumber:
+		value = p.Number
+	case PropertyTypeSelect:
+		value = p.Select
+	case PropertyTypeDate:
+		value = p.Date
+	default:
+		type plain Property
+		return json.Marshal(plain(p))
+	}
+	return json.Marshal(map[string]interface{}{"type": p.Type, p.Type: value})
+}
+
+func nonNilRichText(parts []RichText) []RichText {
+	if parts == nil {
+		return []RichText{}
+	}
+	return parts
+}
+
+// richText は文字列をrich_text要素に変換（空文字列なら空配列）
+// 長い文字列はmaxTextLength文字ずつの要素に分け、要素数の上限を超える部分は切り捨てる
+func richText(s string) []RichText {
+	parts := []RichText{}
+	runes := []rune(s)
+	for len(runes) > 0 && len(parts) < maxRichTextItems {
+		n := min(len(runes), maxTextLength)
+		parts = append(parts, RichText{Type: "text", Text: &Text{Content: string(runes[:n])}})
+		runes = runes[n:]
+	}
+	return parts
+}
+
+// PlainText はtitle/rich_textプロパティの文字列を取得
+func (p Property) PlainText() string {
+	parts := p.Title
+	if len(parts) == 0 {
+		parts = p.RichText
+	}
+
+	var b strings.Builder
+	for _, rt := range parts {
+		if rt.PlainText != "" {
+			b.WriteString(rt.PlainText)
+		} else if rt.Text != nil {
+			b.WriteString(rt.Text.Content)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,444 @@
+package output
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+func init() {
+	Register("anki", []string{".apkg"}, func(repo storage.Repository) Exporter {
+		return NewAnkiExporter(repo, DefaultAnkiDeckName)
+	})
+}
+
+// DefaultAnkiDeckName はデフォルトのAnkiデッキ名
+const DefaultAnkiDeckName = "Learn by Transcript"
+
+const (
+	// ankiNoteIDBase / ankiCardIDBase は表現IDからノート・カードIDを導出する基準値
+	ankiNoteIDBase int64 = 1736900000000
+	ankiCardIDBase int64 = 1736950000000
+)
+
+// ankiFields はノートタイプのフィールド（順序がノートのフィールド順になる）
+var ankiFields = []string{"Expression", "Meaning", "Context", "Category", "Priority", "Examples", "Usage"}
+
+// ankiModelID はノートタイプのID（フィールドが同じなら再エクスポートでも同じノートタイプとして扱われる）
+// フィールドを変えると別のノートタイプになり、取り込み済みの異なるフィールドのノートタイプと衝突しない
+var ankiModelID = ankiModelIDFor(ankiFields)
+
+// ankiSchema はAnki 2.1（スキーマv11）コレクションのテーブル定義
+const ankiSchema = `
+CREATE TABLE col (
+    id integer primary key, crt integer not null, mod integer not null, scm integer not null,
+    ver integer not null, dty integer not null, usn integer not null, ls integer not null,
+    conf text not null, models text not null, decks text not null, dconf text not null, tags text not null
+);
+CREATE TABLE notes (
+    id integer primary key, guid text not null, mid integer not null, mod integer not null,
+    usn integer not null, tags text not null, flds text not null, sfld integer not null,
+    csum integer not null, flags integer not null, data text not null
+);
+CREATE TABLE cards (
+    id integer primary key, nid integer not null, did integer not null, ord integer not null,
+    mod integer not null, usn integer not null, type integer not null, queue integer not null,
+    due integer not null, ivl integer not null, factor integer not null, reps integer not null,
+    lapses integer not null, left integer not null, odue integer not null, odid integer not null,
+    flags integer not null, data text not null
+);
+CREATE TABLE revlog (
+    id integer primary key, cid integer not null, usn integer not null, ease integer not null,
+    ivl integer not null, lastIvl integer not null, factor integer not null, time integer not null,
+    type integer not null
+);
+CREATE TABLE graves (usn integer not null, oid integer not null, type integer not null);
+CREATE INDEX ix_notes_usn on notes (usn);
+CREATE INDEX ix_cards_usn on cards (usn);
+CREATE INDEX ix_revlog_usn on revlog (usn);
+CREATE INDEX ix_cards_nid on cards (nid);
+CREATE INDEX ix_cards_sched on cards (did, queue, due);
+CREATE INDEX ix_revlog_cid on revlog (cid);
+CREATE INDEX ix_notes_csum on notes (csum);
+`
+
+const ankiCSS = `.card { font-family: Arial, sans-serif; font-size: 20px; text-align: center; color: black; background-color: white; }
+.expression { font-size: 28px; font-weight: bold; }
+.meaning { margin-top: 12px; }
+.context { margin-top: 12px; font-size: 16px; color: #555; font-style: italic; }
+.examples { margin-top: 12px; font-size: 16px; text-align: left; }
+.usage { margin-top: 12px; font-size: 14px; color: #555; text-align: left; }
+.meta { margin-top: 12px; font-size: 12px; color: #999; }`
+
+const ankiFrontTemplate = `<div class="expression">{{Expression}}</div>`
+
+const ankiBackTemplate = `{{FrontSide}}
+<hr id=answer>
+<div class="meaning">{{Meaning}}</div>
+{{#Context}}<div class="context">{{Context}}</div>{{/Context}}
+{{#Examples}}<div class="examples">{{Examples}}</div>{{/Examples}}
+{{#Usage}}<div class="usage">{{Usage}}</div>{{/Usage}}
+<div class="meta">{{Category}} / 優先度 {{Priority}}</div>`
+
+// AnkiExporter はAnkiのデッキパッケージ（.apkg）に出力する
+type AnkiExporter struct {
+	repository storage.Repository
+	deckName   string
+}
+
+// NewAnkiExporter は新しいAnkiExporterを作成
+func NewAnkiExporter(repo storage.Repository, deckName string) *AnkiExporter {
+	if deckName == "" {
+		deckName = DefaultAnkiDeckName
+	}
+	return &AnkiExporter{
+		repository: repo,
+		deckName:   deckName,
+	}
+}
+
+// Export はフィルタリング・並び替えを適用した表現を.apkgファイルに出力（opts.DeckNameでデッキ名を上書き）
+// ノートのGUIDは表現IDから導出するため、再エクスポートしてインポートすると既存のカードが更新される
+func (e *AnkiExporter) Export(ctx context.Context, outputPath string, opts ExportOptions) error {
+	expressions, err := SelectExpressions(ctx, e.repository, opts)
+	if err != nil {
+		return err
+	}
+
+	deckName := e.deckName
+	if opts.DeckName != "" {
+		deckName = opts.DeckName
+	}
+
+	// コレクションは一時ディレクトリにSQLiteで作成してからzipに格納
+	tmpDir, err := os.MkdirTemp("", "anki-export-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	collectionPath := filepath.Join(tmpDir, "collection.anki2")
+	if err := e.writeCollection(ctx, collectionPath, deckName, expressions, opts); err != nil {
+		return err
+	}
+
+	return writeAnkiPackage(outputPath, collectionPath)
+}
+
+// writeCollection はAnkiコレクション（collection.anki2）を作成
+func (e *AnkiExporter) writeCollection(ctx context.Context, path, deckName string, expressions []*models.Expression, opts ExportOptions) error {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return fmt.Errorf("failed to create collection: %w", err)
+	}
+	defer db.Close()
+
+	if _, err := db.ExecContext(ctx, ankiSchema); err != nil {
+		return fmt.Errorf("failed to create collection schema: %w", err)
+	}
+
+	now := time.Now()
+	deckID := ankiDeckID(deckName)
+
+	if err := e.writeCollectionRow(ctx, db, now, deckName, deckID); err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i, expr := range expressions {
+		occurrences, err := e.repository.GetOccurrences(ctx, expr.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get occurrences: %w", err)
+		}
+
+		// 複数のcontextは改行区切りで1つのフィールドに入れる
+		contexts, err := exportContexts(ctx, e.repository, expr.ID, opts)
+		if err != nil {
+			return err
+		}
+		for j, c := range contexts {
+			contexts[j] = highlightExpression(html.EscapeString(c), html.EscapeString(expr.Expression))
+		}
+
+		fields := []string{
+			html.EscapeString(expr.Expression),
+			html.EscapeString(expr.Meaning),
+			strings.Join(contexts, "<br>"),
+			html.EscapeString(strings.Join(expr.Categories, ", ")),
+			strconv.Itoa(expr.Priority),
+		}
+		usage, err := e.repository.GetUsage(ctx, expr.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get usage: %w", err)
+		}
+		fields = append(fields, ankiUsageFields(usage, expr.Expression)...)
+
+		noteID := ankiNoteIDBase + int64(expr.ID)
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO notes (id, guid, mid, mod, usn, tags, flds, sfld, csum, flags, data)
+			VALUES (?, ?, ?, ?, -1, ?, ?, ?, ?, 0, '')
+		`, noteID, ankiGUID(expr.ID), ankiModelID, now.Unix(), ankiTags(expr, occurrences),
+			strings.Join(fields, "\x1f"), expr.Expression, ankiChecksum(expr.Expression))
+		if err != nil {
+			return fmt.Errorf("failed to insert note: %w", err)
+		}
+
+		// 新規カード（dueは新規カードの出題順）
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO cards (id, nid, did, ord, mod, usn, type, queue, due, ivl, factor, reps, lapses, left, odue, odid, flags, data)
+			VALUES (?, ?, ?, 0, ?, -1, 0, 0, ?, 0, 0, 0, 0, 0, 0, 0, 0, '')
+		`, ankiCardIDBase+int64(expr.ID), noteID, deckID, now.Unix(), i+1)
+		if err != nil {
+			return fmt.Errorf("failed to insert card: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ankiUsageFields は使い方のフィールド（例文と訳のリスト、使用域・注意・関連語）。未生成なら空
+func ankiUsageFields(usage *models.Usage, expression string) []string {
+	if usage == nil {
+		return []string{"", ""}
+	}
+
+	var examples []string
+	for _, ex := range usage.Examples {
+		item := highlightExpression(html.EscapeString(ex.Sentence), html.EscapeString(expression))
+		if ex.Translation != "" {
+			item += "<br><small>" + html.EscapeString(ex.Translation) + "</small>"
+		}
+		examples = append(examples, "<li>"+item+"</li>")
+	}
+	exampleField := ""
+	if len(examples) > 0 {
+		exampleField = "<ul>" + strings.Join(examples, "") + "</ul>"
+	}
+
+	var lines []string
+	if usage.Register != "" {
+		lines = append(lines, "["+html.EscapeString(usage.Register)+"]")
+	}
+	if usage.Note != "" {
+		lines = append(lines, html.EscapeString(usage.Note))
+	}
+	related := []struct {
+		label string
+		words []string
+	}{
+		{"コロケーション", usage.Collocations},
+		{"類義語", usage.Synonyms},
+		{"対義語", usage.Antonyms},
+	}
+	for _, rel := range related {
+		if len(rel.words) > 0 {
+			lines = append(lines, rel.label+": "+html.EscapeString(strings.Join(rel.words, ", ")))
+		}
+	}
+	return []string{exampleField, strings.Join(lines, "<br>")}
+}
+
+// writeCollectionRow はcolテーブル（ノートタイプ・デッキ・設定）を作成
+func (e *AnkiExporter) writeCollectionRow(ctx context.Context, db *sql.DB, now time.Time, deckName string, deckID int64) error {
+	fields := make([]map[string]interface{}, len(ankiFields))
+	for i, name := range ankiFields {
+		fields[i] = map[string]interface{}{
+			"name": name, "ord": i, "sticky": false, "rtl": false,
+			"font": "Arial", "size": 20, "media": []string{},
+		}
+	}
+
+	modelsJSON := map[string]interface{}{
+		strconv.FormatInt(ankiModelID, 10): map[string]interface{}{
+			"id":    ankiModelID,
+			"name":  "Learn by Transcript",
+			"type":  0,
+			"mod":   now.Unix(),
+			"usn":   -1,
+			"sortf": 0,
+			"did":   deckID,
+			"tmpls": []map[string]interface{}{{
+				"name": "Expression → Meaning", "ord": 0,
+				"qfmt": ankiFrontTemplate, "afmt": ankiBackTemplate,
+				"did": nil, "bqfmt": "", "bafmt": "",
+			}},
+			"flds":      fields,
+			"css":       ankiCSS,
+			"latexPre":  "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n",
+			"latexPost": "\\end{document}",
+			"tags":      []string{},
+			"vers":      []string{},
+			"req":       []interface{}{[]interface{}{0, "all", []int{0}}},
+		},
+	}
+
+	deck := func(id int64, name string) map[string]interface{} {
+		return map[string]interface{}{
+			"id": id, "name": name, "desc": "", "mod": now.Unix(), "usn": -1,
+			"collapsed": false, "browserCollapsed": false, "dyn": 0, "conf": 1,
+			"extendNew": 10, "extendRev": 50,
+			"newToday": []int{0, 0}, "revToday": []int{0, 0}, "lrnToday": []int{0, 0}, "timeToday": []int{0, 0},
+		}
+	}
+	decksJSON := map[string]interface{}{
+		"1":                           deck(1, "Default"),
+		strconv.FormatInt(deckID, 10): deck(deckID, deckName),
+	}
+
+	confJSON := map[string]interface{}{
+		"activeDecks": []int64{1}, "addToCur": true, "collapseTime": 1200, "curDeck": 1,
+		"curModel": strconv.FormatInt(ankiModelID, 10), "dueCounts": true, "estTimes": true,
+		"newBury": true, "newSpread": 0, "nextPos": 1, "sortBackwards": false, "sortType": "noteFld", "timeLim": 0,
+	}
+
+	dconfJSON := map[string]interface{}{
+		"1": map[string]interface{}{
+			"id": 1, "name": "Default", "mod": 0, "usn": 0, "maxTaken": 60, "autoplay": true, "timer": 0, "replayq": true,
+			"new":   map[string]interface{}{"bury": true, "delays": []int{1, 10}, "initialFactor": 2500, "ints": []int{1, 4, 7}, "order": 1, "perDay": 20, "separate": true},
+			"rev":   map[string]interface{}{"bury": true, "ease4": 1.3, "fuzz": 0.05, "ivlFct": 1, "maxIvl": 36500, "minSpace": 1, "perDay": 100},
+			"lapse": map[string]interface{}{"delays": []int{10}, "leechAction": 0, "leechFails": 8, "minInt": 1, "mult": 0},
+		},
+	}
+
+	encoded := make([]string, 0, 4)
+	for _, v := range []interface{}{confJSON, modelsJSON, decksJSON, dconfJSON} {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to marshal collection config: %w", err)
+		}
+		encoded = append(encoded, string(b))
+	}
+
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO col (id, crt, mod, scm, ver, dty, usn, ls, conf, models, decks, dconf, tags)
+		VALUES (1, ?, ?, ?, 11, 0, 0, 0, ?, ?, ?, ?, '{}')
+	`, now.Truncate(24*time.Hour).Unix(), now.UnixMilli(), now.UnixMilli(), encoded[0], encoded[1], encoded[2], encoded[3])
+	if err != nil {
+		return fmt.Errorf("failed to insert collection: %w", err)
+	}
+
+	return nil
+}
+
+// writeAnkiPackage はコレクションとメディア一覧をzipにまとめて.apkgを作成
+func writeAnkiPackage(outputPath, collectionPath string) error {
+	collection, err := os.ReadFile(collectionPath)
+	if err != nil {
+		return fmt.Errorf("failed to read collection: %w", err)
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	zw := zip.NewWriter(file)
+	entries := []struct {
+		name string
+		data []byte
+	}{
+		{"collection.anki2", collection},
+		{"media", []byte("{}")}, // メディアファイルなし
+	}
+	for _, entry := range entries {
+		w, err := zw.Create(entry.name)
+		if err != nil {
+			return fmt.Errorf("failed to add %s: %w", entry.name, err)
+		}
+		if _, err := w.Write(entry.data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", entry.name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize package: %w", err)
+	}
+	return nil
+}
+
+// ankiGUID は表現IDから安定したノートGUIDを生成
+func ankiGUID(expressionID int) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("learn-by-transcript:expression:%d", expressionID)))
+	return "lbt" + hex.EncodeToString(sum[:])[:10]
+}
+
+// ankiDeckID はデッキ名から安定したデッキIDを生成
+func ankiDeckID(deckName string) int64 {
+	sum := sha1.Sum([]byte("learn-by-transcript:deck:" + deckName))
+	return int64(binary.BigEndian.Uint64(sum[:8])>>12) + 1 // 正の値かつJSONで安全に扱える範囲
+}
+
+// ankiModelIDFor はフィールドの一覧から安定したノートタイプIDを生成
+func ankiModelIDFor(fields []string) int64 {
+	sum := sha1.Sum([]byte("learn-by-transcript:model:" + strings.Join(fields, "\x1f")))
+	return int64(binary.BigEndian.Uint64(sum[:8])>>12) + 1 // 正の値かつJSONで安全に扱える範囲
+}
+
+// ankiChecksum はソートフィールドのチェックサム（SHA1の先頭8桁）
+func ankiChecksum(field string) int64 {
+	sum := sha1.Sum([]byte(field))
+	v, _ := strconv.ParseInt(hex.EncodeToString(sum[:])[:8], 16, 64)
+	return v
+}
+
+// ankiTags はカテゴリ・表現のタグ・会議名からタグを生成（Ankiのタグは空白区切り）
+func ankiTags(expr *models.Expression, occurrences []*models.ExpressionOccurrence) string {
+	tagSet := make(map[string]bool)
+	for _, c := range expr.Categories {
+		tagSet[sanitizeAnkiTag(c)] = true
+	}
+	for _, t := range expr.Tags {
+		tagSet["tag::"+sanitizeAnkiTag(t)] = true
+	}
+	for _, occ := range occurrences {
+		if occ.Meeting != "" {
+			tagSet["meeting::"+sanitizeAnkiTag(occ.Meeting)] = true
+		}
+	}
+	if len(tagSet) == 0 {
+		return ""
+	}
+
+	tags := make([]string, 0, len(tagSet))
+	for tag := range tagSet {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return " " + strings.Join(tags, " ") + " "
+}
+
+var ankiTagInvalidChars = regexp.MustCompile(`[\s"]+`)
+
+// sanitizeAnkiTag は空白などタグに使えない文字を_に置換
+func sanitizeAnkiTag(s string) string {
+	return ankiTagInvalidChars.ReplaceAllString(strings.TrimSpace(s), "_")
+}
+
+// highlightExpression はcontext内の表現を<b>で強調（大文字小文字を区別しない）
+func highlightExpression(context, expression string) string {
+	return highlightWith(context, expression, "<b>", "</b>")
+}
@@ -0,0 +1,159 @@
+package output
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+func newTestRepository(t *testing.T) *storage.SQLiteRepository {
+	t.Helper()
+	repo, err := storage.NewSQLiteRepository(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+
+	ctx := context.Background()
+	fixtures := []struct {
+		expr    models.Expression
+		context string
+		meeting string
+	}{
+		{models.Expression{Expression: "deprecate", Type: "word", Meaning: "非推奨にする", Priority: 5, Category: "engineering"}, "We need to deprecate the old endpoint.", "Team Sync"},
+		{models.Expression{Expression: "touch base", Type: "phrase", Meaning: "連絡を取る", Priority: 3, Category: "business"}, "I touched base with them yesterday.", "1on1"},
+	}
+	for _, f := range fixtures {
+		expr := f.expr
+		if err := repo.SaveExpression(ctx, &expr); err != nil {
+			t.Fatalf("SaveExpression: %v", err)
+		}
+		occ := &models.ExpressionOccurrence{ExpressionID: expr.ID, Context: f.context, Meeting: f.meeting}
+		if err := repo.AddOccurrence(ctx, occ); err != nil {
+			t.Fatalf("AddOccurrence: %v", err)
+		}
+	}
+	return repo
+}
+
+// readAnkiNotes は.apkgを展開してノートのGUID→(タグ, フィールド)を返す
+func readAnkiNotes(t *testing.T, apkgPath string) map[string][2]string {
+	t.Helper()
+	zr, err := zip.OpenReader(apkgPath)
+	if err != nil {
+		t.Fatalf("failed to open package: %v", err)
+	}
+	defer zr.Close()
+
+	collectionPath := filepath.Join(t.TempDir(), "collection.anki2")
+	for _, f := range zr.File {
+		if f.Name != "collection.anki2" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("failed to open collection: %v", err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("failed to read collection: %v", err)
+		}
+		if err := os.WriteFile(collectionPath, data, 0o644); err != nil {
+			t.Fatalf("failed to write collection: %v", err)
+		}
+	}
+
+	db, err := sql.Open("sqlite3", collectionPath)
+	if err != nil {
+		t.Fatalf("failed to open collection: %v", err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT guid, tags, flds FROM notes`)
+	if err != nil {
+		t.Fatalf("failed to query notes: %v", err)
+	}
+	defer rows.Close()
+
+	notes := make(map[string][2]string)
+	for rows.Next() {
+		var guid, tags, flds string
+		if err := rows.Scan(&guid, &tags, &flds); err != nil {
+			t.Fatalf("failed to scan note: %v", err)
+		}
+		notes[guid] = [2]string{tags, flds}
+	}
+	return notes
+}
+
+func TestAnkiExport(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	dir := t.TempDir()
+
+	first := filepath.Join(dir, "first.apkg")
+	second := filepath.Join(dir, "second.apkg")
+	for _, path := range []string{first, second} {
+		if err := NewAnkiExporter(repo, "Test Deck").Export(ctx, path, ExportOptions{IncludeContext: true}); err != nil {
+			t.Fatalf("Export: %v", err)
+		}
+	}
+
+	firstNotes := readAnkiNotes(t, first)
+	secondNotes := readAnkiNotes(t, second)
+	if len(firstNotes) != 2 {
+		t.Fatalf("expected 2 notes, got %d", len(firstNotes))
+	}
+
+	// 再エクスポートしても同じGUIDになる
+	for guid := range firstNotes {
+		if _, ok := secondNotes[guid]; !ok {
+			t.Errorf("GUID %s is not stable across exports", guid)
+		}
+	}
+
+	var found bool
+	for _, note := range firstNotes {
+		tags, flds := note[0], note[1]
+		fields := strings.Split(flds, "\x1f")
+		if len(fields) != len(ankiFields) {
+			t.Errorf("expected %d fields, got %d: %q", len(ankiFields), len(fields), flds)
+			continue
+		}
+		if fields[0] == "deprecate" {
+			found = true
+			if !strings.Contains(fields[2], "<b>deprecate</b>") {
+				t.Errorf("expression is not highlighted in context: %q", fields[2])
+			}
+			if !strings.Contains(tags, " engineering ") || !strings.Contains(tags, " meeting::Team_Sync ") {
+				t.Errorf("unexpected tags: %q", tags)
+			}
+		}
+	}
+	if !found {
+		t.Error("note for 'deprecate' not found")
+	}
+}
+
+// ノートタイプIDはフィールドが同じなら変わらず、フィールドを変えると別のIDになる
+func TestAnkiModelID(t *testing.T) {
+	if ankiModelIDFor(ankiFields) != ankiModelIDFor(append([]string{}, ankiFields...)) {
+		t.Error("model ID is not stable for the same fields")
+	}
+	previous := []string{"Expression", "Meaning", "Context", "Category", "Priority"}
+	if ankiModelIDFor(previous) == ankiModelID {
+		t.Error("model ID did not change with the field set")
+	}
+	if ankiModelID <= 0 || ankiModelID > 1<<53 {
+		t.Errorf("model ID %d is not a positive JSON-safe integer", ankiModelID)
+	}
+}
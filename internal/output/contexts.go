@@ -0,0 +1,110 @@
+package output
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+// contextOrders はExportOptions.ContextOrderに指定できる並び順
+var contextOrders = []string{"recent", "diverse"}
+
+// SelectContexts は出現履歴から重複を除いたcontextを最大max件選ぶ（max<=0なら全件）
+// order="recent" は新しい順、"diverse" は会議ごとに新しいものから1件ずつ順番に選び、多くの会議の例文を優先する
+func SelectContexts(occurrences []*models.ExpressionOccurrence, max int, order string) ([]*models.ExpressionOccurrence, error) {
+	// 新しい順に並べ、同じ文（大文字小文字・空白の違いを無視）は最新の1件だけ残す
+	sorted := make([]*models.ExpressionOccurrence, len(occurrences))
+	copy(sorted, occurrences)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].OccurredAt.Equal(sorted[j].OccurredAt) {
+			return sorted[i].OccurredAt.After(sorted[j].OccurredAt)
+		}
+		return sorted[i].ID > sorted[j].ID
+	})
+
+	seen := make(map[string]bool, len(sorted))
+	var unique []*models.ExpressionOccurrence
+	for _, occ := range sorted {
+		key := normalizeContext(occ.Context)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, occ)
+	}
+
+	switch order {
+	case "", "recent":
+	case "diverse":
+		unique = interleaveByMeeting(unique)
+	default:
+		return nil, fmt.Errorf("unknown context order: %s (%s)", order, strings.Join(contextOrders, ", "))
+	}
+
+	if max > 0 && len(unique) > max {
+		unique = unique[:max]
+	}
+	return unique, nil
+}
+
+// interleaveByMeeting は新しい順の出現履歴を会議ごとに1件ずつ順番に並べ替える（会議は最新の出現順）
+func interleaveByMeeting(occurrences []*models.ExpressionOccurrence) []*models.ExpressionOccurrence {
+	var meetings []string
+	byMeeting := make(map[string][]*models.ExpressionOccurrence)
+	for _, occ := range occurrences {
+		if _, ok := byMeeting[occ.Meeting]; !ok {
+			meetings = append(meetings, occ.Meeting)
+		}
+		byMeeting[occ.Meeting] = append(byMeeting[occ.Meeting], occ)
+	}
+
+	result := make([]*models.ExpressionOccurrence, 0, len(occurrences))
+	for round := 0; len(result) < len(occurrences); round++ {
+		for _, meeting := range meetings {
+			if round < len(byMeeting[meeting]) {
+				result = append(result, byMeeting[meeting][round])
+			}
+		}
+	}
+	return result
+}
+
+// normalizeContext は重複判定用にcontextを正規化
+func normalizeContext(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// loadContexts は表現の出現履歴から重複を除いたcontextを最大max件選ぶ（max<=0なら全件）
+func loadContexts(ctx context.Context, repo storage.Repository, expressionID, max int, order string) ([]*models.ExpressionOccurrence, error) {
+	occurrences, err := repo.GetOccurrences(ctx, expressionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get occurrences: %w", err)
+	}
+	return SelectContexts(occurrences, max, order)
+}
+
+// exportContexts はオプションに従って表現のcontextを取得（IncludeContextがfalseなら空）
+func exportContexts(ctx context.Context, repo storage.Repository, expressionID int, opts ExportOptions) ([]string, error) {
+	if !opts.IncludeContext {
+		return nil, nil
+	}
+
+	max := opts.MaxContexts
+	if max == 0 {
+		max = 1
+	}
+	selected, err := loadContexts(ctx, repo, expressionID, max, opts.ContextOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	contexts := make([]string, len(selected))
+	for i, occ := range selected {
+		contexts[i] = occ.Context
+	}
+	return contexts, nil
+}
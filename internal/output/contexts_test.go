@@ -0,0 +1,57 @@
+package output
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+func TestSelectContexts(t *testing.T) {
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	occurrences := []*models.ExpressionOccurrence{
+		{ID: 1, Context: "Let's circle back tomorrow.", Meeting: "standup", OccurredAt: base},
+		{ID: 2, Context: "We should circle back on pricing.", Meeting: "sales", OccurredAt: base.Add(1 * time.Hour)},
+		{ID: 3, Context: "Can we circle back after lunch?", Meeting: "standup", OccurredAt: base.Add(2 * time.Hour)},
+		{ID: 4, Context: "let's  circle back tomorrow.", Meeting: "standup", OccurredAt: base.Add(3 * time.Hour)},
+		{ID: 5, Context: "I'll circle back with legal.", Meeting: "standup", OccurredAt: base.Add(4 * time.Hour)},
+		{ID: 6, Context: "", Meeting: "retro", OccurredAt: base.Add(5 * time.Hour)},
+	}
+
+	ids := func(selected []*models.ExpressionOccurrence) []int {
+		result := make([]int, len(selected))
+		for i, occ := range selected {
+			result[i] = occ.ID
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		max      int
+		order    string
+		expected []int
+	}{
+		{"新しい順・重複除去", 0, "recent", []int{5, 4, 3, 2}},
+		{"件数制限", 2, "", []int{5, 4}},
+		{"会議の多様性優先", 0, "diverse", []int{5, 2, 4, 3}},
+		{"会議の多様性優先・件数制限", 2, "diverse", []int{5, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := SelectContexts(occurrences, tt.max, tt.order)
+			if err != nil {
+				t.Fatalf("SelectContexts: %v", err)
+			}
+			if got := ids(selected); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("SelectContexts(max=%d, order=%q) = %v, expected %v", tt.max, tt.order, got, tt.expected)
+			}
+		})
+	}
+
+	if _, err := SelectContexts(occurrences, 0, "random"); err == nil {
+		t.Error("expected error for unknown order")
+	}
+}
@@ -5,11 +5,18 @@ import (
 	"encoding/csv"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/mamyudapao/learn-by-transcript/internal/models"
 	"github.com/mamyudapao/learn-by-transcript/internal/storage"
 )
 
+func init() {
+	Register("csv", []string{".csv"}, func(repo storage.Repository) Exporter {
+		return NewCSVExporter(repo)
+	})
+}
+
 // CSVExporter はCSVファイルに出力する
 type CSVExporter struct {
 	repository storage.Repository
@@ -22,12 +29,11 @@ func NewCSVExporter(repo storage.Repository) *CSVExporter {
 	}
 }
 
-// Export はデータベースの表現をCSVファイルに出力
-func (e *CSVExporter) Export(ctx context.Context, outputPath string) error {
-	// データベースからすべての表現を取得
-	expressions, err := e.repository.ListExpressions(ctx)
+// Export はフィルタリング・並び替えを適用してCSV出力
+func (e *CSVExporter) Export(ctx context.Context, outputPath string, opts ExportOptions) error {
+	expressions, err := SelectExpressions(ctx, e.repository, opts)
 	if err != nil {
-		return fmt.Errorf("failed to list expressions: %w", err)
+		return err
 	}
 
 	// CSVファイルを作成
@@ -48,101 +54,41 @@ func (e *CSVExporter) Export(ctx context.Context, outputPath string) error {
 		"Priority",
 		"Category",
 		"OccurrenceCount",
-		"Context",
+	}
+	if opts.IncludeContext {
+		header = append(header, "Context")
+	}
+	// 後から追加した列は既存の列の位置を変えないよう末尾に追加
+	header = append(header,
 		"FirstSeenAt",
 		"LastSeenAt",
-	}
+		"Categories",
+		"Tags",
+		"Senses",
+		"Examples",
+		"Register",
+		"UsageNote",
+		"Collocations",
+		"Synonyms",
+		"Antonyms",
+	)
 	if err := writer.Write(header); err != nil {
 		return fmt.Errorf("failed to write header: %w", err)
 	}
 
 	// データ行
 	for _, expr := range expressions {
-		// 最新のcontextを取得
-		context := ""
-		occurrences, err := e.repository.GetOccurrences(ctx, expr.ID)
-		if err == nil && len(occurrences) > 0 {
-			// 最新のoccurrenceのcontextを使用
-			context = occurrences[len(occurrences)-1].Context
-		}
-
-		row := []string{
-			expr.Expression,
-			expr.Type,
-			expr.Meaning,
-			fmt.Sprintf("%d", expr.Priority),
-			expr.Category,
-			fmt.Sprintf("%d", expr.OccurrenceCount),
-			context,
-			expr.FirstSeenAt.Format("2006-01-02 15:04:05"),
-			expr.LastSeenAt.Format("2006-01-02 15:04:05"),
+		usage, err := e.repository.GetUsage(ctx, expr.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get usage: %w", err)
 		}
-		if err := writer.Write(row); err != nil {
-			return fmt.Errorf("failed to write row: %w", err)
-		}
-	}
-
-	return nil
-}
-
-// ExportWithOptions はオプション付きでCSVに出力
-type ExportOptions struct {
-	MinPriority    int    // 最小優先度（フィルタリング）
-	Category       string // カテゴリでフィルタ（空文字列ならすべて）
-	SortBy         string // "priority", "occurrence", "expression"
-	IncludeContext bool   // contextを含めるか
-}
-
-// ExportWithFilter はフィルタリング・並び替えを適用してCSV出力
-func (e *CSVExporter) ExportWithFilter(ctx context.Context, outputPath string, opts ExportOptions) error {
-	// データベースからすべての表現を取得
-	allExpressions, err := e.repository.ListExpressions(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to list expressions: %w", err)
-	}
-
-	// フィルタリング
-	var expressions []*models.Expression
-	for _, expr := range allExpressions {
-		// 優先度フィルタ
-		if opts.MinPriority > 0 && expr.Priority < opts.MinPriority {
-			continue
+		if usage == nil {
+			usage = &models.Usage{}
 		}
-		// カテゴリフィルタ
-		if opts.Category != "" && expr.Category != opts.Category {
-			continue
+		senses, err := e.repository.ListSenses(ctx, expr.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list senses: %w", err)
 		}
-		expressions = append(expressions, expr)
-	}
-
-	// CSVファイルを作成
-	file, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
-	defer file.Close()
-
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
-
-	// ヘッダー行
-	header := []string{
-		"Expression",
-		"Type",
-		"Meaning",
-		"Priority",
-		"Category",
-		"OccurrenceCount",
-	}
-	if opts.IncludeContext {
-		header = append(header, "Context")
-	}
-	if err := writer.Write(header); err != nil {
-		return fmt.Errorf("failed to write header: %w", err)
-	}
-
-	// データ行
-	for _, expr := range expressions {
 		row := []string{
 			expr.Expression,
 			expr.Type,
@@ -153,14 +99,28 @@ func (e *CSVExporter) ExportWithFilter(ctx context.Context, outputPath string, o
 		}
 
 		if opts.IncludeContext {
-			context := ""
-			occurrences, err := e.repository.GetOccurrences(ctx, expr.ID)
-			if err == nil && len(occurrences) > 0 {
-				context = occurrences[len(occurrences)-1].Context
+			// 複数のcontextはセル内で改行区切り
+			contexts, err := exportContexts(ctx, e.repository, expr.ID, opts)
+			if err != nil {
+				return err
 			}
-			row = append(row, context)
+			row = append(row, strings.Join(contexts, "\n"))
 		}
 
+		row = append(row,
+			expr.FirstSeenAt.Format("2006-01-02 15:04:05"),
+			expr.LastSeenAt.Format("2006-01-02 15:04:05"),
+			strings.Join(expr.Categories, ", "),
+			strings.Join(expr.Tags, ", "),
+			csvSenses(senses),
+			csvExamples(usage.Examples),
+			usage.Register,
+			usage.Note,
+			strings.Join(usage.Collocations, ", "),
+			strings.Join(usage.Synonyms, ", "),
+			strings.Join(usage.Antonyms, ", "),
+		)
+
 		if err := writer.Write(row); err != nil {
 			return fmt.Errorf("failed to write row: %w", err)
 		}
@@ -168,3 +128,24 @@ func (e *CSVExporter) ExportWithFilter(ctx context.Context, outputPath string, o
 
 	return nil
 }
+
+// csvSenses は語義をセル内で改行区切りにする
+func csvSenses(senses []*models.Sense) string {
+	lines := make([]string, len(senses))
+	for i, s := range senses {
+		lines[i] = s.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// csvExamples は例文（訳があれば " / " の後に続ける）をセル内で改行区切りにする
+func csvExamples(examples []models.Example) string {
+	lines := make([]string, len(examples))
+	for i, e := range examples {
+		lines[i] = e.Sentence
+		if e.Translation != "" {
+			lines[i] += " / " + e.Translation
+		}
+	}
+	return strings.Join(lines, "\n")
+}
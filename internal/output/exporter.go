@@ -0,0 +1,233 @@
+package output
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+// Exporter は表現を出力する共通インターフェース
+type Exporter interface {
+	// Export はフィルタ・並び替えを適用した表現をoutputPathに出力
+	Export(ctx context.Context, outputPath string, opts ExportOptions) error
+}
+
+// ExportOptions は出力時のフィルタリング・並び替えオプション
+type ExportOptions struct {
+	MinPriority    int       // 最小優先度（フィルタリング）
+	Category       string    // カテゴリでフィルタ（主カテゴリ以外も一致。空文字列ならすべて）
+	Tags           []string  // すべてのタグがついた表現のみ（空ならすべて）
+	SortBy         string    // "priority", "occurrence", "expression", "recent"
+	IncludeContext bool      // contextを含めるか
+	DeckName       string    // Ankiのデッキ名（.apkgのみ）
+	Since          time.Time // この日時以降に登録・更新された表現のみ（ゼロ値ならすべて）
+	MaxContexts    int       // 出力するcontextの最大数（0なら1件、負数なら全件）
+	ContextOrder   string    // contextの並び順 "recent"（新しい順）, "diverse"（会議の多様性優先）
+	TemplatePath   string    // テンプレートファイル（templateのみ）
+}
+
+// Factory はExporterを生成する関数
+type Factory func(repo storage.Repository) Exporter
+
+// format は登録済みの出力形式
+type format struct {
+	name       string
+	extensions []string
+	factory    Factory
+}
+
+// registry は形式名→出力形式
+var registry = make(map[string]*format)
+
+// Register は出力形式を登録（extensionsはファイル拡張子からの自動判定に使用）
+func Register(name string, extensions []string, factory Factory) {
+	registry[name] = &format{name: name, extensions: extensions, factory: factory}
+}
+
+// New は形式名に対応するExporterを生成
+func New(name string, repo storage.Repository) (Exporter, error) {
+	f, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown export format: %s (available: %s)", name, strings.Join(Formats(), ", "))
+	}
+	return f.factory(repo), nil
+}
+
+// FormatForPath はファイル拡張子から形式名を判定
+func FormatForPath(path string) (string, bool) {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, f := range registry {
+		for _, e := range f.extensions {
+			if e == ext {
+				return f.name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Formats は登録済みの形式名一覧
+func Formats() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SelectExpressions はオプションに従って表現を取得・フィルタリング・並び替え（各Exporterの出力対象）
+func SelectExpressions(ctx context.Context, repo storage.Repository, opts ExportOptions) ([]*models.Expression, error) {
+	if _, err := SelectContexts(nil, 0, opts.ContextOrder); err != nil {
+		return nil, err
+	}
+
+	// データベースからすべての表現を取得（優先度・出現回数順）
+	allExpressions, err := repo.ListExpressions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expressions: %w", err)
+	}
+
+	// フィルタリング
+	var expressions []*models.Expression
+	for _, expr := range allExpressions {
+		// 優先度フィルタ
+		if opts.MinPriority > 0 && expr.Priority < opts.MinPriority {
+			continue
+		}
+		// カテゴリフィルタ
+		if opts.Category != "" && !expr.HasCategory(opts.Category) {
+			continue
+		}
+		// タグフィルタ
+		if !expr.HasTags(opts.Tags...) {
+			continue
+		}
+		// 差分フィルタ（登録日時または更新日時がSince以降）
+		if !opts.Since.IsZero() && expr.FirstSeenAt.Before(opts.Since) && expr.UpdatedAt.Before(opts.Since) {
+			continue
+		}
+		expressions = append(expressions, expr)
+	}
+
+	// 並び替え（priorityはListExpressionsの順序のまま）
+	switch opts.SortBy {
+	case "", "priority":
+	case "occurrence":
+		sort.SliceStable(expressions, func(i, j int) bool {
+			return expressions[i].OccurrenceCount > expressions[j].OccurrenceCount
+		})
+	case "expression":
+		sort.SliceStable(expressions, func(i, j int) bool {
+			return strings.ToLower(expressions[i].Expression) < strings.ToLower(expressions[j].Expression)
+		})
+	case "recent":
+		sort.SliceStable(expressions, func(i, j int) bool {
+			return expressions[i].LastSeenAt.After(expressions[j].LastSeenAt)
+		})
+	default:
+		return nil, fmt.Errorf("unknown sort key: %s (priority, occurrence, expression, recent)", opts.SortBy)
+	}
+
+	return expressions, nil
+}
+
+// record はJSON/JSONL/Markdown出力用の1表現分のデータ
+type record struct {
+	Expression      string        `json:"expression"`
+	Type            string        `json:"type"`
+	Meaning         string        `json:"meaning"`
+	Priority        int           `json:"priority"`
+	Category        string        `json:"category"`
+	Categories      []string      `json:"categories"`
+	Tags            []string      `json:"tags"`
+	Senses          []senseRecord `json:"senses,omitempty"` // 登録順
+	Usage           *usageRecord  `json:"usage,omitempty"`  // enrichで生成した使い方
+	OccurrenceCount int           `json:"occurrence_count"`
+	Context         string        `json:"context,omitempty"`
+	Contexts        []string      `json:"contexts,omitempty"`
+	FirstSeenAt     time.Time     `json:"first_seen_at"`
+	LastSeenAt      time.Time     `json:"last_seen_at"`
+}
+
+// senseRecord は出力用の語義
+type senseRecord struct {
+	PartOfSpeech    string `json:"part_of_speech"`
+	Gloss           string `json:"gloss"`
+	Definition      string `json:"definition"`
+	OccurrenceCount int    `json:"occurrence_count"`
+}
+
+// usageRecord は出力用の使い方（例文・使用上の注意・関連語）
+type usageRecord struct {
+	Examples     []models.Example `json:"examples"`
+	Register     string           `json:"register,omitempty"`
+	Note         string           `json:"note,omitempty"`
+	Collocations []string         `json:"collocations"`
+	Synonyms     []string         `json:"synonyms"`
+	Antonyms     []string         `json:"antonyms"`
+}
+
+// buildRecords は表現を出力用データに変換
+func buildRecords(ctx context.Context, repo storage.Repository, expressions []*models.Expression, opts ExportOptions) ([]record, error) {
+	records := make([]record, 0, len(expressions))
+	for _, expr := range expressions {
+		r := record{
+			Expression:      expr.Expression,
+			Type:            expr.Type,
+			Meaning:         expr.Meaning,
+			Priority:        expr.Priority,
+			Category:        expr.Category,
+			Categories:      append([]string{}, expr.Categories...),
+			Tags:            append([]string{}, expr.Tags...),
+			OccurrenceCount: expr.OccurrenceCount,
+			FirstSeenAt:     expr.FirstSeenAt,
+			LastSeenAt:      expr.LastSeenAt,
+		}
+		senses, err := repo.ListSenses(ctx, expr.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list senses: %w", err)
+		}
+		for _, s := range senses {
+			r.Senses = append(r.Senses, senseRecord{
+				PartOfSpeech:    s.PartOfSpeech,
+				Gloss:           s.Gloss,
+				Definition:      s.Definition,
+				OccurrenceCount: s.OccurrenceCount,
+			})
+		}
+		u, err := repo.GetUsage(ctx, expr.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get usage: %w", err)
+		}
+		if u != nil {
+			r.Usage = &usageRecord{
+				Examples:     append([]models.Example{}, u.Examples...),
+				Register:     u.Register,
+				Note:         u.Note,
+				Collocations: append([]string{}, u.Collocations...),
+				Synonyms:     append([]string{}, u.Synonyms...),
+				Antonyms:     append([]string{}, u.Antonyms...),
+			}
+		}
+		contexts, err := exportContexts(ctx, repo, expr.ID, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(contexts) > 0 {
+			r.Context = contexts[0]
+			// 複数件を指定した場合のみ一覧も出力
+			if opts.MaxContexts != 0 && opts.MaxContexts != 1 {
+				r.Contexts = contexts
+			}
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
@@ -0,0 +1,246 @@
+package output
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+func TestFormatForPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+		ok       bool
+	}{
+		{"out.csv", "csv", true},
+		{"out.JSON", "json", true},
+		{"out.jsonl", "jsonl", true},
+		{"notes.md", "markdown", true},
+		{"deck.apkg", "anki", true},
+		{"out.txt", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := FormatForPath(tt.path)
+		if got != tt.expected || ok != tt.ok {
+			t.Errorf("FormatForPath(%q) = (%q, %v), expected (%q, %v)", tt.path, got, ok, tt.expected, tt.ok)
+		}
+	}
+
+	if _, err := New("xlsx", nil); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestJSONLinesExportWithFilter(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	path := filepath.Join(t.TempDir(), "out.jsonl")
+
+	expr, err := repo.GetExpression(ctx, "deprecate")
+	if err != nil {
+		t.Fatalf("GetExpression: %v", err)
+	}
+	usage := &models.Usage{
+		ExpressionID: expr.ID,
+		Examples:     []models.Example{{Sentence: "We deprecated the v1 API.", Translation: "v1 APIを非推奨にした。"}},
+		Register:     models.RegisterFormal,
+		Synonyms:     []string{"phase out"},
+	}
+	if err := repo.SaveUsage(ctx, usage); err != nil {
+		t.Fatalf("SaveUsage: %v", err)
+	}
+
+	exporter, err := New("jsonl", repo)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	opts := ExportOptions{MinPriority: 4, IncludeContext: true}
+	if err := exporter.Export(ctx, path, opts); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open output: %v", err)
+	}
+	defer file.Close()
+
+	var records []record
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var r record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", scanner.Text(), err)
+		}
+		records = append(records, r)
+	}
+
+	if len(records) != 1 || records[0].Expression != "deprecate" {
+		t.Fatalf("expected only 'deprecate' (priority >= 4), got %+v", records)
+	}
+	if records[0].Context == "" {
+		t.Error("context should be included")
+	}
+	if u := records[0].Usage; u == nil || len(u.Examples) != 1 || u.Register != models.RegisterFormal || len(u.Synonyms) != 1 {
+		t.Errorf("usage should be included: %+v", u)
+	}
+}
+
+// 既存の列の位置は変えず、後から追加した列は末尾に並ぶ（列の位置で読み込むスプレッドシートを壊さないため）
+func TestCSVExportHeader(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	path := filepath.Join(t.TempDir(), "out.csv")
+
+	exporter, err := New("csv", repo)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := exporter.Export(ctx, path, ExportOptions{IncludeContext: true}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open output: %v", err)
+	}
+	defer file.Close()
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+
+	expected := []string{
+		"Expression", "Type", "Meaning", "Priority", "Category", "OccurrenceCount", "Context", "FirstSeenAt", "LastSeenAt",
+		"Categories", "Tags", "Senses", "Examples", "Register", "UsageNote", "Collocations", "Synonyms", "Antonyms",
+	}
+	if !reflect.DeepEqual(rows[0], expected) {
+		t.Errorf("header = %v, expected %v", rows[0], expected)
+	}
+	if len(rows) != 3 || rows[1][0] != "deprecate" || rows[1][5] != "1" {
+		t.Errorf("unexpected rows: %v", rows[1:])
+	}
+}
+
+// failingRepository は語義・使い方・出現履歴の取得に失敗するリポジトリ（nilのエラーは取得に成功させる）
+type failingRepository struct {
+	storage.Repository
+	sensesErr      error
+	usageErr       error
+	occurrencesErr error
+}
+
+func (r *failingRepository) GetOccurrences(ctx context.Context, expressionID int) ([]*models.ExpressionOccurrence, error) {
+	if r.occurrencesErr != nil {
+		return nil, r.occurrencesErr
+	}
+	return r.Repository.GetOccurrences(ctx, expressionID)
+}
+
+func (r *failingRepository) ListSenses(ctx context.Context, expressionID int) ([]*models.Sense, error) {
+	if r.sensesErr != nil {
+		return nil, r.sensesErr
+	}
+	return r.Repository.ListSenses(ctx, expressionID)
+}
+
+func (r *failingRepository) GetUsage(ctx context.Context, expressionID int) (*models.Usage, error) {
+	if r.usageErr != nil {
+		return nil, r.usageErr
+	}
+	return r.Repository.GetUsage(ctx, expressionID)
+}
+
+// 語義・使い方・contextを取得できない場合は、それらが欠けた出力を作らずにエラーを返す
+func TestExportPropagatesRepositoryErrors(t *testing.T) {
+	ctx := context.Background()
+	lockErr := errors.New("database is locked")
+	repos := map[string]*failingRepository{
+		"senses":      {Repository: newTestRepository(t), sensesErr: lockErr},
+		"usage":       {Repository: newTestRepository(t), usageErr: lockErr},
+		"occurrences": {Repository: newTestRepository(t), occurrencesErr: lockErr},
+	}
+
+	for name, repo := range repos {
+		formats := []string{"json", "jsonl", "markdown", "csv"}
+		if name != "senses" {
+			formats = append(formats, "anki")
+		}
+		for _, format := range formats {
+			exporter, err := New(format, repo)
+			if err != nil {
+				t.Fatalf("New(%s): %v", format, err)
+			}
+			err = exporter.Export(ctx, filepath.Join(t.TempDir(), "out."+format), ExportOptions{IncludeContext: true})
+			if !errors.Is(err, lockErr) {
+				t.Errorf("%s export with failing %s: error = %v, expected %v", format, name, err, lockErr)
+			}
+		}
+	}
+}
+
+func TestSelectExpressionsSort(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	expressions, err := SelectExpressions(ctx, repo, ExportOptions{SortBy: "expression"})
+	if err != nil {
+		t.Fatalf("SelectExpressions: %v", err)
+	}
+	if len(expressions) != 2 || expressions[0].Expression != "deprecate" || expressions[1].Expression != "touch base" {
+		t.Errorf("unexpected order: %v, %v", expressions[0].Expression, expressions[1].Expression)
+	}
+
+	if _, err := SelectExpressions(ctx, repo, ExportOptions{SortBy: "random"}); err == nil {
+		t.Error("expected error for unknown sort key")
+	}
+}
+
+func TestSelectExpressionsSince(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	all, err := SelectExpressions(ctx, repo, ExportOptions{})
+	if err != nil {
+		t.Fatalf("SelectExpressions: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("expected 2 expressions, got %d", len(all))
+	}
+
+	future := time.Now().Add(time.Hour)
+	since, err := SelectExpressions(ctx, repo, ExportOptions{Since: future})
+	if err != nil {
+		t.Fatalf("SelectExpressions: %v", err)
+	}
+	if len(since) != 0 {
+		t.Errorf("expected no expressions since %v, got %d", future, len(since))
+	}
+
+	// チェックポイントは出力先ごとに記録される
+	if _, ok, err := repo.GetExportCheckpoint(ctx, "csv:/tmp/a.csv"); err != nil || ok {
+		t.Fatalf("expected no checkpoint, got ok=%v err=%v", ok, err)
+	}
+	exportedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := repo.SaveExportCheckpoint(ctx, "csv:/tmp/a.csv", exportedAt); err != nil {
+		t.Fatalf("SaveExportCheckpoint: %v", err)
+	}
+	got, ok, err := repo.GetExportCheckpoint(ctx, "csv:/tmp/a.csv")
+	if err != nil || !ok || !got.Equal(exportedAt) {
+		t.Errorf("GetExportCheckpoint = %v, %v, %v; expected %v", got, ok, err, exportedAt)
+	}
+	if _, ok, _ := repo.GetExportCheckpoint(ctx, "json:/tmp/a.json"); ok {
+		t.Error("checkpoint should not be shared between destinations")
+	}
+}
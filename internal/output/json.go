@@ -0,0 +1,67 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+func init() {
+	Register("json", []string{".json"}, func(repo storage.Repository) Exporter {
+		return NewJSONExporter(repo, false)
+	})
+	Register("jsonl", []string{".jsonl", ".ndjson"}, func(repo storage.Repository) Exporter {
+		return NewJSONExporter(repo, true)
+	})
+}
+
+// JSONExporter はJSON配列またはJSON Lines（1行1表現）で出力する
+type JSONExporter struct {
+	repository storage.Repository
+	lines      bool // trueならJSON Lines
+}
+
+// NewJSONExporter は新しいJSONExporterを作成
+func NewJSONExporter(repo storage.Repository, lines bool) *JSONExporter {
+	return &JSONExporter{
+		repository: repo,
+		lines:      lines,
+	}
+}
+
+// Export はフィルタリング・並び替えを適用してJSON/JSON Lines出力
+func (e *JSONExporter) Export(ctx context.Context, outputPath string, opts ExportOptions) error {
+	expressions, err := SelectExpressions(ctx, e.repository, opts)
+	if err != nil {
+		return err
+	}
+	records, err := buildRecords(ctx, e.repository, expressions, opts)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	if e.lines {
+		for _, r := range records {
+			if err := encoder.Encode(r); err != nil {
+				return fmt.Errorf("failed to write record: %w", err)
+			}
+		}
+		return nil
+	}
+
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(records); err != nil {
+		return fmt.Errorf("failed to write records: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package output
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+func init() {
+	Register("markdown", []string{".md", ".markdown"}, func(repo storage.Repository) Exporter {
+		return NewMarkdownExporter(repo)
+	})
+}
+
+// MarkdownExporter はMarkdownの表形式で出力する
+type MarkdownExporter struct {
+	repository storage.Repository
+}
+
+// NewMarkdownExporter は新しいMarkdownExporterを作成
+func NewMarkdownExporter(repo storage.Repository) *MarkdownExporter {
+	return &MarkdownExporter{
+		repository: repo,
+	}
+}
+
+// Export はフィルタリング・並び替えを適用してMarkdownの表を出力
+func (e *MarkdownExporter) Export(ctx context.Context, outputPath string, opts ExportOptions) error {
+	expressions, err := SelectExpressions(ctx, e.repository, opts)
+	if err != nil {
+		return err
+	}
+	records, err := buildRecords(ctx, e.repository, expressions, opts)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+
+	header := []string{"Expression", "Meaning", "Type", "Priority", "Categories", "Occurrences"}
+	if opts.IncludeContext {
+		header = append(header, "Context")
+	}
+	writeMarkdownRow(w, header)
+	separator := make([]string, len(header))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	writeMarkdownRow(w, separator)
+
+	for _, r := range records {
+		row := []string{
+			"**" + r.Expression + "**",
+			r.Meaning,
+			r.Type,
+			fmt.Sprintf("%d", r.Priority),
+			strings.Join(r.Categories, ", "),
+			fmt.Sprintf("%d", r.OccurrenceCount),
+		}
+		if opts.IncludeContext {
+			contexts := r.Contexts
+			if len(contexts) == 0 && r.Context != "" {
+				contexts = []string{r.Context}
+			}
+			row = append(row, strings.Join(contexts, "<br>"))
+		}
+		writeMarkdownRow(w, row)
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write markdown: %w", err)
+	}
+	return nil
+}
+
+// writeMarkdownRow は表の1行を出力（セル内の|と改行はエスケープ）
+func writeMarkdownRow(w *bufio.Writer, cells []string) {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		cell = strings.ReplaceAll(cell, "|", `\|`)
+		escaped[i] = strings.Join(strings.Fields(cell), " ")
+	}
+	fmt.Fprintf(w, "| %s |\n", strings.Join(escaped, " | "))
+}
@@ -0,0 +1,149 @@
+package output
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/notion"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+// Notionデータベースのプロパティ名（データベース側に同名・同型のプロパティを用意する）
+const (
+	NotionPropExpression  = "Expression"  // title
+	NotionPropMeaning     = "Meaning"     // rich_text
+	NotionPropType        = "Type"        // select
+	NotionPropCategory    = "Category"    // select
+	NotionPropPriority    = "Priority"    // number
+	NotionPropOccurrences = "Occurrences" // number
+	NotionPropContext     = "Context"     // rich_text
+	NotionPropLastSeen    = "Last Seen"   // date
+)
+
+// NotionExporter は表現をNotionデータベースに同期（upsert）する
+type NotionExporter struct {
+	repo       storage.Repository
+	client     *notion.Client
+	databaseID string
+}
+
+// NotionSyncResult は同期結果
+type NotionSyncResult struct {
+	Created int
+	Updated int
+}
+
+// NewNotionExporter は新しいNotionExporterを作成
+func NewNotionExporter(repo storage.Repository, client *notion.Client, databaseID string) *NotionExporter {
+	return &NotionExporter{
+		repo:       repo,
+		client:     client,
+		databaseID: databaseID,
+	}
+}
+
+// Export はNotionデータベースに同期（outputPathは使用しない）
+func (e *NotionExporter) Export(ctx context.Context, outputPath string, opts ExportOptions) error {
+	_, err := e.Sync(ctx, opts)
+	return err
+}
+
+// Sync は表現をNotionデータベースにupsertする
+// 記録済みのページIDがあればそのページを更新し、なければ同じ表現のページ、それもなければ新規作成する
+func (e *NotionExporter) Sync(ctx context.Context, opts ExportOptions) (*NotionSyncResult, error) {
+	expressions, err := SelectExpressions(ctx, e.repo, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := e.repo.GetNotionPages(ctx, e.databaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	// データベース上の既存ページ（アーカイブ済みは含まれない）
+	pages, err := e.client.QueryAllPages(ctx, e.databaseID)
+	if err != nil {
+		return nil, err
+	}
+	live := make(map[string]bool, len(pages))
+	byTitle := make(map[string]string, len(pages))
+	for _, page := range pages {
+		live[page.ID] = true
+		title := strings.ToLower(page.Properties[NotionPropExpression].PlainText())
+		if _, ok := byTitle[title]; !ok {
+			byTitle[title] = page.ID
+		}
+	}
+
+	// 他の表現に対応付け済みのページは表現名で一致しても使わない
+	claimed := make(map[string]bool, len(stored))
+	for _, pageID := range stored {
+		if live[pageID] {
+			claimed[pageID] = true
+		}
+	}
+
+	result := &NotionSyncResult{}
+	for _, expr := range expressions {
+		properties, err := e.properties(ctx, expr, opts)
+		if err != nil {
+			return result, err
+		}
+
+		pageID := stored[expr.ID]
+		if !live[pageID] {
+			pageID = ""
+			if id, ok := byTitle[strings.ToLower(expr.Expression)]; ok && !claimed[id] {
+				pageID = id
+			}
+		}
+
+		if pageID != "" {
+			if _, err := e.client.UpdatePage(ctx, pageID, properties); err != nil {
+				return result, fmt.Errorf("failed to sync '%s': %w", expr.Expression, err)
+			}
+			result.Updated++
+		} else {
+			page, err := e.client.CreatePage(ctx, e.databaseID, properties)
+			if err != nil {
+				return result, fmt.Errorf("failed to sync '%s': %w", expr.Expression, err)
+			}
+			pageID = page.ID
+			result.Created++
+		}
+		claimed[pageID] = true
+
+		if err := e.repo.SaveNotionPage(ctx, e.databaseID, expr.ID, pageID); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// properties は表現をNotionのプロパティに変換
+func (e *NotionExporter) properties(ctx context.Context, expr *models.Expression, opts ExportOptions) (map[string]notion.Property, error) {
+	properties := map[string]notion.Property{
+		NotionPropExpression:  notion.TitleProperty(expr.Expression),
+		NotionPropMeaning:     notion.TextProperty(expr.Meaning),
+		NotionPropPriority:    notion.NumberProperty(float64(expr.Priority)),
+		NotionPropOccurrences: notion.NumberProperty(float64(expr.OccurrenceCount)),
+		// 空ならselect: nullを送り、Notion上の選択を解除する
+		NotionPropType:     notion.SelectProperty(expr.Type),
+		NotionPropCategory: notion.SelectProperty(expr.Category),
+	}
+	if !expr.LastSeenAt.IsZero() {
+		properties[NotionPropLastSeen] = notion.DateProperty(expr.LastSeenAt)
+	}
+	if opts.IncludeContext {
+		contexts, err := exportContexts(ctx, e.repo, expr.ID, opts)
+		if err != nil {
+			return nil, err
+		}
+		properties[NotionPropContext] = notion.TextProperty(strings.Join(contexts, "\n"))
+	}
+	return properties, nil
+}
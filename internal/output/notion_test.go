@@ -0,0 +1,157 @@
+package output
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/notion"
+	"github.com/mamyudapao/learn-by-transcript/internal/notion/notiontest"
+)
+
+const testNotionDatabaseID = "expressions-db"
+
+func newTestNotionExporter(t *testing.T) (*NotionExporter, *notiontest.Server) {
+	t.Helper()
+	server := notiontest.NewServer()
+	t.Cleanup(server.Close)
+
+	client, err := notion.NewClient(notiontest.APIKey, server.BaseURL())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return NewNotionExporter(newTestRepository(t), client, testNotionDatabaseID), server
+}
+
+// notionTitles はデータベースのページを表現名→ページに変換
+func notionTitles(server *notiontest.Server) map[string]notion.Page {
+	pages := make(map[string]notion.Page)
+	for _, page := range server.Pages(testNotionDatabaseID) {
+		pages[page.Properties[NotionPropExpression].PlainText()] = page
+	}
+	return pages
+}
+
+func TestNotionSyncUpserts(t *testing.T) {
+	ctx := context.Background()
+	exporter, server := newTestNotionExporter(t)
+	server.MaxPageSize = 1 // ページネーションを通す
+
+	result, err := exporter.Sync(ctx, ExportOptions{IncludeContext: true})
+	if err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+	if result.Created != 2 || result.Updated != 0 {
+		t.Errorf("first sync = %+v, expected 2 created", result)
+	}
+
+	pages := notionTitles(server)
+	deprecate, ok := pages["deprecate"]
+	if !ok {
+		t.Fatalf("page for 'deprecate' not found: %v", pages)
+	}
+	if got := deprecate.Properties[NotionPropMeaning].PlainText(); got != "非推奨にする" {
+		t.Errorf("Meaning = %q", got)
+	}
+	if got := deprecate.Properties[NotionPropContext].PlainText(); got != "We need to deprecate the old endpoint." {
+		t.Errorf("Context = %q", got)
+	}
+	if p := deprecate.Properties[NotionPropPriority].Number; p == nil || *p != 5 {
+		t.Errorf("Priority = %v, expected 5", p)
+	}
+
+	// 2回目は記録済みのページIDで更新され、重複しない（レート制限もリトライで吸収）
+	server.RateLimitNext(1)
+	result, err = exporter.Sync(ctx, ExportOptions{})
+	if err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	if result.Created != 0 || result.Updated != 2 {
+		t.Errorf("second sync = %+v, expected 2 updated", result)
+	}
+	if n := len(server.Pages(testNotionDatabaseID)); n != 2 {
+		t.Errorf("expected 2 pages after re-sync, got %d", n)
+	}
+	if id := notionTitles(server)["deprecate"].ID; id != deprecate.ID {
+		t.Errorf("page ID changed on re-sync: %s -> %s", deprecate.ID, id)
+	}
+}
+
+func TestNotionSyncRecreatesArchivedAndAdoptsExisting(t *testing.T) {
+	ctx := context.Background()
+	exporter, server := newTestNotionExporter(t)
+
+	// 手動で登録済みの行は表現名で対応付ける
+	existingID := server.AddPage(testNotionDatabaseID, map[string]notion.Property{
+		NotionPropExpression: notion.TitleProperty("Touch Base"),
+	})
+
+	result, err := exporter.Sync(ctx, ExportOptions{})
+	if err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+	if result.Created != 1 || result.Updated != 1 {
+		t.Errorf("first sync = %+v, expected 1 created and 1 updated", result)
+	}
+	if id := notionTitles(server)["touch base"].ID; id != existingID {
+		t.Errorf("expected existing page %s to be adopted, got %s", existingID, id)
+	}
+
+	// Notion上でアーカイブされたページは作り直す
+	archivedID := notionTitles(server)["deprecate"].ID
+	server.ArchivePage(archivedID)
+
+	result, err = exporter.Sync(ctx, ExportOptions{})
+	if err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	if result.Created != 1 || result.Updated != 1 {
+		t.Errorf("second sync = %+v, expected 1 created and 1 updated", result)
+	}
+	if id := notionTitles(server)["deprecate"].ID; id == "" || id == archivedID {
+		t.Errorf("expected a new page for 'deprecate', got %q", id)
+	}
+}
+
+// contextを取得できない場合は、空のContextで上書きせずにエラーを返す
+func TestNotionSyncPropagatesContextErrors(t *testing.T) {
+	ctx := context.Background()
+	exporter, server := newTestNotionExporter(t)
+	lockErr := errors.New("database is locked")
+	exporter.repo = &failingRepository{Repository: exporter.repo, occurrencesErr: lockErr}
+
+	if _, err := exporter.Sync(ctx, ExportOptions{IncludeContext: true}); !errors.Is(err, lockErr) {
+		t.Errorf("sync error = %v, expected %v", err, lockErr)
+	}
+	if n := len(server.Pages(testNotionDatabaseID)); n != 0 {
+		t.Errorf("expected no pages to be created, got %d", n)
+	}
+}
+
+// Notionの1要素あたりの上限（2000文字）を超えるcontextは複数の要素に分けて送る
+func TestNotionSyncSplitsLongContext(t *testing.T) {
+	ctx := context.Background()
+	exporter, server := newTestNotionExporter(t)
+
+	expr, err := exporter.repo.GetExpression(ctx, "deprecate")
+	if err != nil {
+		t.Fatalf("GetExpression: %v", err)
+	}
+	long := "We need to deprecate the old endpoint " + strings.Repeat("and migrate every client to v2 ", 80) + "before the release."
+	if err := exporter.repo.AddOccurrence(ctx, &models.ExpressionOccurrence{ExpressionID: expr.ID, Context: long}); err != nil {
+		t.Fatalf("AddOccurrence: %v", err)
+	}
+
+	if _, err := exporter.Sync(ctx, ExportOptions{IncludeContext: true, MaxContexts: -1}); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	prop := notionTitles(server)["deprecate"].Properties[NotionPropContext]
+	if len(prop.RichText) != 2 {
+		t.Errorf("expected context to be split into 2 rich_text items, got %d", len(prop.RichText))
+	}
+	if got := prop.PlainText(); !strings.Contains(got, long) || len([]rune(got)) <= 2000 {
+		t.Errorf("context was not sent in full: %d characters", len([]rune(got)))
+	}
+}
@@ -0,0 +1,188 @@
+package output
+
+import (
+	_ "embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/service"
+	"github.com/mamyudapao/learn-by-transcript/internal/transcript"
+)
+
+//go:embed templates/report.html
+var reportTemplate string
+
+// ReportOptions は会議レポートの出力オプション
+type ReportOptions struct {
+	Title         string          // レポートのタイトル（空なら会議名から生成）
+	TranscriptURL string          // transcriptファイルへのリンク（タイムスタンプの行にテキストフラグメントで移動）
+	RecordingURL  string          // 録画へのリンク（指定時はTranscriptURLより優先し、#t=秒で再生位置を指定）
+	GeneratedAt   time.Time       // 生成日時（ゼロ値なら現在時刻）
+	Categories    models.Taxonomy // カテゴリの表示順・表示名（空なら初期のカテゴリ）
+}
+
+// reportData はレポートのテンプレートに渡すデータ
+type reportData struct {
+	Title                string
+	Meeting              string
+	GeneratedAt          time.Time
+	NewCount             int
+	UpdatedCount         int
+	PriorityChangedCount int
+	Categories           []reportCategory
+}
+
+// reportCategory はカテゴリごとの表現（優先度の高い順）
+type reportCategory struct {
+	Label string
+	Items []*service.ProcessedExpression
+}
+
+// WriteReportFile は処理結果から会議ごとの学習レポート（外部ファイルに依存しないHTML）を出力
+func WriteReportFile(path string, result *service.ProcessResult, opts ReportOptions) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+	defer file.Close()
+
+	if err := WriteReport(file, result, opts); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// WriteReport は処理結果から学習レポートのHTMLを書き出す（カテゴリ・優先度でグループ化）
+func WriteReport(w io.Writer, result *service.ProcessResult, opts ReportOptions) error {
+	tmpl, err := htmltemplate.New("report").Funcs(htmltemplate.FuncMap{
+		"highlight": func(text, expression string) htmltemplate.HTML {
+			return htmltemplate.HTML(highlightWith(htmltemplate.HTMLEscapeString(text), htmltemplate.HTMLEscapeString(expression), "<mark>", "</mark>"))
+		},
+		"stars": func(priority int) string {
+			if priority < 0 {
+				priority = 0
+			}
+			return strings.Repeat("★", priority) + strings.Repeat("☆", max(5-priority, 0))
+		},
+		"timestamp":     transcript.FormatTimestamp,
+		"timestampLink": opts.timestampLink,
+	}).Parse(reportTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse report template: %w", err)
+	}
+
+	data := reportData{
+		Title:       opts.Title,
+		Meeting:     result.Meeting,
+		GeneratedAt: opts.GeneratedAt,
+		Categories:  groupByCategory(result.Expressions, taxonomyOrDefault(opts.Categories)),
+	}
+	if data.Title == "" {
+		data.Title = "英語表現レポート"
+		if result.Meeting != "" {
+			data.Title += ": " + result.Meeting
+		}
+	}
+	if data.GeneratedAt.IsZero() {
+		data.GeneratedAt = time.Now()
+	}
+	for _, e := range result.Expressions {
+		if e.New {
+			data.NewCount++
+		} else {
+			data.UpdatedCount++
+		}
+		if e.PriorityChanged() {
+			data.PriorityChangedCount++
+		}
+	}
+
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// timestampLink はタイムスタンプへのリンクURL（リンク先がなければ空文字列）
+// file:// のURLもそのまま使えるよう、利用者が指定したURLは信頼済みとして扱う
+func (o ReportOptions) timestampLink(d time.Duration) htmltemplate.URL {
+	switch {
+	case o.RecordingURL != "":
+		return htmltemplate.URL(stripFragment(o.RecordingURL) + "#t=" + strconv.Itoa(int(d/time.Second)))
+	case o.TranscriptURL != "":
+		return htmltemplate.URL(stripFragment(o.TranscriptURL) + "#:~:text=" + url.PathEscape(transcript.FormatTimestamp(d)))
+	default:
+		return ""
+	}
+}
+
+// stripFragment はURLの#以降を除く
+func stripFragment(u string) string {
+	if i := strings.Index(u, "#"); i >= 0 {
+		return u[:i]
+	}
+	return u
+}
+
+// taxonomyOrDefault は分類体系が空なら初期のカテゴリを返す
+func taxonomyOrDefault(taxonomy models.Taxonomy) models.Taxonomy {
+	if len(taxonomy) == 0 {
+		return models.DefaultCategories()
+	}
+	return taxonomy
+}
+
+// groupByCategory は表現を主カテゴリごとにまとめ、各カテゴリ内を優先度の高い順に並べる
+// カテゴリは分類体系の表示順に並べ、分類体系にないカテゴリは名前順で後ろに並べる
+func groupByCategory(expressions []*service.ProcessedExpression, taxonomy models.Taxonomy) []reportCategory {
+	groups := make(map[string][]*service.ProcessedExpression)
+	for _, e := range expressions {
+		groups[e.Expression.Category] = append(groups[e.Expression.Category], e)
+	}
+
+	rank := func(category string) int {
+		for i, c := range taxonomy {
+			if c.Name == category {
+				return i
+			}
+		}
+		return len(taxonomy)
+	}
+	categories := make([]string, 0, len(groups))
+	for category := range groups {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		ri, rj := rank(categories[i]), rank(categories[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return categories[i] < categories[j]
+	})
+
+	result := make([]reportCategory, 0, len(categories))
+	for _, category := range categories {
+		items := groups[category]
+		sort.SliceStable(items, func(i, j int) bool {
+			if items[i].Expression.Priority != items[j].Expression.Priority {
+				return items[i].Expression.Priority > items[j].Expression.Priority
+			}
+			return strings.ToLower(items[i].Expression.Expression) < strings.ToLower(items[j].Expression.Expression)
+		})
+
+		label := taxonomy.Label(category)
+		if label == "" {
+			label = "未分類"
+		}
+		result = append(result, reportCategory{Label: label, Items: items})
+	}
+	return result
+}
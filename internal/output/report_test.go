@@ -0,0 +1,64 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/service"
+)
+
+func TestWriteReport(t *testing.T) {
+	result := &service.ProcessResult{
+		Meeting: "Weekly Sync",
+		Expressions: []*service.ProcessedExpression{
+			{
+				Expression:       &models.Expression{ID: 1, Expression: "touch base", Type: "phrase", Meaning: "連絡を取る", Priority: 3, Category: "business", OccurrenceCount: 1},
+				New:              true,
+				PreviousPriority: 3,
+				Contexts:         []service.ProcessedContext{{Text: "I'll touch base with <legal>.", Timestamp: 95 * time.Second, HasTimestamp: true}},
+			},
+			{
+				Expression:       &models.Expression{ID: 2, Expression: "deprecate", Type: "word", Meaning: "非推奨にする", Priority: 5, Category: "engineering", OccurrenceCount: 3},
+				PreviousPriority: 4,
+				Contexts:         []service.ProcessedContext{{Text: "We deprecate it."}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := WriteReport(&buf, result, ReportOptions{RecordingURL: "https://example.com/rec#old"})
+	if err != nil {
+		t.Fatalf("WriteReport: %v", err)
+	}
+	html := buf.String()
+
+	for _, want := range []string{
+		"<title>英語表現レポート: Weekly Sync</title>",
+		`I&#39;ll <mark>touch base</mark> with &lt;legal&gt;.`,
+		`href="https://example.com/rec#t=95"`,
+		">00:01:35</a>",
+		"We <mark>deprecate</mark> it.",
+		"（優先度 4 → 5）",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report does not contain %q", want)
+		}
+	}
+
+	// カテゴリはエンジニアリング → ビジネスの順
+	if strings.Index(html, "エンジニアリング") > strings.Index(html, "ビジネス") {
+		t.Error("engineering should come before business")
+	}
+
+	// 録画URLがなければtranscriptのテキストフラグメントにリンク
+	buf.Reset()
+	if err := WriteReport(&buf, result, ReportOptions{TranscriptURL: "file:///tmp/sync.txt"}); err != nil {
+		t.Fatalf("WriteReport: %v", err)
+	}
+	if !strings.Contains(buf.String(), `href="file:///tmp/sync.txt#:~:text=00:01:35"`) {
+		t.Errorf("transcript link not found in report")
+	}
+}
@@ -0,0 +1,230 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+func init() {
+	Register("template", nil, func(repo storage.Repository) Exporter {
+		return NewTemplateExporter(repo)
+	})
+}
+
+// TemplateExporter はユーザー定義のGoテンプレートで表現を出力
+// テンプレートのファイル名が .html / .htm（.tmpl などの拡張子を除いて）で終わる場合はhtml/templateを使用する
+type TemplateExporter struct {
+	repository storage.Repository
+}
+
+// TemplateData はテンプレート全体に渡すデータ
+type TemplateData struct {
+	Expressions []*models.Expression
+	GeneratedAt time.Time
+}
+
+// NewTemplateExporter は新しいTemplateExporterを作成
+func NewTemplateExporter(repo storage.Repository) *TemplateExporter {
+	return &TemplateExporter{
+		repository: repo,
+	}
+}
+
+// executor はtext/templateとhtml/templateの共通インターフェース
+type executor interface {
+	Execute(w io.Writer, data interface{}) error
+}
+
+// Export はopts.TemplatePathのテンプレートで表現を出力
+// outputPathが既存のディレクトリなら表現ごとに1ファイル（Obsidianのノートなど）を出力し、テンプレートには各表現を渡す
+func (e *TemplateExporter) Export(ctx context.Context, outputPath string, opts ExportOptions) error {
+	if opts.TemplatePath == "" {
+		return fmt.Errorf("template path is required for template export")
+	}
+
+	expressions, err := SelectExpressions(ctx, e.repository, opts)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := e.parse(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	if info, err := os.Stat(outputPath); err == nil && info.IsDir() {
+		return e.exportPerExpression(tmpl, outputPath, expressions, opts)
+	}
+
+	var buf bytes.Buffer
+	data := TemplateData{Expressions: expressions, GeneratedAt: time.Now()}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	if err := os.WriteFile(outputPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// exportPerExpression は表現ごとにテンプレートを実行してディレクトリに出力
+// ファイル名は表現から生成し、拡張子はテンプレートのファイル名（.tmplなどを除く）から決める
+func (e *TemplateExporter) exportPerExpression(tmpl executor, dir string, expressions []*models.Expression, opts ExportOptions) error {
+	ext := filepath.Ext(templateBaseName(opts.TemplatePath))
+	if ext == "" {
+		ext = ".md"
+	}
+
+	for _, expr := range expressions {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, expr); err != nil {
+			return fmt.Errorf("failed to execute template for '%s': %w", expr.Expression, err)
+		}
+		path := filepath.Join(dir, safeFileName(expr.Expression)+ext)
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+	}
+	return nil
+}
+
+// parse はテンプレートファイルを読み込み、ヘルパー関数を登録してパース
+func (e *TemplateExporter) parse(ctx context.Context, opts ExportOptions) (executor, error) {
+	content, err := os.ReadFile(opts.TemplatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	name := filepath.Base(opts.TemplatePath)
+
+	if isHTMLTemplate(opts.TemplatePath) {
+		funcs := e.funcs(ctx, opts)
+		funcs["highlight"] = func(text, expression string) htmltemplate.HTML {
+			return htmltemplate.HTML(highlightExpression(htmltemplate.HTMLEscapeString(text), htmltemplate.HTMLEscapeString(expression)))
+		}
+		tmpl, err := htmltemplate.New(name).Funcs(funcs).Parse(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template: %w", err)
+		}
+		return tmpl, nil
+	}
+
+	funcs := e.funcs(ctx, opts)
+	funcs["highlight"] = func(text, expression string) string {
+		return highlightWith(text, expression, "**", "**")
+	}
+	tmpl, err := texttemplate.New(name).Funcs(funcs).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// funcs はテンプレートのヘルパー関数（text/html共通）
+func (e *TemplateExporter) funcs(ctx context.Context, opts ExportOptions) map[string]interface{} {
+	var taxonomy models.Taxonomy
+	return map[string]interface{}{
+		// contexts は表現の例文（重複除去済み）を返す。件数を省略するとExportOptionsの設定に従う
+		"contexts": func(expr *models.Expression, max ...int) ([]*models.ExpressionOccurrence, error) {
+			limit := opts.MaxContexts
+			if len(max) > 0 {
+				limit = max[0]
+			}
+			return loadContexts(ctx, e.repository, expr.ID, limit, opts.ContextOrder)
+		},
+		// senses は表現の語義を登録順に返す
+		"senses": func(expr *models.Expression) ([]*models.Sense, error) {
+			senses, err := e.repository.ListSenses(ctx, expr.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list senses: %w", err)
+			}
+			return senses, nil
+		},
+		// usage はenrichで生成した表現の使い方を返す（未生成ならnil）
+		"usage": func(expr *models.Expression) (*models.Usage, error) {
+			usage, err := e.repository.GetUsage(ctx, expr.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get usage: %w", err)
+			}
+			return usage, nil
+		},
+		"highlightWith": highlightWith,
+		// categoryLabel はカテゴリの表示名を返す（分類体系にないカテゴリはそのまま）
+		"categoryLabel": func(category string) (string, error) {
+			if taxonomy == nil {
+				t, err := e.repository.ListCategories(ctx)
+				if err != nil {
+					return "", fmt.Errorf("failed to list categories: %w", err)
+				}
+				taxonomy = taxonomyOrDefault(t)
+			}
+			return taxonomy.Label(category), nil
+		},
+		"date": func(t time.Time, layout ...string) string {
+			if len(layout) > 0 {
+				return t.Format(layout[0])
+			}
+			return t.Format("2006-01-02")
+		},
+		"join":  strings.Join,
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+		"tsv": func(s string) string {
+			return strings.Join(strings.Fields(s), " ")
+		},
+	}
+}
+
+// highlightWith はtext中の表現（単語の先頭から一致、大文字小文字を区別しない）をopen/closeで囲む
+func highlightWith(text, expression, open, close string) string {
+	if text == "" || expression == "" {
+		return text
+	}
+	re, err := regexp.Compile(`(?i)\b` + regexp.QuoteMeta(expression))
+	if err != nil {
+		return text
+	}
+	return re.ReplaceAllStringFunc(text, func(match string) string {
+		return open + match + close
+	})
+}
+
+// templateBaseName はテンプレートのファイル名から .tmpl / .tpl / .gotmpl を除く
+func templateBaseName(path string) string {
+	base := filepath.Base(path)
+	for _, ext := range []string{".tmpl", ".tpl", ".gotmpl"} {
+		if strings.HasSuffix(strings.ToLower(base), ext) {
+			return base[:len(base)-len(ext)]
+		}
+	}
+	return base
+}
+
+// isHTMLTemplate はテンプレートがHTMLを出力するか判定
+func isHTMLTemplate(path string) bool {
+	ext := strings.ToLower(filepath.Ext(templateBaseName(path)))
+	return ext == ".html" || ext == ".htm"
+}
+
+// unsafeFileChars はファイル名に使えない文字
+var unsafeFileChars = regexp.MustCompile(`[\\/:*?"<>|]+`)
+
+// safeFileName は表現をファイル名に使える形に変換
+func safeFileName(s string) string {
+	s = strings.TrimSpace(unsafeFileChars.ReplaceAllString(s, "_"))
+	if s == "" || s == "." || s == ".." {
+		return "_"
+	}
+	return s
+}
@@ -0,0 +1,78 @@
+package output
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTemplate はテスト用のテンプレートファイルを作成
+func writeTemplate(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	return path
+}
+
+func TestTemplateExport(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	exporter := NewTemplateExporter(repo)
+
+	t.Run("text/template", func(t *testing.T) {
+		tmpl := writeTemplate(t, "cards.tsv.tmpl", `{{range .Expressions}}{{$e := .Expression}}{{.Expression}}	{{categoryLabel .Category}}	{{range contexts . 1}}{{highlight .Context $e}}{{end}}
+{{end}}`)
+		out := filepath.Join(t.TempDir(), "cards.tsv")
+		if err := exporter.Export(ctx, out, ExportOptions{TemplatePath: tmpl, SortBy: "expression"}); err != nil {
+			t.Fatalf("Export: %v", err)
+		}
+
+		content, _ := os.ReadFile(out)
+		expected := "deprecate\tエンジニアリング\tWe need to **deprecate** the old endpoint.\n" +
+			"touch base\tビジネス\tI touched base with them yesterday.\n"
+		if string(content) != expected {
+			t.Errorf("unexpected output:\n%s\nexpected:\n%s", content, expected)
+		}
+	})
+
+	t.Run("html/template", func(t *testing.T) {
+		tmpl := writeTemplate(t, "sheet.html.tmpl", `{{range .Expressions}}<p>{{.Meaning}}</p>{{$e := .Expression}}{{range contexts .}}<q>{{highlight .Context $e}}</q>{{end}}{{end}}`)
+		out := filepath.Join(t.TempDir(), "sheet.html")
+		if err := exporter.Export(ctx, out, ExportOptions{TemplatePath: tmpl, MinPriority: 5}); err != nil {
+			t.Fatalf("Export: %v", err)
+		}
+
+		content, _ := os.ReadFile(out)
+		expected := "<p>非推奨にする</p><q>We need to <b>deprecate</b> the old endpoint.</q>"
+		if string(content) != expected {
+			t.Errorf("unexpected output:\n%s\nexpected:\n%s", content, expected)
+		}
+	})
+
+	t.Run("表現ごとのファイル", func(t *testing.T) {
+		tmpl := writeTemplate(t, "note.md.tmpl", "# {{.Expression}}\n{{.Meaning}}\n")
+		dir := t.TempDir()
+		if err := exporter.Export(ctx, dir, ExportOptions{TemplatePath: tmpl}); err != nil {
+			t.Fatalf("Export: %v", err)
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, "touch base.md"))
+		if err != nil {
+			t.Fatalf("note not written: %v", err)
+		}
+		if !strings.HasPrefix(string(content), "# touch base\n連絡を取る") {
+			t.Errorf("unexpected note: %q", content)
+		}
+	})
+
+	t.Run("構文エラー", func(t *testing.T) {
+		tmpl := writeTemplate(t, "broken.tmpl", "{{range .Expressions}")
+		if err := exporter.Export(ctx, filepath.Join(t.TempDir(), "out"), ExportOptions{TemplatePath: tmpl}); err == nil {
+			t.Error("expected parse error")
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package review
+
+import (
+	"math"
+	"time"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+// fsrsDefaultWeights はFSRS-4.5のデフォルトパラメータ
+var fsrsDefaultWeights = [17]float64{
+	0.4872, 1.4003, 3.7145, 13.8206, 5.1618, 1.2298, 0.8975, 0.031,
+	1.6474, 0.1367, 1.0461, 2.1072, 0.0793, 0.3246, 1.587, 0.2272, 2.8755,
+}
+
+const (
+	fsrsDecay  = -0.5
+	fsrsFactor = 19.0 / 81.0 // R(S, S) = 0.9 となる係数
+)
+
+// FSRSScheduler はFSRS（Free Spaced Repetition Scheduler）によるスケジューラ
+type FSRSScheduler struct {
+	weights         [17]float64
+	targetRetention float64 // 次回復習時点での目標想起率
+}
+
+// NewFSRSScheduler は新しいFSRSSchedulerを作成
+func NewFSRSScheduler(targetRetention float64) *FSRSScheduler {
+	if targetRetention <= 0 || targetRetention >= 1 {
+		targetRetention = 0.9 // デフォルト
+	}
+	return &FSRSScheduler{
+		weights:         fsrsDefaultWeights,
+		targetRetention: targetRetention,
+	}
+}
+
+// Name はスケジューラ名を取得
+func (s *FSRSScheduler) Name() string {
+	return "fsrs"
+}
+
+// Schedule は評価に基づいてカードの次回復習日時などを更新
+func (s *FSRSScheduler) Schedule(card *models.ReviewCard, grade Grade, now time.Time) {
+	w := s.weights
+	g := float64(grade)
+
+	if card.IsNew() || card.Stability == 0 {
+		// 初回: 評価ごとの初期安定性・初期難易度
+		card.Stability = w[int(grade)-1]
+		card.Difficulty = s.initialDifficulty(g)
+	} else {
+		elapsed := now.Sub(card.LastReviewedAt).Hours() / 24
+		r := s.retrievability(elapsed, card.Stability)
+
+		if grade == GradeAgain {
+			card.Stability = w[11] * math.Pow(card.Difficulty, -w[12]) *
+				(math.Pow(card.Stability+1, w[13]) - 1) * math.Exp(w[14]*(1-r))
+		} else {
+			hardPenalty, easyBonus := 1.0, 1.0
+			if grade == GradeHard {
+				hardPenalty = w[15]
+			}
+			if grade == GradeEasy {
+				easyBonus = w[16]
+			}
+			card.Stability *= 1 + math.Exp(w[8])*(11-card.Difficulty)*math.Pow(card.Stability, -w[9])*
+				(math.Exp(w[10]*(1-r))-1)*hardPenalty*easyBonus
+		}
+
+		// 難易度を更新し、初期難易度（Easy基準）へ平均回帰
+		d := card.Difficulty - w[6]*(g-3)
+		card.Difficulty = clamp(w[7]*s.initialDifficulty(4)+(1-w[7])*d, 1, 10)
+	}
+
+	if grade == GradeAgain {
+		if card.Reps > 0 {
+			card.Lapses++
+		}
+		card.Reps = 0
+	} else {
+		card.Reps++
+	}
+
+	scheduleAfter(card, grade, s.nextInterval(card.Stability), now)
+}
+
+// initialDifficulty は評価に応じた初期難易度
+func (s *FSRSScheduler) initialDifficulty(g float64) float64 {
+	return clamp(s.weights[4]-(g-3)*s.weights[5], 1, 10)
+}
+
+// retrievability は経過日数と安定性から現在の想起率を推定
+func (s *FSRSScheduler) retrievability(elapsedDays, stability float64) float64 {
+	return math.Pow(1+fsrsFactor*elapsedDays/stability, fsrsDecay)
+}
+
+// nextInterval は目標想起率に達するまでの日数（最低1日）
+func (s *FSRSScheduler) nextInterval(stability float64) float64 {
+	interval := stability / fsrsFactor * (math.Pow(s.targetRetention, 1/fsrsDecay) - 1)
+	return math.Max(1, math.Round(interval))
+}
+
+// clamp は値を[min, max]に収める
+func clamp(v, min, max float64) float64 {
+	return math.Min(math.Max(v, min), max)
+}
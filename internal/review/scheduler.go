@@ -0,0 +1,85 @@
+// Package review は間隔反復（spaced repetition）による復習スケジューラを提供する
+package review
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+// Grade は復習時の自己評価
+type Grade int
+
+const (
+	GradeAgain Grade = 1 // 思い出せなかった
+	GradeHard  Grade = 2 // なんとか思い出せた
+	GradeGood  Grade = 3 // 思い出せた
+	GradeEasy  Grade = 4 // 簡単に思い出せた
+)
+
+// relearnDelay は忘れた（Again）カードを再度出題するまでの時間
+const relearnDelay = 10 * time.Minute
+
+// String はGradeの表示名を返す
+func (g Grade) String() string {
+	switch g {
+	case GradeAgain:
+		return "Again"
+	case GradeHard:
+		return "Hard"
+	case GradeGood:
+		return "Good"
+	case GradeEasy:
+		return "Easy"
+	default:
+		return fmt.Sprintf("Grade(%d)", int(g))
+	}
+}
+
+// Valid はGradeが1〜4の範囲か判定
+func (g Grade) Valid() bool {
+	return g >= GradeAgain && g <= GradeEasy
+}
+
+// Scheduler は復習スケジューラの抽象インターフェース
+type Scheduler interface {
+	// Schedule は評価に基づいてカードの次回復習日時などを更新
+	Schedule(card *models.ReviewCard, grade Grade, now time.Time)
+
+	// Name はスケジューラ名を取得
+	Name() string
+}
+
+// NewScheduler は名前に基づいて適切なスケジューラを生成
+func NewScheduler(name string) (Scheduler, error) {
+	switch name {
+	case "sm2", "":
+		return NewSM2Scheduler(), nil
+	case "fsrs":
+		return NewFSRSScheduler(0.9), nil
+	default:
+		return nil, fmt.Errorf("unknown scheduler: %s", name)
+	}
+}
+
+// NewCard は未復習の表現用の初期カードを作成
+func NewCard(expressionID int, now time.Time) *models.ReviewCard {
+	return &models.ReviewCard{
+		ExpressionID: expressionID,
+		Ease:         sm2InitialEase,
+		DueAt:        now,
+	}
+}
+
+// scheduleAfter はカードの間隔と次回復習日時を設定（Againは短時間後に再出題）
+func scheduleAfter(card *models.ReviewCard, grade Grade, intervalDays float64, now time.Time) {
+	card.LastReviewedAt = now
+	if grade == GradeAgain {
+		card.IntervalDays = 0
+		card.DueAt = now.Add(relearnDelay)
+		return
+	}
+	card.IntervalDays = intervalDays
+	card.DueAt = now.Add(time.Duration(intervalDays * float64(24*time.Hour)))
+}
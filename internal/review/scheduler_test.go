@@ -0,0 +1,93 @@
+package review
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSM2Schedule(t *testing.T) {
+	now := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+	s := NewSM2Scheduler()
+	card := NewCard(1, now)
+
+	// Good: 1日 → 6日 → 6*EF日
+	expected := []float64{1, 6, 15}
+	for i, want := range expected {
+		s.Schedule(card, GradeGood, now)
+		if card.IntervalDays != want {
+			t.Errorf("review %d: interval = %v, expected %v", i+1, card.IntervalDays, want)
+		}
+		now = card.DueAt
+	}
+	if card.Ease != 2.5 {
+		t.Errorf("Good should keep ease at 2.5, got %v", card.Ease)
+	}
+
+	// Again: 最初からやり直し、易しさ係数は変えない
+	s.Schedule(card, GradeAgain, now)
+	if card.Reps != 0 || card.Lapses != 1 || card.Ease != 2.5 {
+		t.Errorf("unexpected state after Again: %+v", card)
+	}
+	if got := card.DueAt.Sub(now); got != relearnDelay {
+		t.Errorf("Again should be due in %v, got %v", relearnDelay, got)
+	}
+
+	// Hard を繰り返しても易しさ係数は下限で止まる
+	for i := 0; i < 20; i++ {
+		s.Schedule(card, GradeHard, now)
+	}
+	if card.Ease != sm2MinEase {
+		t.Errorf("ease = %v, expected minimum %v", card.Ease, sm2MinEase)
+	}
+}
+
+func TestFSRSSchedule(t *testing.T) {
+	now := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+	s := NewFSRSScheduler(0.9)
+
+	// 初回評価が高いほど間隔が長い
+	var prev float64
+	for _, grade := range []Grade{GradeHard, GradeGood, GradeEasy} {
+		card := NewCard(1, now)
+		s.Schedule(card, grade, now)
+		if card.IntervalDays <= prev {
+			t.Errorf("%s: interval %v should be longer than %v", grade, card.IntervalDays, prev)
+		}
+		prev = card.IntervalDays
+	}
+
+	// 期日どおりにGoodを続けると間隔が伸びる
+	card := NewCard(1, now)
+	s.Schedule(card, GradeGood, now)
+	for i := 0; i < 3; i++ {
+		before := card.IntervalDays
+		now = card.DueAt
+		s.Schedule(card, GradeGood, now)
+		if card.IntervalDays <= before {
+			t.Errorf("review %d: interval %v should grow from %v", i+2, card.IntervalDays, before)
+		}
+	}
+
+	// 忘れると安定性が下がり、難易度が上がる
+	stability, difficulty := card.Stability, card.Difficulty
+	now = card.DueAt
+	s.Schedule(card, GradeAgain, now)
+	if card.Stability >= stability || card.Difficulty <= difficulty || card.Lapses != 1 {
+		t.Errorf("unexpected state after Again: %+v", card)
+	}
+}
+
+func TestNewScheduler(t *testing.T) {
+	for _, name := range []string{"sm2", "fsrs"} {
+		s, err := NewScheduler(name)
+		if err != nil {
+			t.Fatalf("NewScheduler(%q): %v", name, err)
+		}
+		if s.Name() != name {
+			t.Errorf("Name() = %q, expected %q", s.Name(), name)
+		}
+	}
+	if _, err := NewScheduler("leitner"); err == nil {
+		t.Error("expected error for unknown scheduler")
+	}
+}
@@ -0,0 +1,63 @@
+package review
+
+import (
+	"math"
+	"time"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+const (
+	sm2InitialEase = 2.5
+	sm2MinEase     = 1.3
+)
+
+// SM2Scheduler はSuperMemo SM-2アルゴリズムによるスケジューラ
+type SM2Scheduler struct{}
+
+// NewSM2Scheduler は新しいSM2Schedulerを作成
+func NewSM2Scheduler() *SM2Scheduler {
+	return &SM2Scheduler{}
+}
+
+// Name はスケジューラ名を取得
+func (s *SM2Scheduler) Name() string {
+	return "sm2"
+}
+
+// Schedule は評価に基づいてカードの次回復習日時などを更新
+// Again/Hard/Good/Easy はSM-2の品質 q=1/3/4/5 に対応させる
+func (s *SM2Scheduler) Schedule(card *models.ReviewCard, grade Grade, now time.Time) {
+	if card.Ease == 0 {
+		card.Ease = sm2InitialEase
+	}
+
+	// 思い出せなかった場合は易しさ係数を変えずに最初からやり直す
+	if grade == GradeAgain {
+		if card.Reps > 0 {
+			card.Lapses++
+		}
+		card.Reps = 0
+		scheduleAfter(card, grade, 0, now)
+		return
+	}
+
+	q := float64(grade) + 1
+	card.Ease += 0.1 - (5-q)*(0.08+(5-q)*0.02)
+	if card.Ease < sm2MinEase {
+		card.Ease = sm2MinEase
+	}
+
+	card.Reps++
+	var interval float64
+	switch card.Reps {
+	case 1:
+		interval = 1
+	case 2:
+		interval = 6
+	default:
+		interval = math.Round(card.IntervalDays * card.Ease)
+	}
+
+	scheduleAfter(card, grade, interval, now)
+}
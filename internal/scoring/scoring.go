@@ -0,0 +1,117 @@
+// Package scoring は表現の学習優先度のスコアを計算する
+//
+// スコア = 基準の優先度（LLMの判定または手動編集）+ 出現頻度 + 会議の多様さ + 復習成績
+// 出現頻度は新しい出現ほど重く数え（半減期で減衰）、しばらく出ていない表現は基準より下がる。
+// スコアは履歴から毎回計算し直すので、同じ出現が繰り返し加算されることはない
+package scoring
+
+import (
+	"math"
+	"time"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+// Params はスコア計算の重み
+type Params struct {
+	HalfLife        time.Duration // 出現の重みが半分になる期間
+	FrequencyWeight float64       // 減衰後の出現回数（log2）の重み
+	DiversityWeight float64       // 出現した会議の数（log2）の重み
+	ReviewWeight    float64       // 復習成績の重み（Againが多いほど上げ、Easyが多いほど下げる）
+	RecentGrades    int           // 復習成績に使う直近の評価数
+}
+
+// DefaultParams はデフォルトの重み
+// 直近に2回出ると+1、4回で+2。2つの会議で出ると+0.5、4つで+1。直近の評価がすべてAgainなら+1、Easyなら-1
+func DefaultParams() Params {
+	return Params{
+		HalfLife:        30 * 24 * time.Hour,
+		FrequencyWeight: 1.0,
+		DiversityWeight: 0.5,
+		ReviewWeight:    1.0,
+		RecentGrades:    5,
+	}
+}
+
+// minDecayedCount は減衰後の出現回数の下限（長く出ていない表現でも基準から-1まで）
+const minDecayedCount = 0.5
+
+// Input はスコア計算に使う履歴
+type Input struct {
+	BasePriority int
+	Occurrences  []*models.ExpressionOccurrence
+	Grades       []int // 直近の復習評価（1:Again 2:Hard 3:Good 4:Easy、新しい順）
+}
+
+// Breakdown はスコアの内訳
+type Breakdown struct {
+	Base      float64 `json:"base"`
+	Frequency float64 `json:"frequency"`
+	Diversity float64 `json:"diversity"`
+	Review    float64 `json:"review"`
+}
+
+// Score は内訳の合計
+func (b Breakdown) Score() float64 {
+	return b.Base + b.Frequency + b.Diversity + b.Review
+}
+
+// Priority はスコアを1〜5の優先度に丸める
+func (b Breakdown) Priority() int {
+	return Priority(b.Score())
+}
+
+// Compute は時刻nowにおけるスコアの内訳を計算
+func (p Params) Compute(in Input, now time.Time) Breakdown {
+	b := Breakdown{Base: float64(in.BasePriority)}
+
+	// 出現頻度: 経過時間で半減する重みの合計
+	if len(in.Occurrences) > 0 {
+		decayed := 0.0
+		meetings := make(map[string]bool)
+		for _, occ := range in.Occurrences {
+			age := now.Sub(occ.OccurredAt)
+			if age < 0 {
+				age = 0
+			}
+			decayed += math.Exp2(-float64(age) / float64(p.HalfLife))
+			if occ.Meeting != "" {
+				meetings[occ.Meeting] = true
+			}
+		}
+		b.Frequency = p.FrequencyWeight * math.Log2(math.Max(decayed, minDecayedCount))
+
+		// 会議の多様さ: 複数の会議で使われる表現ほど汎用的
+		if len(meetings) > 1 {
+			b.Diversity = p.DiversityWeight * math.Log2(float64(len(meetings)))
+		}
+	}
+
+	// 復習成績: 直近の評価の平均が2.5（Hard〜Goodの中間）より悪ければ上げ、良ければ下げる
+	grades := in.Grades
+	if p.RecentGrades > 0 && len(grades) > p.RecentGrades {
+		grades = grades[:p.RecentGrades]
+	}
+	if len(grades) > 0 {
+		sum := 0
+		for _, g := range grades {
+			sum += g
+		}
+		average := float64(sum) / float64(len(grades))
+		b.Review = p.ReviewWeight * (2.5 - average) / 1.5
+	}
+
+	return b
+}
+
+// Priority はスコアを四捨五入して1〜5の優先度にする
+func Priority(score float64) int {
+	priority := int(math.Round(score))
+	if priority < 1 {
+		return 1
+	}
+	if priority > 5 {
+		return 5
+	}
+	return priority
+}
@@ -0,0 +1,88 @@
+package scoring
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+func TestCompute(t *testing.T) {
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	params := DefaultParams()
+	occurrence := func(meeting string, daysAgo int) *models.ExpressionOccurrence {
+		return &models.ExpressionOccurrence{Meeting: meeting, OccurredAt: now.AddDate(0, 0, -daysAgo)}
+	}
+
+	tests := []struct {
+		name  string
+		in    Input
+		want  Breakdown
+		score int
+	}{
+		{
+			name:  "one recent occurrence keeps the base",
+			in:    Input{BasePriority: 3, Occurrences: []*models.ExpressionOccurrence{occurrence("sync", 0)}},
+			want:  Breakdown{Base: 3},
+			score: 3,
+		},
+		{
+			name: "frequent in many meetings",
+			in: Input{BasePriority: 3, Occurrences: []*models.ExpressionOccurrence{
+				occurrence("sync", 0), occurrence("retro", 0), occurrence("planning", 0), occurrence("1on1", 0),
+			}},
+			want:  Breakdown{Base: 3, Frequency: 2, Diversity: 1},
+			score: 5,
+		},
+		{
+			name:  "half-life halves the weight",
+			in:    Input{BasePriority: 3, Occurrences: []*models.ExpressionOccurrence{occurrence("sync", 30), occurrence("sync", 30)}},
+			want:  Breakdown{Base: 3, Frequency: 0},
+			score: 3,
+		},
+		{
+			name:  "stale occurrences fall below the base",
+			in:    Input{BasePriority: 3, Occurrences: []*models.ExpressionOccurrence{occurrence("sync", 365)}},
+			want:  Breakdown{Base: 3, Frequency: -1},
+			score: 2,
+		},
+		{
+			name:  "forgotten in review",
+			in:    Input{BasePriority: 4, Grades: []int{1, 1, 1}},
+			want:  Breakdown{Base: 4, Review: 1},
+			score: 5,
+		},
+		{
+			name:  "only recent grades count",
+			in:    Input{BasePriority: 2, Grades: []int{4, 4, 4, 4, 4, 1, 1}},
+			want:  Breakdown{Base: 2, Review: -1},
+			score: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := params.Compute(tt.in, now)
+			if !near(got.Base, tt.want.Base) || !near(got.Frequency, tt.want.Frequency) ||
+				!near(got.Diversity, tt.want.Diversity) || !near(got.Review, tt.want.Review) {
+				t.Errorf("Compute = %+v, want %+v", got, tt.want)
+			}
+			if got.Priority() != tt.score {
+				t.Errorf("Priority = %d, want %d (score %.2f)", got.Priority(), tt.score, got.Score())
+			}
+		})
+	}
+}
+
+func TestPriority(t *testing.T) {
+	for score, want := range map[float64]int{-2: 1, 0.4: 1, 2.5: 3, 3.49: 3, 4.6: 5, 9: 5} {
+		if got := Priority(score); got != want {
+			t.Errorf("Priority(%v) = %d, want %d", score, got, want)
+		}
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
@@ -0,0 +1,435 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+const (
+	defaultPerPage = 50
+	maxPerPage     = 200
+)
+
+// expressionResponse はAPIで返す表現
+type expressionResponse struct {
+	ID              int             `json:"id"`
+	Expression      string          `json:"expression"`
+	Type            string          `json:"type"`
+	Meaning         string          `json:"meaning"`
+	Priority        int             `json:"priority"`
+	BasePriority    int             `json:"base_priority"`
+	Score           float64         `json:"score"`
+	Category        string          `json:"category"`   // 主カテゴリ
+	Categories      []string        `json:"categories"` // 関連の強い順（主カテゴリが先頭）
+	Tags            []string        `json:"tags"`
+	OccurrenceCount int             `json:"occurrence_count"`
+	FirstSeenAt     time.Time       `json:"first_seen_at"`
+	LastSeenAt      time.Time       `json:"last_seen_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+	ManuallyEdited  bool            `json:"manually_edited"`
+	Aliases         []string        `json:"aliases,omitempty"`
+	Senses          []senseResponse `json:"senses,omitempty"` // 詳細のみ（登録順）
+	Usage           *usageResponse  `json:"usage,omitempty"`  // 詳細のみ（enrichで生成済みの場合）
+}
+
+// senseResponse はAPIで返す語義
+type senseResponse struct {
+	ID              int    `json:"id"`
+	PartOfSpeech    string `json:"part_of_speech"`
+	Gloss           string `json:"gloss"`
+	Definition      string `json:"definition"`
+	OccurrenceCount int    `json:"occurrence_count"`
+}
+
+// usageResponse はAPIで返す表現の使い方（例文・使用上の注意・関連語）
+type usageResponse struct {
+	Examples     []models.Example `json:"examples"`
+	Register     string           `json:"register,omitempty"`
+	Note         string           `json:"note,omitempty"`
+	Collocations []string         `json:"collocations"`
+	Synonyms     []string         `json:"synonyms"`
+	Antonyms     []string         `json:"antonyms"`
+	GeneratedAt  time.Time        `json:"generated_at"`
+}
+
+// occurrenceResponse はAPIで返す出現履歴
+type occurrenceResponse struct {
+	ID         int       `json:"id"`
+	Context    string    `json:"context"`
+	Surface    string    `json:"surface,omitempty"`
+	Meeting    string    `json:"meeting,omitempty"`
+	SenseID    int       `json:"sense_id,omitempty"` // 文脈で使われた語義（未判定なら省略）
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// listResponse はページネーション付きの一覧
+type listResponse struct {
+	Items   []expressionResponse `json:"items"`
+	Total   int                  `json:"total"`
+	Page    int                  `json:"page"`
+	PerPage int                  `json:"per_page"`
+}
+
+func newExpressionResponse(expr *models.Expression) expressionResponse {
+	return expressionResponse{
+		ID:              expr.ID,
+		Expression:      expr.Expression,
+		Type:            expr.Type,
+		Meaning:         expr.Meaning,
+		Priority:        expr.Priority,
+		BasePriority:    expr.BasePriority,
+		Score:           expr.Score,
+		Category:        expr.Category,
+		Categories:      append([]string{}, expr.Categories...),
+		Tags:            append([]string{}, expr.Tags...),
+		OccurrenceCount: expr.OccurrenceCount,
+		FirstSeenAt:     expr.FirstSeenAt,
+		LastSeenAt:      expr.LastSeenAt,
+		UpdatedAt:       expr.UpdatedAt,
+		ManuallyEdited:  expr.ManuallyEdited,
+	}
+}
+
+// handleListExpressions は表現の一覧（検索・フィルタ・並び替え・ページネーション）
+// クエリ: q, type, category（主カテゴリ以外や親カテゴリも一致）, tag（複数指定はすべてのタグがついた表現）, min_priority, sort (priority|occurrence|expression|recent), page, per_page
+func (s *Server) handleListExpressions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	exprType := query.Get("type")
+	if exprType != "" && exprType != string(models.TypeWord) && exprType != string(models.TypePhrase) {
+		writeError(w, badRequest("type", "type must be word or phrase"))
+		return
+	}
+	minPriority, err := queryInt(r, "min_priority", 0, 0, 5)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	page, err := queryInt(r, "page", 1, 1, 1<<20)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	perPage, err := queryInt(r, "per_page", defaultPerPage, 1, maxPerPage)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	sortBy := query.Get("sort")
+	if sortBy != "" && sortBy != "priority" && sortBy != "occurrence" && sortBy != "expression" && sortBy != "recent" {
+		writeError(w, badRequest("sort", "sort must be one of priority, occurrence, expression, recent"))
+		return
+	}
+
+	tags, err := models.NormalizeTags(query["tag"])
+	if err != nil {
+		writeError(w, badRequest("tag", "%v", err))
+		return
+	}
+
+	var expressions []*models.Expression
+	if q := strings.TrimSpace(query.Get("q")); q != "" {
+		// 検索は関連度順（sort指定時のみ並び替え）
+		expressions, err = s.repo.Search(r.Context(), storage.SearchOptions{
+			Query:       q,
+			Type:        exprType,
+			Category:    query.Get("category"),
+			Tags:        tags,
+			MinPriority: minPriority,
+		})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	} else {
+		all, err := s.repo.ListExpressions(r.Context())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		for _, expr := range all {
+			if exprType != "" && expr.Type != exprType {
+				continue
+			}
+			if c := query.Get("category"); c != "" && !expr.HasCategory(c) {
+				continue
+			}
+			if !expr.HasTags(tags...) {
+				continue
+			}
+			if expr.Priority < minPriority {
+				continue
+			}
+			expressions = append(expressions, expr)
+		}
+	}
+	sortExpressions(expressions, sortBy)
+
+	resp := listResponse{Items: []expressionResponse{}, Total: len(expressions), Page: page, PerPage: perPage}
+	start := (page - 1) * perPage
+	for i := start; i < len(expressions) && i < start+perPage; i++ {
+		resp.Items = append(resp.Items, newExpressionResponse(expressions[i]))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// sortExpressions は一覧を並び替え（空文字列なら取得順のまま）
+func sortExpressions(expressions []*models.Expression, sortBy string) {
+	var less func(a, b *models.Expression) bool
+	switch sortBy {
+	case "priority":
+		less = func(a, b *models.Expression) bool {
+			if a.Priority != b.Priority {
+				return a.Priority > b.Priority
+			}
+			return a.OccurrenceCount > b.OccurrenceCount
+		}
+	case "occurrence":
+		less = func(a, b *models.Expression) bool { return a.OccurrenceCount > b.OccurrenceCount }
+	case "expression":
+		less = func(a, b *models.Expression) bool {
+			return strings.ToLower(a.Expression) < strings.ToLower(b.Expression)
+		}
+	case "recent":
+		less = func(a, b *models.Expression) bool { return a.LastSeenAt.After(b.LastSeenAt) }
+	default:
+		return
+	}
+	sort.SliceStable(expressions, func(i, j int) bool { return less(expressions[i], expressions[j]) })
+}
+
+// lookup はパスの{id}の表現を取得
+func (s *Server) lookup(r *http.Request) (*models.Expression, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	expr, err := s.repo.GetExpressionByID(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if expr == nil {
+		return nil, notFound("expression not found: %d", id)
+	}
+	return expr, nil
+}
+
+func (s *Server) handleGetExpression(w http.ResponseWriter, r *http.Request) {
+	expr, err := s.lookup(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := newExpressionResponse(expr)
+	resp.Aliases, err = s.repo.GetAliases(r.Context(), expr.ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	senses, err := s.repo.ListSenses(r.Context(), expr.ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	for _, sense := range senses {
+		resp.Senses = append(resp.Senses, senseResponse{
+			ID:              sense.ID,
+			PartOfSpeech:    sense.PartOfSpeech,
+			Gloss:           sense.Gloss,
+			Definition:      sense.Definition,
+			OccurrenceCount: sense.OccurrenceCount,
+		})
+	}
+	usage, err := s.repo.GetUsage(r.Context(), expr.ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if usage != nil {
+		resp.Usage = &usageResponse{
+			Examples:     append([]models.Example{}, usage.Examples...),
+			Register:     usage.Register,
+			Note:         usage.Note,
+			Collocations: append([]string{}, usage.Collocations...),
+			Synonyms:     append([]string{}, usage.Synonyms...),
+			Antonyms:     append([]string{}, usage.Antonyms...),
+			GeneratedAt:  usage.GeneratedAt,
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleListOccurrences(w http.ResponseWriter, r *http.Request) {
+	expr, err := s.lookup(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	occurrences, err := s.repo.GetOccurrences(r.Context(), expr.ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	items := make([]occurrenceResponse, 0, len(occurrences))
+	for _, occ := range occurrences {
+		items = append(items, occurrenceResponse{
+			ID:         occ.ID,
+			Context:    occ.Context,
+			Surface:    occ.Surface,
+			Meeting:    occ.Meeting,
+			SenseID:    occ.SenseID,
+			OccurredAt: occ.OccurredAt,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"items": items})
+}
+
+// updateRequest は編集リクエスト（指定したフィールドのみ更新）
+// categoriesはカテゴリを関連の強い順に置き換え、categoryは1つのカテゴリに置き換える（空なら未分類）
+type updateRequest struct {
+	Meaning    *string   `json:"meaning"`
+	Priority   *int      `json:"priority"`
+	Category   *string   `json:"category"`
+	Categories *[]string `json:"categories"`
+	Type       *string   `json:"type"`
+}
+
+// handleUpdateExpression は表現を編集（CLIのeditと同様に手動編集フラグを立てる）
+func (s *Server) handleUpdateExpression(w http.ResponseWriter, r *http.Request) {
+	var req updateRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, badRequest("", "invalid JSON body: %v", err))
+		return
+	}
+	if req.Meaning == nil && req.Priority == nil && req.Category == nil && req.Categories == nil && req.Type == nil {
+		writeError(w, badRequest("", "nothing to update: specify meaning, priority, categories or type"))
+		return
+	}
+	if req.Category != nil && req.Categories != nil {
+		writeError(w, badRequest("categories", "specify either category or categories, not both"))
+		return
+	}
+	if req.Priority != nil && (*req.Priority < 1 || *req.Priority > 5) {
+		writeError(w, badRequest("priority", "priority must be between 1 and 5"))
+		return
+	}
+	if req.Type != nil && *req.Type != string(models.TypeWord) && *req.Type != string(models.TypePhrase) {
+		writeError(w, badRequest("type", "type must be word or phrase"))
+		return
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	expr, err := s.lookup(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var categories []string
+	if req.Category != nil {
+		categories = []string{*req.Category}
+	}
+	if req.Categories != nil {
+		categories = *req.Categories
+	}
+	if req.Category != nil || req.Categories != nil {
+		resolved, err := s.resolveCategories(r, categories)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		expr.Category, expr.Categories = "", resolved
+	}
+	if req.Meaning != nil {
+		expr.Meaning = *req.Meaning
+	}
+	if req.Priority != nil {
+		expr.Priority = *req.Priority
+		expr.BasePriority = *req.Priority
+	}
+	if req.Type != nil {
+		expr.Type = *req.Type
+	}
+	expr.ManuallyEdited = true
+
+	if err := s.repo.UpdateExpression(r.Context(), expr); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	updated, err := s.repo.GetExpressionByID(r.Context(), expr.ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newExpressionResponse(updated))
+}
+
+// resolveCategories はカテゴリ名を分類体系で検証し、識別名に揃える（親カテゴリも追加）
+func (s *Server) resolveCategories(r *http.Request, names []string) ([]string, error) {
+	taxonomy, err := s.repo.ListCategories(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	resolved, unknown := taxonomy.Resolve(names)
+	if len(unknown) > 0 {
+		return nil, badRequest("categories", "unknown category: %s", strings.Join(unknown, ", "))
+	}
+	return resolved, nil
+}
+
+// categoryResponse はAPIで返すカテゴリ
+type categoryResponse struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Description string `json:"description"`
+	Parent      string `json:"parent,omitempty"`
+}
+
+// handleListCategories はカテゴリの分類体系を表示順に返す
+func (s *Server) handleListCategories(w http.ResponseWriter, r *http.Request) {
+	taxonomy, err := s.repo.ListCategories(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	items := make([]categoryResponse, 0, len(taxonomy))
+	for _, c := range taxonomy {
+		items = append(items, categoryResponse{Name: c.Name, DisplayName: c.DisplayName, Description: c.Description, Parent: c.Parent})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"items": items})
+}
+
+// tagResponse はタグとそのタグがついた表現の数
+type tagResponse struct {
+	Name        string `json:"name"`
+	Expressions int    `json:"expressions"`
+}
+
+// handleListTags はタグの一覧を名前順に返す
+func (s *Server) handleListTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := s.repo.ListTags(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	items := make([]tagResponse, 0, len(tags))
+	for _, t := range tags {
+		items = append(items, tagResponse{Name: t.Name, Expressions: t.Count})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"items": items})
+}
@@ -0,0 +1,233 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/service"
+)
+
+// extractRequest はJSONでの抽出リクエスト
+type extractRequest struct {
+	Meeting    string   `json:"meeting"`
+	Transcript string   `json:"transcript"`
+	Tags       []string `json:"tags"` // 出現したすべての表現につけるタグ
+}
+
+// extractResponse は抽出結果
+type extractResponse struct {
+	Meeting          string                      `json:"meeting"`
+	Tags             []string                    `json:"tags,omitempty"`
+	TotalExpressions int                         `json:"total_expressions"`
+	NewExpressions   int                         `json:"new_expressions"`
+	UpdatedPriority  int                         `json:"updated_priority"`
+	Expressions      []processedExpressionResult `json:"expressions"`
+}
+
+// processedExpressionResult は抽出で登録・更新された表現
+type processedExpressionResult struct {
+	expressionResponse
+	New              bool `json:"new"`
+	PreviousPriority int  `json:"previous_priority"`
+}
+
+// handleExtract はtranscriptを受け取って抽出を実行
+// multipart/form-data（file: transcript, meeting, tags）またはJSON {"meeting", "transcript", "tags"} を受け付ける
+// Accept: text/event-stream ならServer-Sent Eventsで進捗をストリーミングする
+func (s *Server) handleExtract(w http.ResponseWriter, r *http.Request) {
+	if s.provider == nil {
+		writeError(w, &apiError{Status: http.StatusServiceUnavailable, Code: "extraction_unavailable", Message: "extraction is disabled: LLM provider is not configured"})
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	req, err := parseExtractRequest(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// 抽出は書き込みが多いので1件ずつ処理
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if acceptsEventStream(r) {
+		s.streamExtract(w, r, req)
+		return
+	}
+
+	processor, err := s.newProcessor(r, service.WithTags(req.Tags))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	result, err := processor.Process(r.Context(), req.Meeting, req.Transcript)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newExtractResponse(result))
+}
+
+// newProcessor はリクエスト時点の学習者のプロフィール・カテゴリの分類体系・タグのルールでTranscriptProcessorを作成
+func (s *Server) newProcessor(r *http.Request, opts ...service.ProcessorOption) (*service.TranscriptProcessor, error) {
+	profile, err := service.LoadProfile(r.Context(), s.repo, s.profile)
+	if err != nil {
+		return nil, err
+	}
+	taxonomy, err := s.repo.ListCategories(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	rules, err := s.repo.ListTagRules(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	opts = append([]service.ProcessorOption{
+		service.WithLogger(slog.Default()),
+		service.WithProfile(profile),
+		service.WithCategories(taxonomy),
+		service.WithTagRules(rules),
+	}, opts...)
+	return service.NewTranscriptProcessor(s.provider, s.repo, opts...), nil
+}
+
+// acceptsEventStream はクライアントが進捗のストリーミング（Server-Sent Events）を要求しているか
+func acceptsEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// streamExtract は抽出の進捗をServer-Sent Eventsで送信
+// 進捗ごとに "progress" イベント（service.ProgressEvent）を送り、最後に "result"（extractResponse）
+// または "error"（{"error": {...}}）イベントを送る
+func (s *Server) streamExtract(w http.ResponseWriter, r *http.Request, req *extractRequest) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, errors.New("streaming is not supported by the response writer"))
+		return
+	}
+
+	send := func(event string, v interface{}) {
+		if err := writeEvent(w, event, v); err != nil {
+			slog.Error("failed to write event", "event", event, "error", err)
+			return
+		}
+		flusher.Flush()
+	}
+
+	// 進捗はProcessと同じgoroutineで通知されるので、そのまま書き込める
+	processor, err := s.newProcessor(r,
+		service.WithTags(req.Tags),
+		service.WithProgress(func(ev service.ProgressEvent) { send("progress", ev) }))
+	if err != nil {
+		// ストリーミング開始前なので通常のエラーレスポンスで返す
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	result, err := processor.Process(r.Context(), req.Meeting, req.Transcript)
+	if err != nil {
+		var apiErr *apiError
+		if !errors.As(err, &apiErr) {
+			slog.Error("internal error", "error", err)
+			apiErr = &apiError{Status: http.StatusInternalServerError, Code: "internal_error", Message: "internal server error"}
+		}
+		send("error", errorResponse{Error: apiErr})
+		return
+	}
+	send("result", newExtractResponse(result))
+}
+
+// writeEvent はServer-Sent Eventsのイベントを1件書き込む（dataは1行のJSON）
+func writeEvent(w io.Writer, event string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
+
+// newExtractResponse は抽出結果をレスポンスの形式に変換
+func newExtractResponse(result *service.ProcessResult) extractResponse {
+	resp := extractResponse{
+		Meeting:          result.Meeting,
+		Tags:             result.Tags,
+		TotalExpressions: result.TotalExpressions,
+		NewExpressions:   result.NewExpressions,
+		UpdatedPriority:  result.UpdatedPriority,
+		Expressions:      make([]processedExpressionResult, 0, len(result.Expressions)),
+	}
+	for _, e := range result.Expressions {
+		resp.Expressions = append(resp.Expressions, processedExpressionResult{
+			expressionResponse: newExpressionResponse(e.Expression),
+			New:                e.New,
+			PreviousPriority:   e.PreviousPriority,
+		})
+	}
+	return resp
+}
+
+// parseExtractRequest はリクエストからtranscriptと会議名を取得
+func parseExtractRequest(r *http.Request) (*extractRequest, error) {
+	var req extractRequest
+
+	contentType := r.Header.Get("Content-Type")
+	switch {
+	case strings.HasPrefix(contentType, "multipart/form-data"):
+		file, header, err := r.FormFile("transcript")
+		if err != nil {
+			return nil, tooLargeOr(err, badRequest("transcript", "transcript file is required"))
+		}
+		defer file.Close()
+
+		content, err := io.ReadAll(file)
+		if err != nil {
+			return nil, tooLargeOr(err, badRequest("transcript", "failed to read transcript"))
+		}
+		req.Transcript = string(content)
+		req.Meeting = r.FormValue("meeting")
+		if req.Meeting == "" {
+			req.Meeting = strings.TrimSuffix(header.Filename, filepath.Ext(header.Filename))
+		}
+		req.Tags = r.MultipartForm.Value["tags"]
+	case strings.HasPrefix(contentType, "application/json"):
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			return nil, tooLargeOr(err, badRequest("", "invalid JSON body: %v", err))
+		}
+	default:
+		return nil, &apiError{Status: http.StatusUnsupportedMediaType, Code: "unsupported_media_type", Message: "use multipart/form-data or application/json"}
+	}
+
+	if strings.TrimSpace(req.Transcript) == "" {
+		return nil, badRequest("transcript", "transcript is empty")
+	}
+	tags, err := models.NormalizeTags(req.Tags)
+	if err != nil {
+		return nil, badRequest("tags", "%v", err)
+	}
+	req.Tags = tags
+	return &req, nil
+}
+
+// tooLargeOr はアップロード上限超過なら413、それ以外はfallbackを返す
+func tooLargeOr(err error, fallback *apiError) error {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return &apiError{Status: http.StatusRequestEntityTooLarge, Code: "too_large", Message: "transcript is too large"}
+	}
+	return fallback
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/service"
+)
+
+// profileFields は学習者のプロフィールの各項目
+type profileFields struct {
+	Role           string `json:"role"`
+	Domain         string `json:"domain"`
+	Level          string `json:"level"`
+	Goals          string `json:"goals"`
+	NativeLanguage string `json:"native_language"`
+}
+
+func newProfileFields(p models.LearnerProfile) profileFields {
+	return profileFields{Role: p.Role, Domain: p.Domain, Level: p.Level, Goals: p.Goals, NativeLanguage: p.NativeLanguage}
+}
+
+// profileResponse は保存されたプロフィールと、設定・デフォルト値を反映して抽出に使うプロフィール
+type profileResponse struct {
+	Stored    profileFields `json:"stored"`
+	Effective profileFields `json:"effective"`
+	UpdatedAt *time.Time    `json:"updated_at,omitempty"` // 未保存なら省略
+}
+
+func (s *Server) profileResponse(r *http.Request) (*profileResponse, error) {
+	stored, err := s.repo.GetLearnerProfile(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	effective, err := service.LoadProfile(r.Context(), s.repo, s.profile)
+	if err != nil {
+		return nil, err
+	}
+	resp := &profileResponse{Stored: newProfileFields(*stored), Effective: newProfileFields(effective)}
+	if !stored.UpdatedAt.IsZero() {
+		resp.UpdatedAt = &stored.UpdatedAt
+	}
+	return resp, nil
+}
+
+// handleGetProfile は学習者のプロフィールを返す
+func (s *Server) handleGetProfile(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.profileResponse(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handlePutProfile は学習者のプロフィールを置き換える（省略した項目は空になる）
+func (s *Server) handlePutProfile(w http.ResponseWriter, r *http.Request) {
+	var req profileFields
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, badRequest("", "invalid JSON body: %v", err))
+		return
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	profile := &models.LearnerProfile{
+		Role:           req.Role,
+		Domain:         req.Domain,
+		Level:          req.Level,
+		Goals:          req.Goals,
+		NativeLanguage: req.NativeLanguage,
+	}
+	if err := s.repo.SaveLearnerProfile(r.Context(), profile); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp, err := s.profileResponse(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,161 @@
+// Package server は表現データベースのJSON REST APIを提供する
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/llm"
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+// maxUploadSize はtranscriptのアップロード上限
+const maxUploadSize = 10 << 20
+
+// Server はstorage.RepositoryをHTTPで公開するAPIサーバー
+type Server struct {
+	repo       storage.Repository
+	provider   llm.Provider // nilなら抽出APIは503を返す
+	corsOrigin string
+	profile    models.LearnerProfile // データベースの学習者のプロフィールより優先する項目（環境変数など）
+	mux        *http.ServeMux
+
+	// writeMu はSQLiteへの書き込み（編集・抽出）を直列化する
+	writeMu sync.Mutex
+}
+
+// Option はServerの設定オプション
+type Option func(*Server)
+
+// WithCORSOrigin はCORSで許可するオリジンを設定（フロントエンドの開発サーバーなど）
+func WithCORSOrigin(origin string) Option {
+	return func(s *Server) {
+		s.corsOrigin = origin
+	}
+}
+
+// WithProfile はデータベースに保存された学習者のプロフィールより優先する項目を設定（空の項目は上書きしない）
+func WithProfile(profile models.LearnerProfile) Option {
+	return func(s *Server) {
+		s.profile = profile
+	}
+}
+
+// New は新しいServerを作成（providerがnilなら抽出APIは無効）
+func New(repo storage.Repository, provider llm.Provider, opts ...Option) *Server {
+	s := &Server{
+		repo:     repo,
+		provider: provider,
+		mux:      http.NewServeMux(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.mux.HandleFunc("GET /healthz", s.handleHealth)
+	s.mux.HandleFunc("GET /api/expressions", s.handleListExpressions)
+	s.mux.HandleFunc("GET /api/expressions/{id}", s.handleGetExpression)
+	s.mux.HandleFunc("PATCH /api/expressions/{id}", s.handleUpdateExpression)
+	s.mux.HandleFunc("GET /api/expressions/{id}/occurrences", s.handleListOccurrences)
+	s.mux.HandleFunc("GET /api/categories", s.handleListCategories)
+	s.mux.HandleFunc("GET /api/tags", s.handleListTags)
+	s.mux.HandleFunc("POST /api/extract", s.handleExtract)
+	s.mux.HandleFunc("GET /api/profile", s.handleGetProfile)
+	s.mux.HandleFunc("PUT /api/profile", s.handlePutProfile)
+
+	return s
+}
+
+// ServeHTTP はhttp.Handlerの実装
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.corsOrigin != "" {
+		w.Header().Set("Access-Control-Allow-Origin", s.corsOrigin)
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":     "ok",
+		"extraction": s.provider != nil,
+	})
+}
+
+// apiError はクライアントに返すエラー
+type apiError struct {
+	Status  int    `json:"-"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Field   string `json:"field,omitempty"`
+}
+
+func (e *apiError) Error() string {
+	return e.Message
+}
+
+// errorResponse はエラーレスポンスの形式 {"error": {...}}
+type errorResponse struct {
+	Error *apiError `json:"error"`
+}
+
+// badRequest は入力値のエラー
+func badRequest(field, format string, args ...interface{}) *apiError {
+	return &apiError{Status: http.StatusBadRequest, Code: "invalid_request", Message: fmt.Sprintf(format, args...), Field: field}
+}
+
+// notFound はリソースが見つからないエラー
+func notFound(format string, args ...interface{}) *apiError {
+	return &apiError{Status: http.StatusNotFound, Code: "not_found", Message: fmt.Sprintf(format, args...)}
+}
+
+// writeError はエラーをJSONで返す（apiError以外は内部エラーとしてログに記録し、詳細は返さない）
+func writeError(w http.ResponseWriter, err error) {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) {
+		slog.Error("internal error", "error", err)
+		apiErr = &apiError{Status: http.StatusInternalServerError, Code: "internal_error", Message: "internal server error"}
+	}
+	writeJSON(w, apiErr.Status, errorResponse{Error: apiErr})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("failed to write response", "error", err)
+	}
+}
+
+// pathID はパスの{id}を数値として取得
+func pathID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		return 0, badRequest("id", "invalid expression id: %s", r.PathValue("id"))
+	}
+	return id, nil
+}
+
+// queryInt はクエリパラメータを整数として取得（未指定ならdefaultValue）
+func queryInt(r *http.Request, name string, defaultValue, min, max int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, badRequest(name, "%s must be an integer between %d and %d", name, min, max)
+	}
+	return n, nil
+}
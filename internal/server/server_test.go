@@ -0,0 +1,358 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/llm/llmtest"
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/service"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+func newTestServer(t *testing.T) (*httptest.Server, *storage.SQLiteRepository) {
+	t.Helper()
+	repo, err := storage.NewSQLiteRepository(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+
+	ctx := context.Background()
+	fixtures := []models.Expression{
+		{Expression: "deprecate", Type: "word", Meaning: "非推奨にする", Priority: 5, Category: "engineering"},
+		{Expression: "deployment", Type: "word", Meaning: "デプロイ", Priority: 4, Category: "engineering"},
+		{Expression: "touch base", Type: "phrase", Meaning: "連絡を取る", Priority: 3, Category: "business"},
+	}
+	for _, expr := range fixtures {
+		expr := expr
+		if err := repo.SaveExpression(ctx, &expr); err != nil {
+			t.Fatalf("SaveExpression: %v", err)
+		}
+		if err := repo.AddOccurrence(ctx, &models.ExpressionOccurrence{ExpressionID: expr.ID, Context: "We talked about " + expr.Expression + ".", Meeting: "Team Sync"}); err != nil {
+			t.Fatalf("AddOccurrence: %v", err)
+		}
+	}
+
+	provider := &llmtest.Provider{
+		Phrases:    []llmtest.Phrase{{Phrase: "circle back", Context: "Let's circle back tomorrow"}},
+		Judgements: map[string]llmtest.Judgement{"circle back": {Meaning: "後で話し合う", Priority: 4, Category: "business"}},
+	}
+	server := httptest.NewServer(New(repo, provider))
+	t.Cleanup(server.Close)
+	return server, repo
+}
+
+// doJSON はリクエストを送信してレスポンスをデコード
+func doJSON(t *testing.T, method, url, contentType string, body []byte, out interface{}) int {
+	t.Helper()
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("%s %s: Content-Type = %q", method, url, ct)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestListExpressions(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	tests := []struct {
+		name     string
+		query    string
+		total    int
+		expected []string
+	}{
+		{"全件", "", 3, []string{"deprecate", "deployment", "touch base"}},
+		{"ページネーション", "?sort=expression&per_page=2&page=2", 3, []string{"touch base"}},
+		{"検索", "?q=dep&sort=expression", 2, []string{"deployment", "deprecate"}},
+		{"フィルタ", "?type=word&min_priority=5", 1, []string{"deprecate"}},
+		{"範囲外のページ", "?page=9", 3, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp listResponse
+			if status := doJSON(t, http.MethodGet, server.URL+"/api/expressions"+tt.query, "", nil, &resp); status != http.StatusOK {
+				t.Fatalf("status = %d", status)
+			}
+			if resp.Total != tt.total {
+				t.Errorf("total = %d, expected %d", resp.Total, tt.total)
+			}
+			var got []string
+			for _, item := range resp.Items {
+				got = append(got, item.Expression)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("items = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		field  string
+	}{
+		{"不正なper_page", http.MethodGet, "/api/expressions?per_page=1000", "", http.StatusBadRequest, "per_page"},
+		{"不正なtype", http.MethodGet, "/api/expressions?type=sentence", "", http.StatusBadRequest, "type"},
+		{"不正なID", http.MethodGet, "/api/expressions/abc", "", http.StatusBadRequest, "id"},
+		{"存在しないID", http.MethodGet, "/api/expressions/999", "", http.StatusNotFound, ""},
+		{"優先度の範囲外", http.MethodPatch, "/api/expressions/1", `{"priority": 9}`, http.StatusBadRequest, "priority"},
+		{"未知のフィールド", http.MethodPatch, "/api/expressions/1", `{"meening": "x"}`, http.StatusBadRequest, ""},
+		{"未知のカテゴリ", http.MethodPatch, "/api/expressions/1", `{"categories": ["frontend"]}`, http.StatusBadRequest, "categories"},
+		{"空のtranscript", http.MethodPost, "/api/extract", `{"transcript": "  "}`, http.StatusBadRequest, "transcript"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp errorResponse
+			status := doJSON(t, tt.method, server.URL+tt.path, "application/json", []byte(tt.body), &resp)
+			if status != tt.status {
+				t.Errorf("status = %d, expected %d", status, tt.status)
+			}
+			if resp.Error == nil || resp.Error.Code == "" || resp.Error.Message == "" {
+				t.Fatalf("expected structured error, got %+v", resp)
+			}
+			if resp.Error.Field != tt.field {
+				t.Errorf("field = %q, expected %q", resp.Error.Field, tt.field)
+			}
+		})
+	}
+}
+
+func TestGetAndUpdateExpression(t *testing.T) {
+	server, repo := newTestServer(t)
+	ctx := context.Background()
+	expr, _ := repo.GetExpression(ctx, "touch base")
+	url := server.URL + "/api/expressions/" + strconv.Itoa(expr.ID)
+
+	var occurrences struct {
+		Items []occurrenceResponse `json:"items"`
+	}
+	if status := doJSON(t, http.MethodGet, url+"/occurrences", "", nil, &occurrences); status != http.StatusOK {
+		t.Fatalf("status = %d", status)
+	}
+	if len(occurrences.Items) != 1 || occurrences.Items[0].Meeting != "Team Sync" {
+		t.Errorf("unexpected occurrences: %+v", occurrences.Items)
+	}
+
+	var updated expressionResponse
+	status := doJSON(t, http.MethodPatch, url, "application/json", []byte(`{"meaning": "軽く連絡を取る", "priority": 4}`), &updated)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d", status)
+	}
+	if updated.Meaning != "軽く連絡を取る" || updated.Priority != 4 || updated.Category != "business" || !updated.ManuallyEdited {
+		t.Errorf("unexpected update result: %+v", updated)
+	}
+
+	stored, _ := repo.GetExpression(ctx, "touch base")
+	if stored.Meaning != "軽く連絡を取る" || !stored.ManuallyEdited {
+		t.Errorf("update not persisted: %+v", stored)
+	}
+
+	// カテゴリは表示名でも指定でき、関連の強い順に保存される
+	status = doJSON(t, http.MethodPatch, url, "application/json", []byte(`{"categories": ["日常会話", "business"]}`), &updated)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d", status)
+	}
+	if updated.Category != "casual" || strings.Join(updated.Categories, ",") != "casual,business" {
+		t.Errorf("unexpected categories: %q %v", updated.Category, updated.Categories)
+	}
+	var list listResponse
+	if status := doJSON(t, http.MethodGet, server.URL+"/api/expressions?category=business", "", nil, &list); status != http.StatusOK {
+		t.Fatalf("status = %d", status)
+	}
+	if list.Total != 1 || list.Items[0].Expression != "touch base" {
+		t.Errorf("filter by secondary category: %+v", list.Items)
+	}
+}
+
+func TestExtract(t *testing.T) {
+	server, repo := newTestServer(t)
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, _ := mw.CreateFormFile("transcript", "retro.txt")
+	fw.Write([]byte("We should deprecate it. Let's circle back tomorrow."))
+	mw.WriteField("tags", "Project X")
+	mw.WriteField("tags", "q3,project-x")
+	mw.Close()
+
+	var resp extractResponse
+	status := doJSON(t, http.MethodPost, server.URL+"/api/extract", mw.FormDataContentType(), body.Bytes(), &resp)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d", status)
+	}
+	if resp.Meeting != "retro" {
+		t.Errorf("meeting = %q, expected file name", resp.Meeting)
+	}
+	if strings.Join(resp.Tags, ",") != "project-x,q3" {
+		t.Errorf("tags = %v", resp.Tags)
+	}
+
+	found := make(map[string]processedExpressionResult)
+	for _, e := range resp.Expressions {
+		found[e.Expression] = e
+	}
+	if e, ok := found["circle back"]; !ok || !e.New || e.Meaning != "後で話し合う" {
+		t.Errorf("'circle back' should be new: %+v", e)
+	}
+	if e, ok := found["deprecate"]; !ok || e.New || e.OccurrenceCount != 2 {
+		t.Errorf("'deprecate' should be an existing expression with 2 occurrences: %+v", e)
+	}
+
+	if expr, _ := repo.GetExpression(context.Background(), "circle back"); expr == nil {
+		t.Error("'circle back' not saved")
+	}
+
+	// 複数のtagはすべてついた表現に絞り込む（抽出前からある表現は出現した分だけ）
+	var list listResponse
+	if status := doJSON(t, http.MethodGet, server.URL+"/api/expressions?tag=q3&tag=project-x", "", nil, &list); status != http.StatusOK {
+		t.Fatalf("status = %d", status)
+	}
+	if list.Total != len(resp.Expressions) {
+		t.Errorf("filter by tags: %d expressions, expected %d", list.Total, len(resp.Expressions))
+	}
+	for _, item := range list.Items {
+		if item.Expression == "touch base" || strings.Join(item.Tags, ",") != "project-x,q3" {
+			t.Errorf("unexpected tagged expression: %+v", item)
+		}
+	}
+}
+
+func TestExtractWithoutProvider(t *testing.T) {
+	repo, err := storage.NewSQLiteRepository(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/extract", strings.NewReader(`{"transcript": "hello"}`))
+	req.Header.Set("Content-Type", "application/json")
+	New(repo, nil).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, expected 503", rec.Code)
+	}
+}
+
+func TestExtractStream(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/api/extract", strings.NewReader(`{"meeting": "Retro", "transcript": "Let's circle back tomorrow."}`))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST /api/extract: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	// "event: <name>" と "data: <json>" の組を順に読む
+	var names []string
+	var stages []service.Stage
+	var result extractResponse
+	scanner := bufio.NewScanner(resp.Body)
+	var event string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+			names = append(names, event)
+		case strings.HasPrefix(line, "data: "):
+			data := []byte(strings.TrimPrefix(line, "data: "))
+			switch event {
+			case "progress":
+				var ev service.ProgressEvent
+				if err := json.Unmarshal(data, &ev); err != nil {
+					t.Fatalf("invalid progress event: %v", err)
+				}
+				if ev.Meeting != "Retro" {
+					t.Errorf("progress meeting = %q", ev.Meeting)
+				}
+				stages = append(stages, ev.Stage)
+			case "result":
+				if err := json.Unmarshal(data, &result); err != nil {
+					t.Fatalf("invalid result event: %v", err)
+				}
+			}
+		}
+	}
+
+	if len(names) < 2 || names[len(names)-1] != "result" {
+		t.Fatalf("events = %v, expected progress events followed by result", names)
+	}
+	if stages[0] != service.StageExtractWords || stages[len(stages)-1] != service.StageDone {
+		t.Errorf("unexpected stages: %v", stages)
+	}
+	if result.Meeting != "Retro" || result.NewExpressions == 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestProfile(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	var resp profileResponse
+	if status := doJSON(t, http.MethodGet, server.URL+"/api/profile", "", nil, &resp); status != http.StatusOK {
+		t.Fatalf("GET status = %d", status)
+	}
+	if resp.UpdatedAt != nil || resp.Stored.Role != "" || resp.Effective.Role != models.DefaultLearnerProfile().Role {
+		t.Errorf("unexpected initial profile: %+v", resp)
+	}
+
+	body := []byte(`{"role": "product manager", "domain": "payments"}`)
+	if status := doJSON(t, http.MethodPut, server.URL+"/api/profile", "application/json", body, &resp); status != http.StatusOK {
+		t.Fatalf("PUT status = %d", status)
+	}
+	if resp.UpdatedAt == nil || resp.Stored.Role != "product manager" || resp.Effective.Domain != "payments" ||
+		resp.Stored.NativeLanguage != "" || resp.Effective.NativeLanguage != "日本語" {
+		t.Errorf("unexpected profile after PUT: %+v", resp)
+	}
+
+	if status := doJSON(t, http.MethodPut, server.URL+"/api/profile", "application/json", []byte(`{"team": "x"}`), nil); status != http.StatusBadRequest {
+		t.Errorf("unknown field status = %d, want 400", status)
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	transcriptpkg "github.com/mamyudapao/learn-by-transcript/internal/transcript"
+)
+
+// FileJob はバッチ処理する1つのtranscriptファイル
+type FileJob struct {
+	Path    string
+	Meeting string // 出現履歴に記録する会議名（空ならファイル名）
+}
+
+// FileResult はファイルごとの処理結果（Errがnilでなければ何も保存されていない）
+type FileResult struct {
+	Path   string
+	Result *ProcessResult
+	Err    error
+}
+
+// ProcessFiles は複数のtranscriptファイルを最大parallel件ずつ並列に処理する
+// LLMによる抽出は並列に行い、保存はファイルごとのトランザクションで1件ずつ行う
+// 1つのファイルの失敗は他のファイルの処理に影響しない。結果はjobsと同じ順序で返す
+func (p *TranscriptProcessor) ProcessFiles(ctx context.Context, jobs []FileJob, parallel int) []*FileResult {
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	results := make([]*FileResult, len(jobs))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			result, err := p.processFile(ctx, job)
+			results[i] = &FileResult{Path: job.Path, Result: result, Err: err}
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// processFile は1つのtranscriptファイルを読み込んで処理
+func (p *TranscriptProcessor) processFile(ctx context.Context, job FileJob) (*ProcessResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(job.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	transcript, err := transcriptpkg.Parse(job.Path, content)
+	if err != nil {
+		return nil, err
+	}
+
+	meeting := job.Meeting
+	if meeting == "" {
+		meeting = MeetingName(job.Path)
+	}
+
+	return p.Process(ctx, meeting, transcript)
+}
+
+// MeetingName はファイル名（拡張子を除く）を会議名として返す
+func MeetingName(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
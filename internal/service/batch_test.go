@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/llm/llmtest"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+func TestProcessFiles(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	repo, err := storage.NewSQLiteRepository(filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	files := map[string]string{
+		"standup.txt": "We deprecate the endpoint.",
+		"retro.srt":   "1\n00:00:05,000 --> 00:00:07,000\nWe deprecate the old client.\n",
+	}
+	var jobs []FileJob
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		jobs = append(jobs, FileJob{Path: path})
+	}
+	jobs = append(jobs, FileJob{Path: filepath.Join(dir, "missing.txt")})
+
+	provider := &llmtest.Provider{
+		Judgements: map[string]llmtest.Judgement{
+			"deprecate": {Meaning: "非推奨にする", Priority: 4, Category: "engineering"},
+		},
+	}
+	results := NewTranscriptProcessor(provider, repo).ProcessFiles(ctx, jobs, 4)
+
+	if len(results) != len(jobs) {
+		t.Fatalf("got %d results, want %d", len(results), len(jobs))
+	}
+	newCount := 0
+	for i, r := range results {
+		if r.Path != jobs[i].Path {
+			t.Errorf("results[%d].Path = %q, want %q (results must keep job order)", i, r.Path, jobs[i].Path)
+		}
+		if filepath.Base(r.Path) == "missing.txt" {
+			if r.Err == nil {
+				t.Error("missing file should fail")
+			}
+			continue
+		}
+		if r.Err != nil {
+			t.Fatalf("%s: %v", r.Path, r.Err)
+		}
+		if r.Result.Meeting != MeetingName(r.Path) {
+			t.Errorf("meeting = %q, want file name", r.Result.Meeting)
+		}
+		for _, e := range r.Result.Expressions {
+			if e.Expression.Expression == "deprecate" && e.New {
+				newCount++
+			}
+		}
+	}
+
+	expr, err := repo.GetExpression(ctx, "deprecate")
+	if err != nil || expr == nil {
+		t.Fatalf("GetExpression: %v", err)
+	}
+	if expr.OccurrenceCount != 2 {
+		t.Errorf("OccurrenceCount = %d, want 2 (one per file)", expr.OccurrenceCount)
+	}
+	if newCount != 1 {
+		t.Errorf("'deprecate' should be new in exactly one file, got %d", newCount)
+	}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/llm/llmtest"
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+func TestProcessWithCustomCategories(t *testing.T) {
+	ctx := context.Background()
+	repo, err := storage.NewSQLiteRepository(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	taxonomy := models.Taxonomy{
+		{Name: "engineering", DisplayName: "エンジニアリング", Description: "技術用語", Position: 1},
+		{Name: "infra", DisplayName: "インフラ", Description: "クラウド・運用・監視", Parent: "engineering", Position: 2},
+		{Name: "negotiation", DisplayName: "交渉", Description: "条件の調整・合意", Position: 3},
+	}
+	provider := &llmtest.Provider{
+		Phrases: []llmtest.Phrase{{Phrase: "meet halfway", Context: "Can we meet halfway?"}},
+		Judgements: map[string]llmtest.Judgement{
+			"meet halfway": {Meaning: "歩み寄る", Priority: 4, Categories: []string{"交渉", "small-talk"}},
+			"deprecate":    {Meaning: "非推奨にする", Priority: 5, Categories: []string{"infra"}},
+			"failover":     {Meaning: "切り替え", Priority: 3, Category: "business"},
+		},
+	}
+	processor := NewTranscriptProcessor(provider, repo, WithCategories(taxonomy))
+	if _, err := processor.Process(ctx, "Vendor Sync", "We deprecate the old cluster after failover. Can we meet halfway?"); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+
+	// プロンプトには分類体系のカテゴリが階層付きで入る
+	prompts := provider.Prompts()
+	prioritize := prompts[len(prompts)-1]
+	for _, s := range []string{"- negotiation（交渉）: 条件の調整・合意", "  - infra（インフラ）: クラウド・運用・監視"} {
+		if !strings.Contains(prioritize, s) {
+			t.Errorf("prompt does not contain %q:\n%s", s, prioritize)
+		}
+	}
+	if strings.Contains(prioritize, "casual") {
+		t.Errorf("prompt contains a category outside the taxonomy:\n%s", prioritize)
+	}
+
+	// 分類体系にないカテゴリは捨て、表示名は識別名に、親カテゴリは追加される
+	tests := []struct {
+		expression string
+		categories []string
+	}{
+		{"meet halfway", []string{"negotiation"}},
+		{"deprecate", []string{"infra", "engineering"}},
+		{"failover", nil},
+	}
+	for _, tt := range tests {
+		expr, err := repo.GetExpression(ctx, tt.expression)
+		if err != nil || expr == nil {
+			t.Fatalf("GetExpression(%q): %v", tt.expression, err)
+		}
+		if !reflect.DeepEqual(expr.Categories, tt.categories) {
+			t.Errorf("%s: categories = %v, want %v", tt.expression, expr.Categories, tt.categories)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/extractor"
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+// EnrichOptions は使い方の生成の設定
+type EnrichOptions struct {
+	Force   bool                        // 使い方を生成済みの表現も作り直す
+	Limit   int                         // 生成する表現の最大数（0なら制限なし。生成済みで対象外の表現は数えない）
+	OnBatch extractor.BatchProgressFunc // LLMのバッチごとの進捗（nilなら通知しない）
+}
+
+// EnrichResult は使い方の生成結果
+type EnrichResult struct {
+	Enriched []*EnrichedExpression // 使い方を生成して保存した表現（対象の順）
+	Skipped  int                   // 生成済みのため対象外にした表現の数
+	Missing  int                   // LLMが使い方を返さなかった表現の数
+}
+
+// EnrichedExpression は使い方を生成した表現
+type EnrichedExpression struct {
+	Expression *models.Expression
+	Usage      *models.Usage
+}
+
+// Enrich は表現の例文・使用上の注意・関連語をLLMで生成して保存（保存は1つのトランザクションで行う）
+// 意味・優先度は変えないため、手動編集した内容は上書きしない
+func Enrich(ctx context.Context, repo storage.Repository, enricher *extractor.Enricher, expressions []*models.Expression, opts EnrichOptions) (*EnrichResult, error) {
+	result := &EnrichResult{}
+	var targets []*models.Expression
+	for _, expr := range expressions {
+		if opts.Limit > 0 && len(targets) == opts.Limit {
+			break
+		}
+		if !opts.Force {
+			usage, err := repo.GetUsage(ctx, expr.ID)
+			if err != nil {
+				return nil, err
+			}
+			if usage != nil {
+				result.Skipped++
+				continue
+			}
+		}
+		if err := prepareEnrichment(ctx, repo, expr); err != nil {
+			return nil, err
+		}
+		targets = append(targets, expr)
+	}
+
+	usages, err := enricher.EnrichWithProgress(ctx, targets, opts.OnBatch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enrich expressions: %w", err)
+	}
+	result.Missing = len(targets) - len(usages)
+
+	byID := make(map[int]*models.Expression, len(targets))
+	for _, expr := range targets {
+		byID[expr.ID] = expr
+	}
+	err = repo.WithTx(ctx, func(repo storage.Repository) error {
+		for _, usage := range usages {
+			if err := repo.SaveUsage(ctx, usage); err != nil {
+				return fmt.Errorf("failed to save usage: %w", err)
+			}
+			result.Enriched = append(result.Enriched, &EnrichedExpression{Expression: byID[usage.ExpressionID], Usage: usage})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// prepareEnrichment は例文の手がかりとして登録済みの語義と最新の出現した文脈を表現に設定
+func prepareEnrichment(ctx context.Context, repo storage.Repository, expr *models.Expression) error {
+	senses, err := repo.ListSenses(ctx, expr.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list senses: %w", err)
+	}
+	expr.Senses = senses
+
+	occurrences, err := repo.GetOccurrences(ctx, expr.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get occurrences: %w", err)
+	}
+	if len(occurrences) > 0 {
+		expr.Context = occurrences[len(occurrences)-1].Context
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/extractor"
+	"github.com/mamyudapao/learn-by-transcript/internal/llm/llmtest"
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+func TestEnrich(t *testing.T) {
+	ctx := context.Background()
+	repo, err := storage.NewSQLiteRepository(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	provider := &llmtest.Provider{
+		Judgements: map[string]llmtest.Judgement{
+			"deprecate": {Meaning: "非推奨にする", PartOfSpeech: "verb", Definition: "to discourage use of", Priority: 4},
+			"failover":  {Meaning: "切り替え", Priority: 3},
+		},
+		Usages: map[string]models.Usage{
+			"deprecate": {
+				Examples: []models.Example{
+					{Sentence: "We will deprecate the v1 API next quarter.", Translation: "来四半期にv1 APIを非推奨にします。"},
+					{Sentence: "  ", Translation: "空の例文は捨てる"},
+					{Sentence: "This flag was deprecated in 2.0."},
+				},
+				Register:     "Formal",
+				Note:         "  技術文書でよく使う。  ",
+				Collocations: []string{"deprecate an API", "Deprecate an API", ""},
+				Synonyms:     []string{"phase out"},
+			},
+		},
+	}
+	processor := NewTranscriptProcessor(provider, repo)
+	if _, err := processor.Process(ctx, "Platform Sync", "Um, so we, like, deprecate the old cluster after failover."); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+
+	var expressions []*models.Expression
+	for _, e := range []string{"deprecate", "failover"} {
+		expr, err := repo.GetExpression(ctx, e)
+		if err != nil || expr == nil {
+			t.Fatalf("GetExpression(%q): %v", e, err)
+		}
+		expressions = append(expressions, expr)
+	}
+	enricher := extractor.NewEnricher(provider)
+	result, err := Enrich(ctx, repo, enricher, expressions, EnrichOptions{})
+	if err != nil {
+		t.Fatalf("Enrich: %v", err)
+	}
+	if len(result.Enriched) != 1 || result.Missing != 1 || result.Skipped != 0 {
+		t.Fatalf("result: enriched=%d missing=%d skipped=%d", len(result.Enriched), result.Missing, result.Skipped)
+	}
+
+	// 意味・語義・会議での文脈を例文の手がかりとしてプロンプトに入れる
+	prompts := provider.Prompts()
+	enrichPrompt := prompts[len(prompts)-1]
+	for _, s := range []string{
+		"1. deprecate\n   - 語義: (verb) 非推奨にする — to discourage use of\n   - 会議での文脈: Um, so we, like, deprecate the old cluster after failover",
+	} {
+		if !strings.Contains(enrichPrompt, s) {
+			t.Errorf("prompt does not contain %q:\n%s", s, enrichPrompt)
+		}
+	}
+
+	expr := expressions[0]
+	usage, err := repo.GetUsage(ctx, expr.ID)
+	if err != nil || usage == nil {
+		t.Fatalf("GetUsage: %v", err)
+	}
+	want := []models.Example{
+		{Sentence: "We will deprecate the v1 API next quarter.", Translation: "来四半期にv1 APIを非推奨にします。"},
+		{Sentence: "This flag was deprecated in 2.0."},
+	}
+	if !reflect.DeepEqual(usage.Examples, want) {
+		t.Errorf("examples = %+v", usage.Examples)
+	}
+	if usage.Register != models.RegisterFormal || usage.Note != "技術文書でよく使う。" {
+		t.Errorf("register = %q, note = %q", usage.Register, usage.Note)
+	}
+	if !reflect.DeepEqual(usage.Collocations, []string{"deprecate an API"}) || !reflect.DeepEqual(usage.Synonyms, []string{"phase out"}) || usage.Antonyms != nil {
+		t.Errorf("related words = %v / %v / %v", usage.Collocations, usage.Synonyms, usage.Antonyms)
+	}
+
+	// 生成済みの表現はForceでなければ対象外
+	calls := len(provider.Prompts())
+	result, err = Enrich(ctx, repo, enricher, []*models.Expression{expr}, EnrichOptions{})
+	if err != nil {
+		t.Fatalf("Enrich: %v", err)
+	}
+	if result.Skipped != 1 || len(result.Enriched) != 0 || len(provider.Prompts()) != calls {
+		t.Errorf("second enrich: skipped=%d enriched=%d calls=%d", result.Skipped, len(result.Enriched), len(provider.Prompts())-calls)
+	}
+
+	provider.Usages["deprecate"] = models.Usage{Examples: []models.Example{{Sentence: "They deprecated the endpoint."}}}
+	result, err = Enrich(ctx, repo, enricher, []*models.Expression{expr}, EnrichOptions{Force: true})
+	if err != nil {
+		t.Fatalf("Enrich: %v", err)
+	}
+	if len(result.Enriched) != 1 {
+		t.Fatalf("forced enrich: enriched=%d", len(result.Enriched))
+	}
+	usage, err = repo.GetUsage(ctx, expr.ID)
+	if err != nil {
+		t.Fatalf("GetUsage: %v", err)
+	}
+	if len(usage.Examples) != 1 || usage.Synonyms != nil || usage.Note != "" {
+		t.Errorf("usage was not replaced: %+v", usage)
+	}
+	// 意味・優先度は変えない
+	after, err := repo.GetExpressionByID(ctx, expr.ID)
+	if err != nil {
+		t.Fatalf("GetExpressionByID: %v", err)
+	}
+	if after.Meaning != expr.Meaning || after.Priority != expr.Priority {
+		t.Errorf("expression changed: %+v", after)
+	}
+}
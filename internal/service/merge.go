@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/scoring"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+// MergeExpressions は統合元の表現を統合先に統合し、統合後の履歴でスコアと優先度を再計算
+// 以降の抽出で統合元の表記が出現した場合は統合先の出現として記録される
+func MergeExpressions(ctx context.Context, repo storage.Repository, source, target string) (*models.Expression, error) {
+	sourceExpr, err := repo.GetExpression(ctx, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source expression: %w", err)
+	}
+	if sourceExpr == nil {
+		return nil, fmt.Errorf("expression not found: %s", source)
+	}
+	targetExpr, err := repo.GetExpression(ctx, target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target expression: %w", err)
+	}
+	if targetExpr == nil {
+		return nil, fmt.Errorf("expression not found: %s", target)
+	}
+	if sourceExpr.ID == targetExpr.ID {
+		return nil, fmt.Errorf("'%s' is already merged into '%s'", source, targetExpr.Expression)
+	}
+
+	// 統合と優先度・スコアの更新は1つのトランザクションで行い、途中で失敗したら統合も取り消す
+	var merged *models.Expression
+	err = repo.WithTx(ctx, func(repo storage.Repository) error {
+		var err error
+		merged, err = repo.MergeExpressions(ctx, sourceExpr.ID, targetExpr.ID)
+		if err != nil {
+			return fmt.Errorf("failed to merge expressions: %w", err)
+		}
+
+		// 高い方の基準の優先度を引き継ぐ（手動編集された表現はそのまま）
+		if !merged.ManuallyEdited && sourceExpr.BasePriority > merged.BasePriority {
+			merged.BasePriority = sourceExpr.BasePriority
+			if err := repo.UpdateExpression(ctx, merged); err != nil {
+				return fmt.Errorf("failed to update base priority: %w", err)
+			}
+		}
+
+		// 統合後の出現履歴でスコアと優先度を再計算
+		_, err = scoreExpression(ctx, repo, merged, scoring.DefaultParams(), time.Now())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return merged, nil
+}
+
+// SplitExpression は統合先targetに統合された表記sourceを元の表現として復元し、
+// 戻した出現履歴で両方の表現のスコアと優先度を再計算する
+// 戻り値は復元した表現と統合先の表現
+func SplitExpression(ctx context.Context, repo storage.Repository, source, target string) (*models.Expression, *models.Expression, error) {
+	targetExpr, err := repo.GetExpression(ctx, target)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get target expression: %w", err)
+	}
+	if targetExpr == nil {
+		return nil, nil, fmt.Errorf("expression not found: %s", target)
+	}
+	aliased, err := repo.GetExpression(ctx, source)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get source expression: %w", err)
+	}
+	if aliased == nil || aliased.ID != targetExpr.ID || aliased.Expression == source {
+		return nil, nil, fmt.Errorf("'%s' is not merged into '%s'", source, targetExpr.Expression)
+	}
+
+	var restored, updated *models.Expression
+	err = repo.WithTx(ctx, func(repo storage.Repository) error {
+		var err error
+		restored, updated, err = repo.SplitExpression(ctx, source)
+		if err != nil {
+			return fmt.Errorf("failed to split expression: %w", err)
+		}
+
+		// 分割後の出現履歴で両方のスコアと優先度を再計算
+		now := time.Now()
+		for _, expr := range []*models.Expression{restored, updated} {
+			if _, err := scoreExpression(ctx, repo, expr, scoring.DefaultParams(), now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return restored, updated, nil
+}
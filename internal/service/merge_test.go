@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+// failingScoreRepository はスコアの保存に失敗するリポジトリ
+type failingScoreRepository struct {
+	storage.Repository
+}
+
+func (r *failingScoreRepository) UpdateScore(ctx context.Context, id int, score float64, priority int) error {
+	return errors.New("database is locked")
+}
+
+func (r *failingScoreRepository) WithTx(ctx context.Context, fn func(storage.Repository) error) error {
+	return r.Repository.WithTx(ctx, func(tx storage.Repository) error {
+		return fn(&failingScoreRepository{Repository: tx})
+	})
+}
+
+func TestMergeAndSplitRescore(t *testing.T) {
+	ctx := context.Background()
+	repo, err := storage.NewSQLiteRepository(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	save := func(expression string, meetings ...string) {
+		expr := &models.Expression{Expression: expression, Type: string(models.TypePhrase), Priority: 3}
+		if err := repo.SaveExpression(ctx, expr); err != nil {
+			t.Fatalf("SaveExpression: %v", err)
+		}
+		for _, m := range meetings {
+			occ := &models.ExpressionOccurrence{ExpressionID: expr.ID, Context: expression, Surface: expression, Meeting: m}
+			if err := repo.AddOccurrence(ctx, occ); err != nil {
+				t.Fatalf("AddOccurrence: %v", err)
+			}
+		}
+	}
+	save("pull request", "sync")
+	save("pull requests", "retro", "planning", "1on1", "review")
+
+	// スコアを保存できなければ統合も取り消される
+	if _, err := MergeExpressions(ctx, &failingScoreRepository{Repository: repo}, "pull requests", "pull request"); err == nil {
+		t.Fatal("expected merge to fail when the score cannot be saved")
+	}
+	if got, err := repo.GetExpression(ctx, "pull requests"); err != nil || got == nil || got.Expression != "pull requests" {
+		t.Fatalf("merge was committed despite the failure: %+v, %v", got, err)
+	}
+
+	merged, err := MergeExpressions(ctx, repo, "pull requests", "pull request")
+	if err != nil {
+		t.Fatalf("MergeExpressions: %v", err)
+	}
+	if merged.Priority != 5 {
+		t.Errorf("merged priority = %d, expected 5", merged.Priority)
+	}
+
+	// 分割すると戻した出現履歴で両方の優先度が再計算される
+	source, target, err := SplitExpression(ctx, repo, "pull requests", "pull request")
+	if err != nil {
+		t.Fatalf("SplitExpression: %v", err)
+	}
+	if source.Priority != 5 || target.Priority != 3 {
+		t.Errorf("priorities after split: source=%d target=%d, expected 5 and 3", source.Priority, target.Priority)
+	}
+	stored, err := repo.GetExpression(ctx, "pull request")
+	if err != nil {
+		t.Fatalf("GetExpression: %v", err)
+	}
+	if stored.Priority != 3 || stored.Score != target.Score {
+		t.Errorf("stored target: priority=%d score=%.2f, expected 3 and %.2f", stored.Priority, stored.Score, target.Score)
+	}
+
+	if _, _, err := SplitExpression(ctx, repo, "pull requests", "pull request"); err == nil {
+		t.Error("expected error when splitting an expression that is not merged")
+	}
+}
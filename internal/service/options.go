@@ -0,0 +1,65 @@
+package service
+
+import (
+	"log/slog"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/extractor"
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+// ProcessorOption はTranscriptProcessorの設定オプション
+type ProcessorOption func(*TranscriptProcessor)
+
+// WithLogger は処理の経過やLLMのレスポンスのパース失敗を記録するロガーを設定（デフォルトは記録しない）
+func WithLogger(logger *slog.Logger) ProcessorOption {
+	return func(p *TranscriptProcessor) {
+		p.logger = logger
+	}
+}
+
+// WithProgress は進捗を受け取る関数を設定
+// ProcessFilesでは複数のgoroutineから同時に呼ばれるため、fnは並行に呼ばれても安全である必要がある
+func WithProgress(fn ProgressFunc) ProcessorOption {
+	return func(p *TranscriptProcessor) {
+		p.progress = fn
+	}
+}
+
+// WithProfile は熟語抽出・優先度判定のプロンプトに埋め込む学習者のプロフィールを設定
+// 通常はLoadProfileでデータベースと設定から読み込んだものを渡す
+func WithProfile(profile models.LearnerProfile) ProcessorOption {
+	return func(p *TranscriptProcessor) {
+		p.profile = profile
+	}
+}
+
+// WithCategories は優先度判定で選ばせるカテゴリの分類体系を設定（通常はRepository.ListCategoriesの結果）
+// LLMが分類体系にないカテゴリを返した場合は捨てる。設定しなければmodels.DefaultCategoriesを使う
+func WithCategories(taxonomy models.Taxonomy) ProcessorOption {
+	return func(p *TranscriptProcessor) {
+		p.categories = taxonomy
+	}
+}
+
+// WithTags は処理するtranscriptで出現したすべての表現につけるタグを設定（正規化済みのタグを渡す）
+func WithTags(tags []string) ProcessorOption {
+	return func(p *TranscriptProcessor) {
+		p.tags = tags
+	}
+}
+
+// WithTagRules は会議名から自動でタグをつけるルールを設定（通常はRepository.ListTagRulesの結果）
+func WithTagRules(rules []models.TagRule) ProcessorOption {
+	return func(p *TranscriptProcessor) {
+		p.tagRules = rules
+	}
+}
+
+// extractorOptions はextractorに渡すオプション
+func (p *TranscriptProcessor) extractorOptions() []extractor.Option {
+	return []extractor.Option{
+		extractor.WithLogger(p.logger),
+		extractor.WithProfile(p.profile),
+		extractor.WithCategories(p.categories),
+	}
+}
@@ -3,10 +3,16 @@ package service
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"sync"
+	"time"
 
 	"github.com/mamyudapao/learn-by-transcript/internal/extractor"
 	"github.com/mamyudapao/learn-by-transcript/internal/llm"
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/scoring"
 	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+	transcriptpkg "github.com/mamyudapao/learn-by-transcript/internal/transcript"
 )
 
 // TranscriptProcessor はtranscript処理のメインロジック
@@ -15,106 +21,231 @@ type TranscriptProcessor struct {
 	phraseExtractor *extractor.PhraseExtractor
 	prioritizer     *extractor.Prioritizer
 	repository      storage.Repository
+	scoring         scoring.Params // 優先度のスコア計算の重み
+	profile         models.LearnerProfile
+	categories      models.Taxonomy
+	tags            []string         // 出現したすべての表現につけるタグ
+	tagRules        []models.TagRule // 会議名から自動でつけるタグのルール
+	logger          *slog.Logger
+	progress        ProgressFunc // nilなら進捗を通知しない
+	saveMu          sync.Mutex   // DBへの保存を直列化（SQLiteの書き込みトランザクションは同時に1つまで）
 }
 
 // NewTranscriptProcessor は新しいTranscriptProcessorを作成
-func NewTranscriptProcessor(provider llm.Provider, repo storage.Repository) *TranscriptProcessor {
-	return &TranscriptProcessor{
-		wordExtractor:   extractor.NewWordExtractor(),
-		phraseExtractor: extractor.NewPhraseExtractor(provider),
-		prioritizer:     extractor.NewPrioritizer(provider),
-		repository:      repo,
+func NewTranscriptProcessor(provider llm.Provider, repo storage.Repository, opts ...ProcessorOption) *TranscriptProcessor {
+	p := &TranscriptProcessor{
+		wordExtractor: extractor.NewWordExtractor(),
+		repository:    repo,
+		scoring:       scoring.DefaultParams(),
+		logger:        slog.New(slog.DiscardHandler),
 	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.phraseExtractor = extractor.NewPhraseExtractor(provider, p.extractorOptions()...)
+	p.prioritizer = extractor.NewPrioritizer(provider, p.extractorOptions()...)
+	return p
 }
 
 // ProcessResult は処理結果
 type ProcessResult struct {
+	Meeting          string
+	Tags             []string // 出現した表現につけたタグ（WithTagsと会議名に一致したルールのタグ）
 	TotalExpressions int
 	NewExpressions   int
 	UpdatedPriority  int
+	Expressions      []*ProcessedExpression // 今回の処理で登録・出現記録した表現（重複なし、処理順）
+}
+
+// ProcessedExpression は処理で登録・更新された表現
+type ProcessedExpression struct {
+	Expression       *models.Expression // 保存後の表現（優先度更新を反映済み）
+	New              bool               // 今回新規登録したか
+	PreviousPriority int                // 処理前の優先度（新規なら登録時の優先度）
+	Contexts         []ProcessedContext // 今回のtranscriptでの文脈
+}
+
+// PriorityChanged は今回の処理で優先度が変わったか
+func (e *ProcessedExpression) PriorityChanged() bool {
+	return !e.New && e.PreviousPriority != e.Expression.Priority
 }
 
-// Process はtranscriptを処理して表現を抽出・保存
-func (p *TranscriptProcessor) Process(ctx context.Context, transcript string) (*ProcessResult, error) {
-	result := &ProcessResult{}
+// ProcessedContext はtranscript中の文脈とその位置
+type ProcessedContext struct {
+	Text         string
+	Timestamp    time.Duration // transcript中のタイムスタンプ（HasTimestampがtrueの場合のみ有効）
+	HasTimestamp bool
+}
+
+// Process はtranscriptを処理して表現を抽出・保存（出現履歴には会議名meetingを記録）
+// 保存は1つのトランザクションで行い、途中で失敗した場合は何も保存しない
+func (p *TranscriptProcessor) Process(ctx context.Context, meeting, transcript string) (*ProcessResult, error) {
+	expressions, err := p.analyze(ctx, meeting, transcript)
+	if err != nil {
+		return nil, err
+	}
+	result, err := p.save(ctx, meeting, transcript, expressions)
+	if err != nil {
+		return nil, err
+	}
+
+	p.logger.Info("processed transcript", "meeting", meeting, "expressions", result.TotalExpressions,
+		"new", result.NewExpressions, "updated_priority", result.UpdatedPriority)
+	p.report(meeting, StageDone, 1, 1, 0)
+	return result, nil
+}
 
-	fmt.Println("Step 1: 単語抽出中...")
+// analyze はtranscriptから表現を抽出し、優先度・意味・カテゴリ・語義を判定（DBは登録済みの語義の読み込みのみ）
+func (p *TranscriptProcessor) analyze(ctx context.Context, meeting, transcript string) ([]*models.Expression, error) {
 	// 1. 単語抽出
+	p.report(meeting, StageExtractWords, 0, 1, 0)
 	words := p.wordExtractor.ExtractWithContext(transcript)
-	fmt.Printf("  抽出された単語: %d個\n", len(words))
+	p.logger.Info("extracted words", "meeting", meeting, "count", len(words))
+	p.report(meeting, StageExtractWords, 1, 1, len(words))
 
-	fmt.Println("\nStep 2: 熟語・慣用表現抽出中（LLM使用）...")
 	// 2. 熟語・慣用表現抽出
+	p.report(meeting, StageExtractPhrases, 0, 1, 0)
 	phrases, err := p.phraseExtractor.Extract(ctx, transcript)
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract phrases: %w", err)
 	}
-	fmt.Printf("  抽出された熟語: %d個\n", len(phrases))
+	p.logger.Info("extracted phrases", "meeting", meeting, "count", len(phrases))
+	p.report(meeting, StageExtractPhrases, 1, 1, len(phrases))
 
 	// 3. 全表現をマージ
 	allExpressions := append(words, phrases...)
-	result.TotalExpressions = len(allExpressions)
 
-	fmt.Println("\nStep 3: 優先度・意味・カテゴリ判定中（LLM使用）...")
-	// 4. 優先度・意味・カテゴリ判定
-	if err := p.prioritizer.Prioritize(ctx, allExpressions, transcript); err != nil {
+	// 4. 優先度・意味・カテゴリ・語義判定（登録済みの表現は既知の語義から選ばせる）
+	if err := p.loadSenses(ctx, allExpressions); err != nil {
+		return nil, err
+	}
+	onBatch := func(done, total int) {
+		p.report(meeting, StagePrioritize, done, total, 0)
+	}
+	if err := p.prioritizer.PrioritizeWithProgress(ctx, allExpressions, transcript, onBatch); err != nil {
 		return nil, fmt.Errorf("failed to prioritize expressions: %w", err)
 	}
-	fmt.Println("  判定完了")
 
-	fmt.Println("\nStep 4: データベースに保存中...")
+	return allExpressions, nil
+}
+
+// save は抽出した表現をトランザクション内で保存
+func (p *TranscriptProcessor) save(ctx context.Context, meeting, transcript string, allExpressions []*models.Expression) (*ProcessResult, error) {
+	p.saveMu.Lock()
+	defer p.saveMu.Unlock()
+
+	var result *ProcessResult
+	err := p.repository.WithTx(ctx, func(repo storage.Repository) error {
+		var err error
+		result, err = p.saveExpressions(ctx, repo, meeting, transcript, allExpressions)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// saveExpressions は表現と出現履歴を保存し、出現頻度に基づいて優先度を更新
+func (p *TranscriptProcessor) saveExpressions(ctx context.Context, repo storage.Repository, meeting, transcript string, allExpressions []*models.Expression) (*ProcessResult, error) {
+	result := &ProcessResult{Meeting: meeting, TotalExpressions: len(allExpressions)}
+	processed := make(map[int]*ProcessedExpression)
+
+	// record は処理した表現を結果に追加（同じ表現は文脈のみ追加）
+	record := func(expr *models.Expression, isNew bool, previousPriority int, contextText string) {
+		entry, ok := processed[expr.ID]
+		if !ok {
+			entry = &ProcessedExpression{New: isNew, PreviousPriority: previousPriority}
+			processed[expr.ID] = entry
+			result.Expressions = append(result.Expressions, entry)
+		}
+		entry.Expression = expr
+		if contextText == "" {
+			return
+		}
+		c := ProcessedContext{Text: contextText}
+		c.Timestamp, c.HasTimestamp = transcriptpkg.FindTimestamp(transcript, contextText)
+		entry.Contexts = append(entry.Contexts, c)
+	}
+
 	// 5. データベースに保存（重複チェック含む）
-	for _, expr := range allExpressions {
+	for i, expr := range allExpressions {
+		p.report(meeting, StageSave, i, len(allExpressions), 0)
+
 		// 既存チェック
-		exists, err := p.repository.ExpressionExists(ctx, expr.Expression)
+		exists, err := repo.ExpressionExists(ctx, expr.Expression)
 		if err != nil {
 			return nil, fmt.Errorf("failed to check existence: %w", err)
 		}
 
 		if exists {
 			// 既存の場合: 出現履歴を追加
-			existing, err := p.repository.GetExpression(ctx, expr.Expression)
+			existing, err := repo.GetExpression(ctx, expr.Expression)
 			if err != nil {
 				return nil, fmt.Errorf("failed to get existing expression: %w", err)
 			}
 
-			// 出現履歴追加
-			if err := p.repository.AddOccurrence(ctx, existing.ID, expr.Context); err != nil {
+			// 出現履歴追加（文脈での語義を記録）
+			senseID, err := saveSense(ctx, repo, existing.ID, expr.Sense)
+			if err != nil {
+				return nil, err
+			}
+			occ := &models.ExpressionOccurrence{ExpressionID: existing.ID, Context: expr.Context, Surface: expr.Expression, Meeting: meeting, SenseID: senseID}
+			if err := repo.AddOccurrence(ctx, occ); err != nil {
 				return nil, fmt.Errorf("failed to add occurrence: %w", err)
 			}
 
 			// 出現回数を取得（トリガーで更新されている）
-			updated, err := p.repository.GetExpression(ctx, expr.Expression)
+			updated, err := repo.GetExpression(ctx, expr.Expression)
 			if err != nil {
 				return nil, fmt.Errorf("failed to get updated expression: %w", err)
 			}
 
-			// 出現頻度に基づいて優先度を更新
-			newPriority := extractor.UpdatePriorityBasedOnOccurrence(updated.Priority, updated.OccurrenceCount)
-			if newPriority != updated.Priority {
-				if err := p.repository.UpdatePriority(ctx, updated.ID, newPriority); err != nil {
-					return nil, fmt.Errorf("failed to update priority: %w", err)
-				}
+			previousPriority := updated.Priority
+			if prev, ok := processed[updated.ID]; ok {
+				previousPriority = prev.PreviousPriority
+			}
+
+			// 出現履歴・復習履歴からスコアを再計算して優先度を更新（手動編集された表現は優先度を変えない）
+			priorityBefore := updated.Priority
+			breakdown, err := scoreExpression(ctx, repo, updated, p.scoring, time.Now())
+			if err != nil {
+				return nil, err
+			}
+			if updated.Priority != priorityBefore {
 				result.UpdatedPriority++
-				fmt.Printf("  '%s' の優先度を更新: %d → %d (出現回数: %d)\n",
-					expr.Expression, updated.Priority, newPriority, updated.OccurrenceCount)
+				p.logger.Debug("updated priority", "expression", expr.Expression, "from", priorityBefore,
+					"to", updated.Priority, "score", breakdown.Score(), "occurrences", updated.OccurrenceCount)
 			}
+			record(updated, false, previousPriority, expr.Context)
 		} else {
 			// 新規の場合: 保存
-			if err := p.repository.SaveExpression(ctx, expr); err != nil {
+			if err := repo.SaveExpression(ctx, expr); err != nil {
 				return nil, fmt.Errorf("failed to save expression: %w", err)
 			}
 
-			// 最初の出現履歴を追加
-			if err := p.repository.AddOccurrence(ctx, expr.ID, expr.Context); err != nil {
+			// 最初の語義と出現履歴を追加
+			senseID, err := saveSense(ctx, repo, expr.ID, expr.Sense)
+			if err != nil {
+				return nil, err
+			}
+			occ := &models.ExpressionOccurrence{ExpressionID: expr.ID, Context: expr.Context, Surface: expr.Expression, Meeting: meeting, SenseID: senseID}
+			if err := repo.AddOccurrence(ctx, occ); err != nil {
 				return nil, fmt.Errorf("failed to add first occurrence: %w", err)
 			}
 
+			record(expr, true, expr.Priority, expr.Context)
 			result.NewExpressions++
-			fmt.Printf("  新規: '%s' (優先度: %d, カテゴリ: %s)\n",
-				expr.Expression, expr.Priority, expr.Category)
+			p.logger.Debug("saved new expression", "expression", expr.Expression, "priority", expr.Priority,
+				"categories", expr.Categories)
 		}
 	}
+	p.report(meeting, StageSave, len(allExpressions), len(allExpressions), 0)
+
+	if err := p.tagExpressions(ctx, repo, result); err != nil {
+		return nil, err
+	}
 
 	return result, nil
 }
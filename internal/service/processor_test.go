@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/llm/llmtest"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+func TestProcessResultExpressions(t *testing.T) {
+	ctx := context.Background()
+	repo, err := storage.NewSQLiteRepository(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	provider := &llmtest.Provider{
+		Phrases: []llmtest.Phrase{{Phrase: "circle back", Context: "Let's circle back on the rollout"}},
+		Judgements: map[string]llmtest.Judgement{
+			"deprecate":   {Meaning: "非推奨にする", Priority: 5, Category: "engineering"},
+			"circle back": {Meaning: "後で話し合う", Priority: 3, Category: "business"},
+		},
+	}
+	processor := NewTranscriptProcessor(provider, repo)
+
+	transcript := "00:00:10\nWe deprecate the endpoint.\n00:01:05\nLet's circle back on the rollout.\n"
+	result, err := processor.Process(ctx, "Weekly Sync", transcript)
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if result.Meeting != "Weekly Sync" || len(result.Expressions) != result.NewExpressions {
+		t.Fatalf("unexpected result: meeting=%q expressions=%d new=%d", result.Meeting, len(result.Expressions), result.NewExpressions)
+	}
+
+	byName := make(map[string]*ProcessedExpression)
+	for _, e := range result.Expressions {
+		byName[e.Expression.Expression] = e
+	}
+	phrase, ok := byName["circle back"]
+	if !ok || !phrase.New || phrase.Expression.ID == 0 {
+		t.Fatalf("'circle back' should be recorded as new with an ID: %+v", phrase)
+	}
+	if len(phrase.Contexts) != 1 || !phrase.Contexts[0].HasTimestamp || phrase.Contexts[0].Timestamp != 65*time.Second {
+		t.Errorf("unexpected contexts for 'circle back': %+v", phrase.Contexts)
+	}
+	if word := byName["deprecate"]; word == nil || word.Expression.Meaning != "非推奨にする" {
+		t.Errorf("'deprecate' not recorded with its meaning: %+v", word)
+	}
+
+	// 2回目は既存の表現として記録される
+	result, err = processor.Process(ctx, "Retro", "We deprecate the endpoint again.")
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	var again *ProcessedExpression
+	for _, e := range result.Expressions {
+		if e.Expression.Expression == "deprecate" {
+			again = e
+		}
+	}
+	if again == nil || again.New || again.Expression.OccurrenceCount != 2 {
+		t.Errorf("'deprecate' should be recorded as an existing expression with 2 occurrences: %+v", again)
+	}
+}
+
+func TestProcessProgress(t *testing.T) {
+	repo, err := storage.NewSQLiteRepository(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	provider := &llmtest.Provider{Phrases: []llmtest.Phrase{{Phrase: "circle back", Context: "Let's circle back"}}}
+	var events []ProgressEvent
+	processor := NewTranscriptProcessor(provider, repo, WithProgress(func(ev ProgressEvent) {
+		events = append(events, ev)
+	}))
+
+	result, err := processor.Process(context.Background(), "Weekly Sync", "We deprecate the endpoint. Let's circle back.")
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+
+	// 段階は順に進み、各段階のCurrentは0からTotalまで増える
+	order := []Stage{StageExtractWords, StageExtractPhrases, StagePrioritize, StageSave, StageDone}
+	stageIndex, last := 0, ProgressEvent{}
+	for _, ev := range events {
+		if ev.Meeting != "Weekly Sync" {
+			t.Errorf("meeting = %q", ev.Meeting)
+		}
+		if ev.Stage != order[stageIndex] {
+			stageIndex++
+			if stageIndex >= len(order) || ev.Stage != order[stageIndex] {
+				t.Fatalf("unexpected stage order: %+v", events)
+			}
+			if last.Current != last.Total {
+				t.Errorf("stage %s ended at %d/%d", last.Stage, last.Current, last.Total)
+			}
+		} else if last.Stage == ev.Stage && ev.Current != last.Current+1 {
+			t.Errorf("stage %s progressed from %d to %d", ev.Stage, last.Current, ev.Current)
+		}
+		last = ev
+	}
+	if last.Stage != StageDone {
+		t.Errorf("last event = %+v, expected done", last)
+	}
+
+	found := make(map[Stage]int)
+	for _, ev := range events {
+		if ev.Current == ev.Total {
+			found[ev.Stage] = ev.Found
+		}
+	}
+	if found[StageExtractPhrases] != 1 || found[StageExtractWords]+found[StageExtractPhrases] != result.TotalExpressions {
+		t.Errorf("found counts %v do not match %d expressions", found, result.TotalExpressions)
+	}
+}
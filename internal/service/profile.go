@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+// LoadProfile はデータベースに保存された学習者のプロフィールに、overrideの空でない項目
+// （環境変数などの設定）を上書きし、未設定の項目をデフォルト値で補ったプロフィールを返す
+func LoadProfile(ctx context.Context, repo storage.Repository, override models.LearnerProfile) (models.LearnerProfile, error) {
+	stored, err := repo.GetLearnerProfile(ctx)
+	if err != nil {
+		return models.LearnerProfile{}, fmt.Errorf("failed to load learner profile: %w", err)
+	}
+	return models.DefaultLearnerProfile().Merge(*stored).Merge(override), nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/llm/llmtest"
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+func TestLoadProfileAndPrompts(t *testing.T) {
+	ctx := context.Background()
+	repo, err := storage.NewSQLiteRepository(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	if err := repo.SaveLearnerProfile(ctx, &models.LearnerProfile{Role: "product manager", Domain: "payments", Level: "TOEIC 600"}); err != nil {
+		t.Fatalf("SaveLearnerProfile: %v", err)
+	}
+
+	// 設定の空でない項目がデータベースより優先され、未設定の項目はデフォルト値
+	profile, err := LoadProfile(ctx, repo, models.LearnerProfile{Level: "advanced"})
+	if err != nil {
+		t.Fatalf("LoadProfile: %v", err)
+	}
+	want := models.LearnerProfile{Role: "product manager", Domain: "payments", Level: "advanced", NativeLanguage: "日本語"}
+	if profile != want {
+		t.Errorf("LoadProfile = %+v, want %+v", profile, want)
+	}
+
+	provider := &llmtest.Provider{Phrases: []llmtest.Phrase{{Phrase: "circle back", Context: "Let's circle back"}}}
+	processor := NewTranscriptProcessor(provider, repo, WithProfile(profile))
+	if _, err := processor.Process(ctx, "Weekly Sync", "We deprecate the endpoint. Let's circle back."); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+
+	prompts := provider.Prompts()
+	if len(prompts) < 2 {
+		t.Fatalf("expected phrase and prioritize prompts, got %d", len(prompts))
+	}
+	for _, p := range prompts {
+		for _, s := range []string{"product manager（payments）", "英語のレベル: advanced", "母語: 日本語"} {
+			if !strings.Contains(p, s) {
+				t.Errorf("prompt does not contain %q:\n%s", s, p)
+			}
+		}
+		if strings.Contains(p, "ソフトウェアエンジニア") {
+			t.Errorf("prompt still contains the default role:\n%s", p)
+		}
+	}
+}
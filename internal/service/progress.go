@@ -0,0 +1,32 @@
+package service
+
+// Stage はtranscript処理の段階
+type Stage string
+
+const (
+	StageExtractWords   Stage = "extract_words"   // 単語抽出
+	StageExtractPhrases Stage = "extract_phrases" // 熟語・慣用表現抽出（LLM）
+	StagePrioritize     Stage = "prioritize"      // 優先度・意味・カテゴリ判定（LLM、バッチ単位）
+	StageSave           Stage = "save"            // データベースへの保存（表現単位）
+	StageDone           Stage = "done"            // 完了
+)
+
+// ProgressEvent はtranscript処理の進捗
+// 各段階の開始時にCurrent=0で通知し、段階内の単位（バッチ・表現）が終わるたびにCurrentを増やして通知する
+type ProgressEvent struct {
+	Meeting string `json:"meeting"`
+	Stage   Stage  `json:"stage"`
+	Current int    `json:"current"`
+	Total   int    `json:"total"`
+	Found   int    `json:"found,omitempty"` // 抽出段階の終了時に見つかった表現の数
+}
+
+// ProgressFunc は進捗を受け取る関数
+type ProgressFunc func(ProgressEvent)
+
+// report は進捗を通知
+func (p *TranscriptProcessor) report(meeting string, stage Stage, current, total, found int) {
+	if p.progress != nil {
+		p.progress(ProgressEvent{Meeting: meeting, Stage: stage, Current: current, Total: total, Found: found})
+	}
+}
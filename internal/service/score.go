@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/scoring"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+// ScoreChange は再計算で優先度が変わった表現
+type ScoreChange struct {
+	Expression       *models.Expression // 再計算後の表現
+	PreviousPriority int
+	PreviousScore    float64
+	Breakdown        scoring.Breakdown
+}
+
+// RescoreResult はスコアの再計算結果
+type RescoreResult struct {
+	Expressions int            // 再計算した表現の数
+	Changes     []*ScoreChange // 優先度が変わった表現（再計算順）
+}
+
+// Rescore はすべての表現のスコアと優先度を履歴から再計算して保存（1つのトランザクションで行う）
+// 手動編集された表現はスコアのみ更新し、優先度は変えない
+func Rescore(ctx context.Context, repo storage.Repository, params scoring.Params, now time.Time) (*RescoreResult, error) {
+	result := &RescoreResult{}
+	err := repo.WithTx(ctx, func(repo storage.Repository) error {
+		expressions, err := repo.GetAllExpressions(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get expressions: %w", err)
+		}
+
+		for _, expr := range expressions {
+			previousPriority, previousScore := expr.Priority, expr.Score
+			breakdown, err := scoreExpression(ctx, repo, expr, params, now)
+			if err != nil {
+				return err
+			}
+			result.Expressions++
+			if expr.Priority != previousPriority {
+				result.Changes = append(result.Changes, &ScoreChange{
+					Expression:       expr,
+					PreviousPriority: previousPriority,
+					PreviousScore:    previousScore,
+					Breakdown:        breakdown,
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// RescoreExpression は1つの表現のスコアと優先度を履歴から再計算して保存（復習の直後など）
+func RescoreExpression(ctx context.Context, repo storage.Repository, expr *models.Expression, params scoring.Params, now time.Time) (scoring.Breakdown, error) {
+	return scoreExpression(ctx, repo, expr, params, now)
+}
+
+// scoreExpression は出現履歴と復習履歴からスコアを計算し、変わっていれば保存してexprに反映
+func scoreExpression(ctx context.Context, repo storage.Repository, expr *models.Expression, params scoring.Params, now time.Time) (scoring.Breakdown, error) {
+	occurrences, err := repo.GetOccurrences(ctx, expr.ID)
+	if err != nil {
+		return scoring.Breakdown{}, fmt.Errorf("failed to get occurrences: %w", err)
+	}
+	grades, err := repo.GetRecentGrades(ctx, expr.ID, params.RecentGrades)
+	if err != nil {
+		return scoring.Breakdown{}, fmt.Errorf("failed to get review grades: %w", err)
+	}
+
+	breakdown := params.Compute(scoring.Input{BasePriority: expr.BasePriority, Occurrences: occurrences, Grades: grades}, now)
+	score := breakdown.Score()
+	priority := breakdown.Priority()
+	// 手動編集された表現は優先度を自動更新しない
+	if expr.ManuallyEdited {
+		priority = expr.Priority
+	}
+
+	if priority != expr.Priority || math.Abs(score-expr.Score) > 1e-9 {
+		if err := repo.UpdateScore(ctx, expr.ID, score, priority); err != nil {
+			return scoring.Breakdown{}, fmt.Errorf("failed to update score: %w", err)
+		}
+		expr.Score, expr.Priority = score, priority
+	}
+
+	return breakdown, nil
+}
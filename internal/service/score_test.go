@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/scoring"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+func TestRescore(t *testing.T) {
+	ctx := context.Background()
+	repo, err := storage.NewSQLiteRepository(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	save := func(expression string, priority int, edited bool, meetings ...string) *models.Expression {
+		expr := &models.Expression{Expression: expression, Type: string(models.TypePhrase), Priority: priority, ManuallyEdited: edited}
+		if err := repo.SaveExpression(ctx, expr); err != nil {
+			t.Fatalf("SaveExpression: %v", err)
+		}
+		for _, m := range meetings {
+			if err := repo.AddOccurrence(ctx, &models.ExpressionOccurrence{ExpressionID: expr.ID, Context: expression, Meeting: m}); err != nil {
+				t.Fatalf("AddOccurrence: %v", err)
+			}
+		}
+		return expr
+	}
+	frequent := save("circle back", 3, false, "sync", "retro", "planning", "1on1")
+	edited := save("touch base", 2, true, "sync", "retro", "planning", "1on1")
+	save("deprecate", 4, false, "sync")
+
+	now := time.Now().Add(time.Minute)
+	result, err := Rescore(ctx, repo, scoring.DefaultParams(), now)
+	if err != nil {
+		t.Fatalf("Rescore: %v", err)
+	}
+	if result.Expressions != 3 || len(result.Changes) != 1 {
+		t.Fatalf("expected 3 rescored and 1 changed, got %d and %+v", result.Expressions, result.Changes)
+	}
+	change := result.Changes[0]
+	if change.Expression.ID != frequent.ID || change.PreviousPriority != 3 || change.Expression.Priority != 5 {
+		t.Errorf("unexpected change: %+v", change)
+	}
+
+	got, err := repo.GetExpression(ctx, "circle back")
+	if err != nil {
+		t.Fatalf("GetExpression: %v", err)
+	}
+	if got.Priority != 5 || got.BasePriority != 3 || got.Score < 4.5 {
+		t.Errorf("stored expression: priority=%d base=%d score=%.2f", got.Priority, got.BasePriority, got.Score)
+	}
+
+	// 手動編集された表現はスコアのみ更新される
+	got, err = repo.GetExpression(ctx, "touch base")
+	if err != nil {
+		t.Fatalf("GetExpression: %v", err)
+	}
+	if got.ID != edited.ID || got.Priority != 2 || got.Score < 3.5 {
+		t.Errorf("manually edited expression: priority=%d score=%.2f", got.Priority, got.Score)
+	}
+
+	// 履歴が変わらなければ再計算しても変化はない
+	result, err = Rescore(ctx, repo, scoring.DefaultParams(), now)
+	if err != nil {
+		t.Fatalf("Rescore: %v", err)
+	}
+	if len(result.Changes) != 0 {
+		t.Errorf("second rescore changed %d expressions", len(result.Changes))
+	}
+}
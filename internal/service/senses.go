@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+// loadSenses は登録済みの表現の語義を読み込み、語義の判定の候補としてexpr.Sensesに設定
+func (p *TranscriptProcessor) loadSenses(ctx context.Context, expressions []*models.Expression) error {
+	// 並列処理中に他のファイルの保存と重ならないよう、保存と同じロックで読み込む
+	p.saveMu.Lock()
+	defer p.saveMu.Unlock()
+
+	for _, expr := range expressions {
+		existing, err := p.repository.GetExpression(ctx, expr.Expression)
+		if err != nil {
+			return fmt.Errorf("failed to get existing expression: %w", err)
+		}
+		if existing == nil {
+			continue
+		}
+		if expr.Senses, err = p.repository.ListSenses(ctx, existing.ID); err != nil {
+			return fmt.Errorf("failed to list senses: %w", err)
+		}
+	}
+	return nil
+}
+
+// saveSense はPrioritizerが判定した語義を表現の語義として保存し、そのIDを返す（語義がなければ0）
+// 登録済みの語義を選んだ場合や、品詞と意味が同じ語義が既にある場合は追加しない
+func saveSense(ctx context.Context, repo storage.Repository, expressionID int, sense *models.Sense) (int, error) {
+	if sense == nil {
+		return 0, nil
+	}
+
+	senses, err := repo.ListSenses(ctx, expressionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list senses: %w", err)
+	}
+	for _, s := range senses {
+		if (sense.ID != 0 && s.ID == sense.ID) || s.Same(sense) {
+			return s.ID, nil
+		}
+	}
+
+	added := &models.Sense{
+		ExpressionID: expressionID,
+		PartOfSpeech: sense.PartOfSpeech,
+		Gloss:        sense.Gloss,
+		Definition:   sense.Definition,
+	}
+	if err := repo.AddSense(ctx, added); err != nil {
+		return 0, err
+	}
+	return added.ID, nil
+}
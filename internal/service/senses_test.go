@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/llm/llmtest"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+func TestProcessDisambiguatesSenses(t *testing.T) {
+	ctx := context.Background()
+	repo, err := storage.NewSQLiteRepository(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	// 文脈で語義を判定し、登録済みの語義と同じなら番号を返す
+	provider := &llmtest.Provider{
+		JudgeFunc: func(expression, context string) llmtest.Judgement {
+			if expression != "ship" {
+				return llmtest.Judgement{Priority: 2}
+			}
+			if strings.Contains(context, "warehouse") {
+				return llmtest.Judgement{Meaning: "出荷する", PartOfSpeech: "verb", Definition: "to send goods", Priority: 3, Sense: 1}
+			}
+			return llmtest.Judgement{Meaning: "リリースする", PartOfSpeech: "Verb", Definition: "to release software", Priority: 4}
+		},
+	}
+	processor := NewTranscriptProcessor(provider, repo)
+
+	transcripts := []struct{ meeting, text string }{
+		{"Logistics", "We ship from the warehouse."},
+		{"Release Planning", "Can we ship on Friday?"},
+		{"Ops Sync", "They ship pallets from the warehouse."},
+	}
+	for _, tt := range transcripts {
+		if _, err := processor.Process(ctx, tt.meeting, tt.text); err != nil {
+			t.Fatalf("Process(%s): %v", tt.meeting, err)
+		}
+	}
+
+	// 2回目の判定では登録済みの語義が候補としてプロンプトに入る
+	prompts := provider.Prompts()
+	if !strings.Contains(prompts[3], "   - 1 (verb) 出荷する — to send goods") {
+		t.Errorf("prompt does not list the known sense:\n%s", prompts[3])
+	}
+
+	expr, err := repo.GetExpression(ctx, "ship")
+	if err != nil || expr == nil {
+		t.Fatalf("GetExpression: %v", err)
+	}
+	if expr.Meaning != "出荷する" {
+		t.Errorf("meaning = %q, expected the first meaning", expr.Meaning)
+	}
+	senses, err := repo.ListSenses(ctx, expr.ID)
+	if err != nil {
+		t.Fatalf("ListSenses: %v", err)
+	}
+	if len(senses) != 2 {
+		t.Fatalf("expected 2 senses, got %d", len(senses))
+	}
+	if senses[0].Gloss != "出荷する" || senses[0].OccurrenceCount != 2 ||
+		senses[1].Gloss != "リリースする" || senses[1].PartOfSpeech != "verb" || senses[1].OccurrenceCount != 1 {
+		t.Errorf("unexpected senses: %+v %+v", senses[0], senses[1])
+	}
+
+	occurrences, err := repo.GetOccurrences(ctx, expr.ID)
+	if err != nil {
+		t.Fatalf("GetOccurrences: %v", err)
+	}
+	for _, occ := range occurrences {
+		want := senses[0].ID
+		if occ.Meeting == "Release Planning" {
+			want = senses[1].ID
+		}
+		if occ.SenseID != want {
+			t.Errorf("%s: sense = %d, want %d", occ.Meeting, occ.SenseID, want)
+		}
+	}
+}
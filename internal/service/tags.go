@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+// meetingTags は会議で出現した表現につけるタグ（WithTagsのタグと、会議名に一致したルールのタグ）
+func (p *TranscriptProcessor) meetingTags(meeting string) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, tag := range append(append([]string(nil), p.tags...), models.MeetingTags(p.tagRules, meeting)...) {
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// tagExpressions は処理で登録・出現記録した表現に会議のタグをつける
+func (p *TranscriptProcessor) tagExpressions(ctx context.Context, repo storage.Repository, result *ProcessResult) error {
+	tags := p.meetingTags(result.Meeting)
+	if len(tags) == 0 {
+		return nil
+	}
+
+	for _, e := range result.Expressions {
+		if err := repo.AddTags(ctx, e.Expression.ID, models.TagSourceMeeting, tags...); err != nil {
+			return fmt.Errorf("failed to tag expression: %w", err)
+		}
+		for _, tag := range tags {
+			if !e.Expression.HasTags(tag) {
+				e.Expression.Tags = append(e.Expression.Tags, tag)
+			}
+		}
+		sort.Strings(e.Expression.Tags)
+	}
+	result.Tags = tags
+	p.logger.Debug("tagged expressions", "meeting", result.Meeting, "tags", tags, "expressions", len(result.Expressions))
+	return nil
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/llm/llmtest"
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+func TestProcessWithTags(t *testing.T) {
+	ctx := context.Background()
+	repo, err := storage.NewSQLiteRepository(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	rules := []models.TagRule{
+		{Tag: "project-x", Pattern: "project x*"},
+		{Tag: "interview", Pattern: "*interview*"},
+	}
+	provider := &llmtest.Provider{
+		Judgements: map[string]llmtest.Judgement{
+			"deprecate": {Meaning: "非推奨にする", Priority: 4},
+			"failover":  {Meaning: "切り替え", Priority: 3},
+		},
+	}
+	processor := NewTranscriptProcessor(provider, repo, WithTagRules(rules), WithTags([]string{"q3"}))
+
+	result, err := processor.Process(ctx, "Project X Weekly", "We deprecate the old cluster.")
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if !reflect.DeepEqual(result.Tags, []string{"q3", "project-x"}) {
+		t.Errorf("result tags = %v", result.Tags)
+	}
+	if _, err := processor.Process(ctx, "Vendor sync", "We failover to the standby."); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+
+	tests := []struct {
+		expression string
+		tags       []string
+	}{
+		{"deprecate", []string{"project-x", "q3"}},
+		{"failover", []string{"q3"}},
+	}
+	for _, tt := range tests {
+		expr, err := repo.GetExpression(ctx, tt.expression)
+		if err != nil || expr == nil {
+			t.Fatalf("GetExpression(%q): %v", tt.expression, err)
+		}
+		if !reflect.DeepEqual(expr.Tags, tt.tags) {
+			t.Errorf("%s: tags = %v, want %v", tt.expression, expr.Tags, tt.tags)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+// ListCategories はカテゴリの分類体系を表示順に取得
+func (r *SQLiteRepository) ListCategories(ctx context.Context) (models.Taxonomy, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT name, display_name, description, COALESCE(parent, ''), position
+		FROM categories
+		ORDER BY position ASC, name ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	defer rows.Close()
+
+	var taxonomy models.Taxonomy
+	for rows.Next() {
+		var c models.Category
+		if err := rows.Scan(&c.Name, &c.DisplayName, &c.Description, &c.Parent, &c.Position); err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		taxonomy = append(taxonomy, c)
+	}
+
+	return taxonomy, rows.Err()
+}
+
+// SaveCategory はカテゴリを追加または更新（新規で表示順が0なら末尾に追加）
+func (r *SQLiteRepository) SaveCategory(ctx context.Context, category *models.Category) error {
+	if err := models.ValidateCategoryName(category.Name); err != nil {
+		return err
+	}
+	if category.Parent == category.Name {
+		return fmt.Errorf("category cannot be its own parent: %s", category.Name)
+	}
+
+	if category.Position == 0 {
+		var position sql.NullInt64
+		err := r.q.QueryRowContext(ctx, `SELECT position FROM categories WHERE name = ?`, category.Name).Scan(&position)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("failed to get category: %w", err)
+		}
+		if err == sql.ErrNoRows {
+			err = r.q.QueryRowContext(ctx, `SELECT COALESCE(MAX(position), 0) + 1 FROM categories`).Scan(&position)
+			if err != nil {
+				return fmt.Errorf("failed to get category position: %w", err)
+			}
+		}
+		category.Position = int(position.Int64)
+	}
+
+	_, err := r.q.ExecContext(ctx, `
+		INSERT INTO categories (name, display_name, description, parent, position)
+		VALUES (?, ?, ?, NULLIF(?, ''), ?)
+		ON CONFLICT(name) DO UPDATE SET
+			display_name = excluded.display_name,
+			description = excluded.description,
+			parent = excluded.parent,
+			position = excluded.position
+	`, category.Name, category.DisplayName, category.Description, category.Parent, category.Position)
+	if err != nil {
+		return fmt.Errorf("failed to save category: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteCategory はカテゴリを削除し、表現のカテゴリをreassignに付け替える（空なら外す）
+// 子カテゴリは削除したカテゴリの親に付け替え、主カテゴリが外れた表現は次に関連の強いカテゴリを主カテゴリにする
+// 戻り値はカテゴリを付け替えた（または外した）表現の数
+func (r *SQLiteRepository) DeleteCategory(ctx context.Context, name, reassign string) (int, error) {
+	if name == reassign {
+		return 0, fmt.Errorf("cannot reassign a category to itself: %s", name)
+	}
+
+	tx, err := r.begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var parent sql.NullString
+	err = tx.QueryRowContext(ctx, `SELECT parent FROM categories WHERE name = ?`, name).Scan(&parent)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("category not found: %s", name)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get category: %w", err)
+	}
+	if reassign != "" {
+		var exists int
+		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM categories WHERE name = ?`, reassign).Scan(&exists); err != nil {
+			return 0, fmt.Errorf("failed to get category: %w", err)
+		}
+		if exists == 0 {
+			return 0, fmt.Errorf("category not found: %s", reassign)
+		}
+	}
+
+	var affected int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM expression_categories WHERE category = ?`, name).Scan(&affected); err != nil {
+		return 0, fmt.Errorf("failed to count expressions: %w", err)
+	}
+
+	if reassign != "" {
+		// 既にreassignにも属している表現はそのまま（順位は元の位置を引き継ぐ）
+		_, err := tx.ExecContext(ctx, `
+			INSERT OR IGNORE INTO expression_categories (expression_id, category, position)
+			SELECT expression_id, ?, position FROM expression_categories WHERE category = ?
+		`, reassign, name)
+		if err != nil {
+			return 0, fmt.Errorf("failed to reassign category: %w", err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM expression_categories WHERE category = ?`, name); err != nil {
+		return 0, fmt.Errorf("failed to remove category from expressions: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, `
+		UPDATE expressions
+		SET category = COALESCE((
+			SELECT category FROM expression_categories WHERE expression_id = expressions.id ORDER BY position LIMIT 1
+		), ''), updated_at = CURRENT_TIMESTAMP
+		WHERE category = ?
+	`, name)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update primary categories: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE categories SET parent = ? WHERE parent = ?`, parent, name); err != nil {
+		return 0, fmt.Errorf("failed to update child categories: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM categories WHERE name = ?`, name); err != nil {
+		return 0, fmt.Errorf("failed to delete category: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit category deletion: %w", err)
+	}
+
+	return affected, nil
+}
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+func TestCategories(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	// 初期のカテゴリはマイグレーションで登録される
+	taxonomy, err := repo.ListCategories(ctx)
+	if err != nil {
+		t.Fatalf("ListCategories: %v", err)
+	}
+	if len(taxonomy) != 3 || taxonomy[0].Name != "engineering" || taxonomy.Find("ビジネス") == nil {
+		t.Fatalf("unexpected default taxonomy: %+v", taxonomy)
+	}
+
+	infra := &models.Category{Name: "infra", DisplayName: "インフラ", Description: "クラウド・運用", Parent: "engineering"}
+	if err := repo.SaveCategory(ctx, infra); err != nil {
+		t.Fatalf("SaveCategory: %v", err)
+	}
+	if infra.Position != 4 {
+		t.Errorf("new category position = %d, expected 4", infra.Position)
+	}
+	if err := repo.SaveCategory(ctx, &models.Category{Name: "Small Talk"}); err == nil {
+		t.Error("SaveCategory accepted an unnormalized name")
+	}
+
+	// 複数カテゴリ（主カテゴリが先頭）
+	expr := &models.Expression{Expression: "blast radius", Type: "phrase", Priority: 3, Categories: []string{"infra", "casual", "engineering"}}
+	if err := repo.SaveExpression(ctx, expr); err != nil {
+		t.Fatalf("SaveExpression: %v", err)
+	}
+	got, err := repo.GetExpression(ctx, "blast radius")
+	if err != nil {
+		t.Fatalf("GetExpression: %v", err)
+	}
+	if got.Category != "infra" || !reflect.DeepEqual(got.Categories, []string{"infra", "casual", "engineering"}) {
+		t.Errorf("stored categories: %q %v", got.Category, got.Categories)
+	}
+	if err := repo.AddOccurrence(ctx, &models.ExpressionOccurrence{ExpressionID: expr.ID, Context: "What's the blast radius?"}); err != nil {
+		t.Fatalf("AddOccurrence: %v", err)
+	}
+
+	// 主カテゴリ以外でも絞り込める
+	results, err := repo.Search(ctx, SearchOptions{Query: "blast", Category: "casual"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 1 || !reflect.DeepEqual(results[0].Categories, got.Categories) {
+		t.Errorf("search by secondary category: %+v", results)
+	}
+
+	// 主カテゴリを削除すると次のカテゴリが主カテゴリになる
+	affected, err := repo.DeleteCategory(ctx, "infra", "")
+	if err != nil {
+		t.Fatalf("DeleteCategory: %v", err)
+	}
+	if affected != 1 {
+		t.Errorf("DeleteCategory affected %d expressions, expected 1", affected)
+	}
+	got, err = repo.GetExpression(ctx, "blast radius")
+	if err != nil {
+		t.Fatalf("GetExpression: %v", err)
+	}
+	if got.Category != "casual" || !reflect.DeepEqual(got.Categories, []string{"casual", "engineering"}) {
+		t.Errorf("categories after delete: %q %v", got.Category, got.Categories)
+	}
+
+	// 付け替え先に既に属していれば重複しない
+	if _, err := repo.DeleteCategory(ctx, "casual", "engineering"); err != nil {
+		t.Fatalf("DeleteCategory: %v", err)
+	}
+	got, err = repo.GetExpression(ctx, "blast radius")
+	if err != nil {
+		t.Fatalf("GetExpression: %v", err)
+	}
+	if got.Category != "engineering" || !reflect.DeepEqual(got.Categories, []string{"engineering"}) {
+		t.Errorf("categories after reassign: %q %v", got.Category, got.Categories)
+	}
+	if _, err := repo.DeleteCategory(ctx, "casual", ""); err == nil {
+		t.Error("DeleteCategory succeeded for a missing category")
+	}
+}
+
+func TestMergeCategories(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	target := &models.Expression{Expression: "pull request", Type: "phrase", Priority: 4, Category: "engineering"}
+	source := &models.Expression{Expression: "pull requests", Type: "phrase", Priority: 3, Categories: []string{"business", "engineering"}}
+	for _, expr := range []*models.Expression{target, source} {
+		if err := repo.SaveExpression(ctx, expr); err != nil {
+			t.Fatalf("SaveExpression: %v", err)
+		}
+	}
+
+	merged, err := repo.MergeExpressions(ctx, source.ID, target.ID)
+	if err != nil {
+		t.Fatalf("MergeExpressions: %v", err)
+	}
+	if !reflect.DeepEqual(merged.Categories, []string{"engineering", "business"}) {
+		t.Errorf("merged categories: %v", merged.Categories)
+	}
+
+	restored, _, err := repo.SplitExpression(ctx, "pull requests")
+	if err != nil {
+		t.Fatalf("SplitExpression: %v", err)
+	}
+	if restored.Category != "business" || !reflect.DeepEqual(restored.Categories, []string{"business", "engineering"}) {
+		t.Errorf("restored categories: %q %v", restored.Category, restored.Categories)
+	}
+}
@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// GetExportCheckpoint は出力先の最終エクスポート日時を取得（未エクスポートならfalse）
+func (r *SQLiteRepository) GetExportCheckpoint(ctx context.Context, destination string) (time.Time, bool, error) {
+	var exportedAt time.Time
+	err := r.q.QueryRowContext(ctx, `
+		SELECT exported_at FROM export_checkpoints
+		WHERE destination = ?
+	`, destination).Scan(&exportedAt)
+	if err == sql.ErrNoRows {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to get export checkpoint: %w", err)
+	}
+
+	return exportedAt, true, nil
+}
+
+// SaveExportCheckpoint は出力先の最終エクスポート日時を記録
+func (r *SQLiteRepository) SaveExportCheckpoint(ctx context.Context, destination string, exportedAt time.Time) error {
+	_, err := r.q.ExecContext(ctx, `
+		INSERT INTO export_checkpoints (destination, exported_at)
+		VALUES (?, ?)
+		ON CONFLICT(destination) DO UPDATE SET exported_at = excluded.exported_at
+	`, destination, exportedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to save export checkpoint: %w", err)
+	}
+
+	return nil
+}
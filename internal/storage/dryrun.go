@@ -0,0 +1,480 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+// ErrReadOnly はDryRunRepositoryで模擬できない書き込みを行った場合のエラー
+var ErrReadOnly = errors.New("repository is read-only (dry run)")
+
+// DryRunRepository は書き込みをデータベースに反映せず、メモリ上で模擬するRepository
+// 抽出処理で使う書き込み（表現の登録・出現履歴と語義の追加・スコアの更新・タグ付け・使い方の保存）は以降の読み込みに反映され、
+// それ以外の書き込みはErrReadOnlyを返す。読み込みは元のリポジトリに委譲する
+// 新しい書き込みメソッドが素通りしないよう、元のリポジトリは埋め込まずに明示的に委譲する
+type DryRunRepository struct {
+	base Repository
+
+	mu          sync.Mutex
+	nextID      int                           // 模擬的に採番するID（実在のIDと衝突しないよう負数）
+	added       map[string]*models.Expression // 新規登録した表現（表記→表現）
+	addedByID   map[int]*models.Expression
+	occurrences map[int][]*models.ExpressionOccurrence // 追加した出現履歴（表現ID→出現履歴）
+	scores      map[int]dryRunScore                    // 更新したスコア（表現ID→スコアと優先度）
+	tags        map[int][]string                       // つけたタグ（表現ID→タグ）
+	senses      map[int][]*models.Sense                // 追加した語義（表現ID→語義）
+	usages      map[int]*models.Usage                  // 保存した使い方（表現ID→使い方）
+}
+
+// dryRunScore は模擬的に更新したスコアと優先度
+type dryRunScore struct {
+	score    float64
+	priority int
+}
+
+// NewDryRunRepository はrepoへの書き込みをメモリ上で模擬するリポジトリを作成
+func NewDryRunRepository(repo Repository) *DryRunRepository {
+	return &DryRunRepository{
+		base:        repo,
+		nextID:      -1,
+		added:       make(map[string]*models.Expression),
+		addedByID:   make(map[int]*models.Expression),
+		occurrences: make(map[int][]*models.ExpressionOccurrence),
+		scores:      make(map[int]dryRunScore),
+		tags:        make(map[int][]string),
+		senses:      make(map[int][]*models.Sense),
+		usages:      make(map[int]*models.Usage),
+	}
+}
+
+// SaveExpression は表現を登録したものとして記録（負数のIDを採番）
+func (r *DryRunRepository) SaveExpression(ctx context.Context, expr *models.Expression) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.added[expr.Expression]; ok {
+		return fmt.Errorf("failed to save expression: already exists: %s", expr.Expression)
+	}
+
+	normalizeCategories(expr)
+	expr.ID = r.nextID
+	r.nextID--
+
+	saved := *expr
+	now := time.Now()
+	saved.OccurrenceCount = 0
+	saved.FirstSeenAt, saved.LastSeenAt = now, now
+	saved.UpdatedAt = now
+	r.added[saved.Expression] = &saved
+	r.addedByID[saved.ID] = &saved
+	return nil
+}
+
+// GetExpression は表現を取得し、模擬した書き込みを反映
+func (r *DryRunRepository) GetExpression(ctx context.Context, expression string) (*models.Expression, error) {
+	r.mu.Lock()
+	if expr, ok := r.added[expression]; ok {
+		defer r.mu.Unlock()
+		return r.applyLocked(expr), nil
+	}
+	r.mu.Unlock()
+
+	expr, err := r.base.GetExpression(ctx, expression)
+	if err != nil || expr == nil {
+		return expr, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.applyLocked(expr), nil
+}
+
+// GetExpressionByID はIDで表現を取得し、模擬した書き込みを反映
+func (r *DryRunRepository) GetExpressionByID(ctx context.Context, id int) (*models.Expression, error) {
+	r.mu.Lock()
+	if expr, ok := r.addedByID[id]; ok {
+		defer r.mu.Unlock()
+		return r.applyLocked(expr), nil
+	}
+	r.mu.Unlock()
+
+	expr, err := r.base.GetExpressionByID(ctx, id)
+	if err != nil || expr == nil {
+		return expr, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.applyLocked(expr), nil
+}
+
+// applyLocked は追加した出現履歴と更新したスコアを反映したコピーを返す（r.muを保持して呼ぶ）
+func (r *DryRunRepository) applyLocked(expr *models.Expression) *models.Expression {
+	result := *expr
+	if occs := r.occurrences[expr.ID]; len(occs) > 0 {
+		result.OccurrenceCount += len(occs)
+		result.LastSeenAt = occs[len(occs)-1].OccurredAt
+	}
+	if s, ok := r.scores[expr.ID]; ok {
+		result.Score = s.score
+		result.Priority = s.priority
+	}
+	if tags := r.tags[expr.ID]; len(tags) > 0 {
+		result.Tags = append([]string(nil), expr.Tags...)
+		for _, tag := range tags {
+			if !result.HasTags(tag) {
+				result.Tags = append(result.Tags, tag)
+			}
+		}
+		sort.Strings(result.Tags)
+	}
+	return &result
+}
+
+// ExpressionExists は表現が既に存在するか（模擬的に登録したものを含む）確認
+func (r *DryRunRepository) ExpressionExists(ctx context.Context, expression string) (bool, error) {
+	expr, err := r.GetExpression(ctx, expression)
+	if err != nil {
+		return false, err
+	}
+	return expr != nil, nil
+}
+
+// AddOccurrence は出現履歴を追加したものとして記録
+func (r *DryRunRepository) AddOccurrence(ctx context.Context, occ *models.ExpressionOccurrence) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	occ.ID = r.nextID
+	r.nextID--
+	if occ.OccurredAt.IsZero() {
+		occ.OccurredAt = time.Now()
+	}
+
+	added := *occ
+	r.occurrences[occ.ExpressionID] = append(r.occurrences[occ.ExpressionID], &added)
+	return nil
+}
+
+// GetOccurrences は出現履歴を取得（追加したものを先頭に含む）
+func (r *DryRunRepository) GetOccurrences(ctx context.Context, expressionID int) ([]*models.ExpressionOccurrence, error) {
+	var occs []*models.ExpressionOccurrence
+	if !r.isAdded(expressionID) {
+		var err error
+		occs, err = r.base.GetOccurrences(ctx, expressionID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	added := r.occurrences[expressionID]
+	result := make([]*models.ExpressionOccurrence, 0, len(added)+len(occs))
+	for i := len(added) - 1; i >= 0; i-- {
+		result = append(result, added[i])
+	}
+	return append(result, occs...), nil
+}
+
+// AddSense は語義を追加したものとして記録（負数のIDを採番）
+func (r *DryRunRepository) AddSense(ctx context.Context, sense *models.Sense) error {
+	if sense.Gloss == "" && sense.Definition == "" {
+		return fmt.Errorf("sense has neither gloss nor definition")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sense.ID = r.nextID
+	r.nextID--
+	sense.PartOfSpeech = models.NormalizePartOfSpeech(sense.PartOfSpeech)
+	if sense.CreatedAt.IsZero() {
+		sense.CreatedAt = time.Now()
+	}
+
+	added := *sense
+	r.senses[sense.ExpressionID] = append(r.senses[sense.ExpressionID], &added)
+	return nil
+}
+
+// ListSenses は語義を取得（追加したものを末尾に含み、追加した出現履歴を語義ごとの出現回数に反映）
+func (r *DryRunRepository) ListSenses(ctx context.Context, expressionID int) ([]*models.Sense, error) {
+	var senses []*models.Sense
+	if !r.isAdded(expressionID) {
+		var err error
+		senses, err = r.base.ListSenses(ctx, expressionID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := make([]*models.Sense, 0, len(senses)+len(r.senses[expressionID]))
+	for _, s := range append(senses, r.senses[expressionID]...) {
+		sense := *s
+		for _, occ := range r.occurrences[expressionID] {
+			if occ.SenseID == sense.ID {
+				sense.OccurrenceCount++
+			}
+		}
+		result = append(result, &sense)
+	}
+	return result, nil
+}
+
+// GetUsage は使い方を取得（保存したものがあればそれを返す）
+func (r *DryRunRepository) GetUsage(ctx context.Context, expressionID int) (*models.Usage, error) {
+	r.mu.Lock()
+	usage, ok := r.usages[expressionID]
+	r.mu.Unlock()
+	if ok {
+		saved := *usage
+		return &saved, nil
+	}
+	if r.isAdded(expressionID) {
+		return nil, nil
+	}
+	return r.base.GetUsage(ctx, expressionID)
+}
+
+// SaveUsage は使い方を保存したものとして記録
+func (r *DryRunRepository) SaveUsage(ctx context.Context, usage *models.Usage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	usage.Register = models.NormalizeRegister(usage.Register)
+	usage.GeneratedAt = time.Now()
+	saved := *usage
+	r.usages[usage.ExpressionID] = &saved
+	return nil
+}
+
+// isAdded は模擬的に登録した表現か判定
+func (r *DryRunRepository) isAdded(id int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.addedByID[id]
+	return ok
+}
+
+// UpdateScore はスコアと優先度を更新したものとして記録
+func (r *DryRunRepository) UpdateScore(ctx context.Context, expressionID int, score float64, priority int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.scores[expressionID] = dryRunScore{score: score, priority: priority}
+	return nil
+}
+
+// AddTags はタグをつけたものとして記録
+func (r *DryRunRepository) AddTags(ctx context.Context, expressionID int, source string, tags ...string) error {
+	for _, tag := range tags {
+		if err := models.ValidateTag(tag); err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tags[expressionID] = append(r.tags[expressionID], tags...)
+	return nil
+}
+
+// WithTx は模擬的な書き込みのみなのでそのままfnを実行
+func (r *DryRunRepository) WithTx(ctx context.Context, fn func(repo Repository) error) error {
+	return fn(r)
+}
+
+// Close は元のリポジトリを閉じない（呼び出し側が管理する）
+func (r *DryRunRepository) Close() error {
+	return nil
+}
+
+// 以下の読み込みは元のリポジトリに委譲（模擬した書き込みは反映しない）
+
+// GetAliases は表現に統合された別名の一覧を取得
+func (r *DryRunRepository) GetAliases(ctx context.Context, expressionID int) ([]string, error) {
+	return r.base.GetAliases(ctx, expressionID)
+}
+
+// GetAllExpressions はすべての表現を取得
+func (r *DryRunRepository) GetAllExpressions(ctx context.Context) ([]*models.Expression, error) {
+	return r.base.GetAllExpressions(ctx)
+}
+
+// GetTopExpressions は優先度・出現頻度の高い表現を取得
+func (r *DryRunRepository) GetTopExpressions(ctx context.Context, limit int) ([]*models.Expression, error) {
+	return r.base.GetTopExpressions(ctx, limit)
+}
+
+// ListExpressions はすべての表現を取得
+func (r *DryRunRepository) ListExpressions(ctx context.Context) ([]*models.Expression, error) {
+	return r.base.ListExpressions(ctx)
+}
+
+// Search は表現・意味・contextを検索
+func (r *DryRunRepository) Search(ctx context.Context, opts SearchOptions) ([]*models.Expression, error) {
+	return r.base.Search(ctx, opts)
+}
+
+// GetDueCards は復習期日を過ぎたカードと未復習の表現を取得
+func (r *DryRunRepository) GetDueCards(ctx context.Context, now time.Time, limit, newLimit int) ([]*models.ReviewCard, error) {
+	return r.base.GetDueCards(ctx, now, limit, newLimit)
+}
+
+// GetRecentGrades は表現の直近の復習評価を取得
+func (r *DryRunRepository) GetRecentGrades(ctx context.Context, expressionID int, limit int) ([]int, error) {
+	return r.base.GetRecentGrades(ctx, expressionID, limit)
+}
+
+// CountDueCards は復習期日を過ぎたカード数と未復習の表現数を取得
+func (r *DryRunRepository) CountDueCards(ctx context.Context, now time.Time) (int, int, error) {
+	return r.base.CountDueCards(ctx, now)
+}
+
+// GetNotionPages はNotionデータベースに同期済みの表現ID→ページIDを取得
+func (r *DryRunRepository) GetNotionPages(ctx context.Context, databaseID string) (map[int]string, error) {
+	return r.base.GetNotionPages(ctx, databaseID)
+}
+
+// GetExportCheckpoint は出力先の最終エクスポート日時を取得
+func (r *DryRunRepository) GetExportCheckpoint(ctx context.Context, destination string) (time.Time, bool, error) {
+	return r.base.GetExportCheckpoint(ctx, destination)
+}
+
+// IsFileProcessed は同じ内容のtranscriptが処理済みか確認
+func (r *DryRunRepository) IsFileProcessed(ctx context.Context, contentHash string) (bool, error) {
+	return r.base.IsFileProcessed(ctx, contentHash)
+}
+
+// ListCategories はカテゴリの分類体系を取得
+func (r *DryRunRepository) ListCategories(ctx context.Context) (models.Taxonomy, error) {
+	return r.base.ListCategories(ctx)
+}
+
+// ListTags はタグごとの表現数を取得
+func (r *DryRunRepository) ListTags(ctx context.Context) ([]NameCount, error) {
+	return r.base.ListTags(ctx)
+}
+
+// ListTagRules は会議名から自動でタグをつけるルールを取得
+func (r *DryRunRepository) ListTagRules(ctx context.Context) ([]models.TagRule, error) {
+	return r.base.ListTagRules(ctx)
+}
+
+// GetLearnerProfile は保存された学習者のプロフィールを取得
+func (r *DryRunRepository) GetLearnerProfile(ctx context.Context) (*models.LearnerProfile, error) {
+	return r.base.GetLearnerProfile(ctx)
+}
+
+// GetStatsTotals はデータベース全体の件数を取得
+func (r *DryRunRepository) GetStatsTotals(ctx context.Context) (*StatsTotals, error) {
+	return r.base.GetStatsTotals(ctx)
+}
+
+// CountNewExpressions は期間ごとに新しく登録された表現の数を集計
+func (r *DryRunRepository) CountNewExpressions(ctx context.Context, rng PeriodRange) ([]PeriodCount, error) {
+	return r.base.CountNewExpressions(ctx, rng)
+}
+
+// CountOccurrences は期間ごとの出現回数を集計
+func (r *DryRunRepository) CountOccurrences(ctx context.Context, rng PeriodRange) ([]PeriodCount, error) {
+	return r.base.CountOccurrences(ctx, rng)
+}
+
+// CountByCategory はカテゴリごとの表現数を集計
+func (r *DryRunRepository) CountByCategory(ctx context.Context) ([]NameCount, error) {
+	return r.base.CountByCategory(ctx)
+}
+
+// GetFrequentExpressions はsince以降の出現回数が多い表現を取得
+func (r *DryRunRepository) GetFrequentExpressions(ctx context.Context, since time.Time, limit int) ([]*FrequentExpression, error) {
+	return r.base.GetFrequentExpressions(ctx, since, limit)
+}
+
+// GetExpressionsWithoutMeaning は意味が空の表現を取得
+func (r *DryRunRepository) GetExpressionsWithoutMeaning(ctx context.Context, limit int) ([]*models.Expression, error) {
+	return r.base.GetExpressionsWithoutMeaning(ctx, limit)
+}
+
+// 以下の書き込みは模擬しない
+
+// UpdateExpression はErrReadOnlyを返す
+func (r *DryRunRepository) UpdateExpression(ctx context.Context, expr *models.Expression) error {
+	return ErrReadOnly
+}
+
+// DeleteExpression はErrReadOnlyを返す
+func (r *DryRunRepository) DeleteExpression(ctx context.Context, expressionID int) error {
+	return ErrReadOnly
+}
+
+// MergeExpressions はErrReadOnlyを返す
+func (r *DryRunRepository) MergeExpressions(ctx context.Context, sourceID, targetID int) (*models.Expression, error) {
+	return nil, ErrReadOnly
+}
+
+// SplitExpression はErrReadOnlyを返す
+func (r *DryRunRepository) SplitExpression(ctx context.Context, alias string) (*models.Expression, *models.Expression, error) {
+	return nil, nil, ErrReadOnly
+}
+
+// SaveReview はErrReadOnlyを返す
+func (r *DryRunRepository) SaveReview(ctx context.Context, card *models.ReviewCard, log *models.ReviewLog) error {
+	return ErrReadOnly
+}
+
+// SaveNotionPage はErrReadOnlyを返す
+func (r *DryRunRepository) SaveNotionPage(ctx context.Context, databaseID string, expressionID int, pageID string) error {
+	return ErrReadOnly
+}
+
+// SaveExportCheckpoint はErrReadOnlyを返す
+func (r *DryRunRepository) SaveExportCheckpoint(ctx context.Context, destination string, exportedAt time.Time) error {
+	return ErrReadOnly
+}
+
+// MarkFileProcessed はErrReadOnlyを返す
+func (r *DryRunRepository) MarkFileProcessed(ctx context.Context, contentHash, path, meeting string) error {
+	return ErrReadOnly
+}
+
+// SaveCategory はErrReadOnlyを返す
+func (r *DryRunRepository) SaveCategory(ctx context.Context, category *models.Category) error {
+	return ErrReadOnly
+}
+
+// DeleteCategory はErrReadOnlyを返す
+func (r *DryRunRepository) DeleteCategory(ctx context.Context, name, reassign string) (int, error) {
+	return 0, ErrReadOnly
+}
+
+// RemoveTags はErrReadOnlyを返す
+func (r *DryRunRepository) RemoveTags(ctx context.Context, expressionID int, tags ...string) (int, error) {
+	return 0, ErrReadOnly
+}
+
+// SaveTagRule はErrReadOnlyを返す
+func (r *DryRunRepository) SaveTagRule(ctx context.Context, rule models.TagRule) error {
+	return ErrReadOnly
+}
+
+// DeleteTagRule はErrReadOnlyを返す
+func (r *DryRunRepository) DeleteTagRule(ctx context.Context, rule models.TagRule) error {
+	return ErrReadOnly
+}
+
+// SaveLearnerProfile はErrReadOnlyを返す
+func (r *DryRunRepository) SaveLearnerProfile(ctx context.Context, profile *models.LearnerProfile) error {
+	return ErrReadOnly
+}
+
+// DryRunRepositoryがRepositoryを満たすことをコンパイル時に確認
+var _ Repository = (*DryRunRepository)(nil)
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+func TestDryRunRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	existing := &models.Expression{Expression: "deprecate", Type: "word", Priority: 3, Category: "engineering"}
+	if err := repo.SaveExpression(ctx, existing); err != nil {
+		t.Fatalf("SaveExpression: %v", err)
+	}
+	if err := repo.AddOccurrence(ctx, &models.ExpressionOccurrence{ExpressionID: existing.ID, Context: "We deprecate it."}); err != nil {
+		t.Fatalf("AddOccurrence: %v", err)
+	}
+
+	dry := NewDryRunRepository(repo)
+
+	// 新規登録は負数のIDで読み込みに反映される
+	added := &models.Expression{Expression: "touch base", Type: "phrase", Priority: 3, Category: "business"}
+	if err := dry.SaveExpression(ctx, added); err != nil {
+		t.Fatalf("SaveExpression: %v", err)
+	}
+	if added.ID >= 0 {
+		t.Errorf("simulated ID = %d, want negative", added.ID)
+	}
+	if err := dry.AddOccurrence(ctx, &models.ExpressionOccurrence{ExpressionID: added.ID, Context: "Let's touch base."}); err != nil {
+		t.Fatalf("AddOccurrence: %v", err)
+	}
+	got, err := dry.GetExpression(ctx, "touch base")
+	if err != nil || got == nil || got.OccurrenceCount != 1 {
+		t.Fatalf("simulated expression = %+v, %v; want 1 occurrence", got, err)
+	}
+
+	// 既存の表現は出現回数とスコア・優先度の変更が反映される
+	if err := dry.AddOccurrence(ctx, &models.ExpressionOccurrence{ExpressionID: existing.ID, Context: "Deprecate the flag."}); err != nil {
+		t.Fatalf("AddOccurrence: %v", err)
+	}
+	if err := dry.UpdateScore(ctx, existing.ID, 4.2, 4); err != nil {
+		t.Fatalf("UpdateScore: %v", err)
+	}
+	got, err = dry.GetExpression(ctx, "deprecate")
+	if err != nil || got.OccurrenceCount != 2 || got.Priority != 4 || got.Score != 4.2 {
+		t.Fatalf("simulated existing expression = %+v, %v; want 2 occurrences, score 4.2 and priority 4", got, err)
+	}
+	occs, err := dry.GetOccurrences(ctx, existing.ID)
+	if err != nil || len(occs) != 2 || occs[0].Context != "Deprecate the flag." {
+		t.Errorf("GetOccurrences = %d occurrences, %v; want the simulated one first", len(occs), err)
+	}
+
+	// 模擬しない書き込みはエラー
+	if err := dry.DeleteExpression(ctx, existing.ID); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("DeleteExpression error = %v, want ErrReadOnly", err)
+	}
+
+	// 元のデータベースは変更されていない
+	if exists, err := repo.ExpressionExists(ctx, "touch base"); err != nil || exists {
+		t.Errorf("dry run must not save expressions: exists=%v err=%v", exists, err)
+	}
+	base, err := repo.GetExpression(ctx, "deprecate")
+	if err != nil || base.OccurrenceCount != 1 || base.Priority != 3 {
+		t.Errorf("dry run must not change existing expressions: %+v, %v", base, err)
+	}
+}
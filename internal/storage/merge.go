@@ -0,0 +1,328 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+// querier は*sql.DBと*sql.Txの共通インターフェース
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// getExpressionByID はIDで表現を取得（存在しなければnil）
+func getExpressionByID(ctx context.Context, q querier, id int) (*models.Expression, error) {
+	query := `
+		SELECT ` + expressionColumns + `
+		FROM expressions
+		WHERE id = ?
+	`
+
+	expr, err := scanExpression(q.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expression: %w", err)
+	}
+
+	return expr, nil
+}
+
+// GetExpressionByID はIDで表現を取得（存在しなければnil）
+func (r *SQLiteRepository) GetExpressionByID(ctx context.Context, id int) (*models.Expression, error) {
+	return getExpressionByID(ctx, r.q, id)
+}
+
+// getExpressionByAlias は別名から統合先の表現を取得（別名でなければnil）
+func (r *SQLiteRepository) getExpressionByAlias(ctx context.Context, q querier, alias string) (*models.Expression, error) {
+	var id int
+	err := q.QueryRowContext(ctx, `SELECT expression_id FROM expression_aliases WHERE alias = ?`, alias).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alias: %w", err)
+	}
+
+	return getExpressionByID(ctx, q, id)
+}
+
+// GetAliases は表現に統合された別名の一覧を取得
+func (r *SQLiteRepository) GetAliases(ctx context.Context, expressionID int) ([]string, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT alias FROM expression_aliases
+		WHERE expression_id = ?
+		ORDER BY alias ASC
+	`, expressionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query aliases: %w", err)
+	}
+	defer rows.Close()
+
+	var aliases []string
+	for rows.Next() {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
+			return nil, fmt.Errorf("failed to scan alias: %w", err)
+		}
+		aliases = append(aliases, alias)
+	}
+
+	return aliases, rows.Err()
+}
+
+// MergeExpressions は統合元の出現履歴を統合先に移し、統合元の表記を別名として記録する
+// 出現回数・初出/最終出現日時は出現履歴から再計算する（優先度の再計算は呼び出し側で行う）
+func (r *SQLiteRepository) MergeExpressions(ctx context.Context, sourceID, targetID int) (*models.Expression, error) {
+	if sourceID == targetID {
+		return nil, fmt.Errorf("cannot merge an expression into itself")
+	}
+
+	tx, err := r.begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	source, err := getExpressionByID(ctx, tx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := getExpressionByID(ctx, tx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil || target == nil {
+		return nil, fmt.Errorf("expression not found: source=%d target=%d", sourceID, targetID)
+	}
+
+	// 分割時に復元できるよう統合元のスナップショットを保存
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO expression_merges (source_expression, target_id, type, meaning, priority, category, manually_edited, base_priority, categories, tags)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, source.Expression, target.ID, source.Type, source.Meaning, source.Priority, source.Category, source.ManuallyEdited, basePriority(source),
+		strings.Join(source.Categories, ","), strings.Join(source.Tags, ","))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record merge: %w", err)
+	}
+
+	// 出現履歴を移動（表記が未記録の履歴には統合元の表記を残す）
+	_, err = tx.ExecContext(ctx, `
+		UPDATE expression_occurrences
+		SET expression_id = ?, surface = COALESCE(surface, ?)
+		WHERE expression_id = ?
+	`, target.ID, source.Expression, source.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to move occurrences: %w", err)
+	}
+
+	// 語義も統合先に移す（出現履歴は語義への参照を保ったまま移動済み）
+	if _, err := tx.ExecContext(ctx, `UPDATE expression_senses SET expression_id = ? WHERE expression_id = ?`, target.ID, source.ID); err != nil {
+		return nil, fmt.Errorf("failed to move senses: %w", err)
+	}
+
+	// 統合元の別名も統合先に付け替え、統合元の表記を別名として登録
+	if _, err := tx.ExecContext(ctx, `UPDATE expression_aliases SET expression_id = ? WHERE expression_id = ?`, target.ID, source.ID); err != nil {
+		return nil, fmt.Errorf("failed to move aliases: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO expression_aliases (alias, expression_id) VALUES (?, ?)`, source.Expression, target.ID); err != nil {
+		return nil, fmt.Errorf("failed to add alias: %w", err)
+	}
+
+	// 復習履歴は統合先に引き継ぎ、統合元のスケジュールは破棄
+	if _, err := tx.ExecContext(ctx, `UPDATE review_logs SET expression_id = ? WHERE expression_id = ?`, target.ID, source.ID); err != nil {
+		return nil, fmt.Errorf("failed to move review logs: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM review_cards WHERE expression_id = ?`, source.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete review card: %w", err)
+	}
+
+	// 統合元のカテゴリのうち統合先にないものを統合先の後ろに追加
+	target.Categories = append(target.Categories, source.Categories...)
+	normalizeCategories(target)
+	if err := setCategories(ctx, tx, target.ID, target.Categories); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM expression_categories WHERE expression_id = ?`, source.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete categories: %w", err)
+	}
+
+	// 統合元のタグも統合先に引き継ぐ
+	_, err = tx.ExecContext(ctx, `
+		INSERT OR IGNORE INTO expression_tags (expression_id, tag, source, created_at)
+		SELECT ?, tag, source, created_at FROM expression_tags WHERE expression_id = ?
+	`, target.ID, source.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to move tags: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM expression_tags WHERE expression_id = ?`, source.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete tags: %w", err)
+	}
+
+	// 使い方は統合先になければ引き継ぐ
+	if err := mergeUsage(ctx, tx, source.ID, target.ID); err != nil {
+		return nil, err
+	}
+
+	// 統合元のNotionページとの対応は破棄（統合先のページに集約される）
+	if _, err := tx.ExecContext(ctx, `DELETE FROM notion_pages WHERE expression_id = ?`, source.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete notion page mapping: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM expressions WHERE id = ?`, source.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete merged expression: %w", err)
+	}
+
+	// 統合先に意味がなければ統合元の意味を引き継ぐ
+	if target.Meaning == "" && source.Meaning != "" {
+		if _, err := tx.ExecContext(ctx, `UPDATE expressions SET meaning = ? WHERE id = ?`, source.Meaning, target.ID); err != nil {
+			return nil, fmt.Errorf("failed to update meaning: %w", err)
+		}
+	}
+
+	if err := recomputeStats(ctx, tx, target.ID); err != nil {
+		return nil, err
+	}
+
+	merged, err := getExpressionByID(ctx, tx, target.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit merge: %w", err)
+	}
+
+	return merged, nil
+}
+
+// SplitExpression は統合済みの別名を元の表現として復元し、その表記で記録された出現履歴を戻す
+// 戻り値は復元した表現と統合先の表現（スコアと優先度の再計算はservice.SplitExpressionで行う）
+func (r *SQLiteRepository) SplitExpression(ctx context.Context, alias string) (*models.Expression, *models.Expression, error) {
+	tx, err := r.begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	target, err := r.getExpressionByAlias(ctx, tx, alias)
+	if err != nil {
+		return nil, nil, err
+	}
+	if target == nil {
+		return nil, nil, fmt.Errorf("not a merged alias: %s", alias)
+	}
+
+	// 統合時のスナップショットから復元（なければ統合先の属性を引き継ぐ）
+	restored := &models.Expression{
+		Expression:   alias,
+		Type:         target.Type,
+		Priority:     target.Priority,
+		BasePriority: basePriority(target),
+		Category:     target.Category,
+		Categories:   target.Categories,
+		Tags:         target.Tags,
+	}
+	var meaning, categories, tags sql.NullString
+	err = tx.QueryRowContext(ctx, `
+		SELECT type, meaning, priority, category, manually_edited, COALESCE(base_priority, priority), categories, tags
+		FROM expression_merges
+		WHERE source_expression = ?
+		ORDER BY id DESC
+		LIMIT 1
+	`, alias).Scan(&restored.Type, &meaning, &restored.Priority, &restored.Category, &restored.ManuallyEdited, &restored.BasePriority, &categories, &tags)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, nil, fmt.Errorf("failed to get merge record: %w", err)
+	}
+	restored.Meaning = meaning.String
+	if err == nil {
+		// カテゴリを記録する前の統合履歴なら主カテゴリのみ
+		restored.Categories = splitCategories(categories.String)
+		// タグを記録する前の統合履歴なら統合先のタグを引き継ぐ
+		if tags.Valid {
+			restored.Tags = splitCategories(tags.String)
+		}
+	}
+	normalizeCategories(restored)
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM expression_aliases WHERE alias = ?`, alias); err != nil {
+		return nil, nil, fmt.Errorf("failed to delete alias: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO expressions (expression, type, meaning, priority, category, manually_edited, occurrence_count, base_priority, score)
+		VALUES (?, ?, ?, ?, ?, ?, 0, ?, ?)
+	`, restored.Expression, restored.Type, restored.Meaning, restored.Priority, restored.Category, restored.ManuallyEdited,
+		restored.BasePriority, restored.Priority)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to restore expression: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+	if err := setCategories(ctx, tx, int(id), restored.Categories); err != nil {
+		return nil, nil, err
+	}
+	if err := addTags(ctx, tx, int(id), models.TagSourceManual, restored.Tags); err != nil {
+		return nil, nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE expression_occurrences
+		SET expression_id = ?
+		WHERE expression_id = ? AND surface = ?
+	`, id, target.ID, alias)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to move occurrences: %w", err)
+	}
+	if err := splitSenses(ctx, tx, int(id), target.ID); err != nil {
+		return nil, nil, err
+	}
+
+	for _, exprID := range []int{int(id), target.ID} {
+		if err := recomputeStats(ctx, tx, exprID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	source, err := getExpressionByID(ctx, tx, int(id))
+	if err != nil {
+		return nil, nil, err
+	}
+	target, err = getExpressionByID(ctx, tx, target.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit split: %w", err)
+	}
+
+	return source, target, nil
+}
+
+// recomputeStats は出現履歴から出現回数・初出/最終出現日時を再計算
+func recomputeStats(ctx context.Context, q querier, expressionID int) error {
+	_, err := q.ExecContext(ctx, `
+		UPDATE expressions
+		SET
+			occurrence_count = (SELECT COUNT(*) FROM expression_occurrences WHERE expression_id = ?1),
+			first_seen_at = COALESCE((SELECT MIN(occurred_at) FROM expression_occurrences WHERE expression_id = ?1), first_seen_at),
+			last_seen_at = COALESCE((SELECT MAX(occurred_at) FROM expression_occurrences WHERE expression_id = ?1), last_seen_at),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?1
+	`, expressionID)
+	if err != nil {
+		return fmt.Errorf("failed to recompute stats: %w", err)
+	}
+
+	return nil
+}
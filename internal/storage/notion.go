@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+)
+
+// GetNotionPages はNotionデータベースに同期済みの表現ID→ページIDを取得
+func (r *SQLiteRepository) GetNotionPages(ctx context.Context, databaseID string) (map[int]string, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT expression_id, page_id FROM notion_pages
+		WHERE database_id = ?
+	`, databaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notion pages: %w", err)
+	}
+	defer rows.Close()
+
+	pages := make(map[int]string)
+	for rows.Next() {
+		var expressionID int
+		var pageID string
+		if err := rows.Scan(&expressionID, &pageID); err != nil {
+			return nil, fmt.Errorf("failed to scan notion page: %w", err)
+		}
+		pages[expressionID] = pageID
+	}
+
+	return pages, rows.Err()
+}
+
+// SaveNotionPage は表現に対応するNotionのページIDを記録（既存の対応は上書き）
+func (r *SQLiteRepository) SaveNotionPage(ctx context.Context, databaseID string, expressionID int, pageID string) error {
+	_, err := r.q.ExecContext(ctx, `
+		INSERT INTO notion_pages (database_id, expression_id, page_id, synced_at)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(database_id, expression_id) DO UPDATE SET
+			page_id = excluded.page_id,
+			synced_at = excluded.synced_at
+	`, databaseID, expressionID, pageID)
+	if err != nil {
+		return fmt.Errorf("failed to save notion page: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// IsFileProcessed は同じ内容（ハッシュ）のtranscriptが処理済みか確認
+func (r *SQLiteRepository) IsFileProcessed(ctx context.Context, contentHash string) (bool, error) {
+	var exists int
+	err := r.q.QueryRowContext(ctx, `SELECT 1 FROM processed_files WHERE content_hash = ?`, contentHash).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check processed file: %w", err)
+	}
+
+	return true, nil
+}
+
+// MarkFileProcessed はtranscriptを処理済みとして記録
+func (r *SQLiteRepository) MarkFileProcessed(ctx context.Context, contentHash, path, meeting string) error {
+	_, err := r.q.ExecContext(ctx, `
+		INSERT OR IGNORE INTO processed_files (content_hash, path, meeting)
+		VALUES (?, ?, ?)
+	`, contentHash, path, meeting)
+	if err != nil {
+		return fmt.Errorf("failed to mark file as processed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+// GetLearnerProfile は保存された学習者のプロフィールを取得（未保存ならすべて空のプロフィール）
+func (r *SQLiteRepository) GetLearnerProfile(ctx context.Context) (*models.LearnerProfile, error) {
+	var profile models.LearnerProfile
+	err := r.q.QueryRowContext(ctx, `
+		SELECT role, domain, level, goals, native_language, updated_at
+		FROM learner_profile
+		WHERE id = 1
+	`).Scan(&profile.Role, &profile.Domain, &profile.Level, &profile.Goals, &profile.NativeLanguage, &profile.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return &models.LearnerProfile{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get learner profile: %w", err)
+	}
+
+	return &profile, nil
+}
+
+// SaveLearnerProfile は学習者のプロフィールを保存（既存のプロフィールは置き換える）
+func (r *SQLiteRepository) SaveLearnerProfile(ctx context.Context, profile *models.LearnerProfile) error {
+	_, err := r.q.ExecContext(ctx, `
+		INSERT INTO learner_profile (id, role, domain, level, goals, native_language, updated_at)
+		VALUES (1, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(id) DO UPDATE SET
+			role = excluded.role,
+			domain = excluded.domain,
+			level = excluded.level,
+			goals = excluded.goals,
+			native_language = excluded.native_language,
+			updated_at = excluded.updated_at
+	`, profile.Role, profile.Domain, profile.Level, profile.Goals, profile.NativeLanguage)
+	if err != nil {
+		return fmt.Errorf("failed to save learner profile: %w", err)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	"github.com/mamyudapao/learn-by-transcript/internal/models"
 )
@@ -11,17 +12,35 @@ type Repository interface {
 	// SaveExpression は新しい表現を保存
 	SaveExpression(ctx context.Context, expr *models.Expression) error
 
-	// GetExpression は表現を取得
+	// GetExpression は表現を取得（別名の場合は統合先の表現を返す）
 	GetExpression(ctx context.Context, expression string) (*models.Expression, error)
 
+	// GetExpressionByID はIDで表現を取得（存在しなければnil）
+	GetExpressionByID(ctx context.Context, id int) (*models.Expression, error)
+
 	// ExpressionExists は表現が既に存在するか確認
 	ExpressionExists(ctx context.Context, expression string) (bool, error)
 
-	// AddOccurrence は出現履歴を追加
-	AddOccurrence(ctx context.Context, expressionID int, context string) error
+	// AddOccurrence は出現履歴を追加（occ.IDに採番されたIDを設定）
+	AddOccurrence(ctx context.Context, occ *models.ExpressionOccurrence) error
+
+	// UpdateScore はスコアと優先度を更新
+	UpdateScore(ctx context.Context, expressionID int, score float64, priority int) error
+
+	// UpdateExpression は表現の意味・優先度・基準の優先度・カテゴリ・種類・手動編集フラグを更新
+	UpdateExpression(ctx context.Context, expr *models.Expression) error
+
+	// DeleteExpression は表現と出現履歴を削除
+	DeleteExpression(ctx context.Context, expressionID int) error
+
+	// MergeExpressions は統合元の出現履歴を統合先に移し、統合元の表記を別名として記録
+	MergeExpressions(ctx context.Context, sourceID, targetID int) (*models.Expression, error)
 
-	// UpdatePriority は優先度を更新
-	UpdatePriority(ctx context.Context, expressionID int, priority int) error
+	// SplitExpression は統合済みの別名を元の表現として復元（復元した表現と統合先を返す）
+	SplitExpression(ctx context.Context, alias string) (*models.Expression, *models.Expression, error)
+
+	// GetAliases は表現に統合された別名の一覧を取得
+	GetAliases(ctx context.Context, expressionID int) ([]string, error)
 
 	// GetAllExpressions はすべての表現を取得
 	GetAllExpressions(ctx context.Context) ([]*models.Expression, error)
@@ -35,6 +54,105 @@ type Repository interface {
 	// GetOccurrences は表現の出現履歴を取得
 	GetOccurrences(ctx context.Context, expressionID int) ([]*models.ExpressionOccurrence, error)
 
+	// ListSenses は表現の語義を登録順に取得（語義ごとの出現回数を含む）
+	ListSenses(ctx context.Context, expressionID int) ([]*models.Sense, error)
+
+	// AddSense は表現に語義を追加（sense.IDに採番されたIDを設定）
+	AddSense(ctx context.Context, sense *models.Sense) error
+
+	// GetUsage は表現の使い方（例文・使用上の注意・関連語）を取得（まだ生成していなければnil）
+	GetUsage(ctx context.Context, expressionID int) (*models.Usage, error)
+
+	// SaveUsage は表現の使い方を保存（生成済みの使い方は置き換える）
+	SaveUsage(ctx context.Context, usage *models.Usage) error
+
+	// Search は表現・意味・contextを検索（フィルタ付き）
+	Search(ctx context.Context, opts SearchOptions) ([]*models.Expression, error)
+
+	// GetDueCards は復習期日を過ぎたカードと未復習の表現（最大newLimit件）を取得
+	GetDueCards(ctx context.Context, now time.Time, limit, newLimit int) ([]*models.ReviewCard, error)
+
+	// SaveReview は復習結果（カードの状態と復習履歴）を保存
+	SaveReview(ctx context.Context, card *models.ReviewCard, log *models.ReviewLog) error
+
+	// GetRecentGrades は表現の直近の復習評価を新しい順に最大limit件取得
+	GetRecentGrades(ctx context.Context, expressionID int, limit int) ([]int, error)
+
+	// CountDueCards は復習期日を過ぎたカード数と未復習の表現数を取得
+	CountDueCards(ctx context.Context, now time.Time) (due int, unseen int, err error)
+
+	// GetNotionPages はNotionデータベースに同期済みの表現ID→ページIDを取得
+	GetNotionPages(ctx context.Context, databaseID string) (map[int]string, error)
+
+	// SaveNotionPage は表現に対応するNotionのページIDを記録
+	SaveNotionPage(ctx context.Context, databaseID string, expressionID int, pageID string) error
+
+	// GetExportCheckpoint は出力先の最終エクスポート日時を取得（未エクスポートならfalse）
+	GetExportCheckpoint(ctx context.Context, destination string) (time.Time, bool, error)
+
+	// SaveExportCheckpoint は出力先の最終エクスポート日時を記録
+	SaveExportCheckpoint(ctx context.Context, destination string, exportedAt time.Time) error
+
+	// IsFileProcessed は同じ内容（ハッシュ）のtranscriptが処理済みか確認
+	IsFileProcessed(ctx context.Context, contentHash string) (bool, error)
+
+	// MarkFileProcessed はtranscriptを処理済みとして記録
+	MarkFileProcessed(ctx context.Context, contentHash, path, meeting string) error
+
+	// GetStatsTotals はデータベース全体の表現数・出現回数などを取得
+	GetStatsTotals(ctx context.Context) (*StatsTotals, error)
+
+	// CountNewExpressions は期間ごとに新しく登録された表現の数を集計
+	CountNewExpressions(ctx context.Context, rng PeriodRange) ([]PeriodCount, error)
+
+	// CountOccurrences は期間ごとの出現回数を集計
+	CountOccurrences(ctx context.Context, rng PeriodRange) ([]PeriodCount, error)
+
+	// CountByCategory はカテゴリごとの表現数を多い順に集計
+	CountByCategory(ctx context.Context) ([]NameCount, error)
+
+	// GetFrequentExpressions はsince以降の出現回数が多い表現を取得
+	GetFrequentExpressions(ctx context.Context, since time.Time, limit int) ([]*FrequentExpression, error)
+
+	// GetExpressionsWithoutMeaning は意味が空の表現を取得
+	GetExpressionsWithoutMeaning(ctx context.Context, limit int) ([]*models.Expression, error)
+
+	// ListCategories はカテゴリの分類体系を表示順に取得
+	ListCategories(ctx context.Context) (models.Taxonomy, error)
+
+	// SaveCategory はカテゴリを追加または更新
+	SaveCategory(ctx context.Context, category *models.Category) error
+
+	// DeleteCategory はカテゴリを削除し、表現のカテゴリをreassignに付け替える（空なら外す）
+	DeleteCategory(ctx context.Context, name, reassign string) (int, error)
+
+	// ListTags はタグごとの表現数を名前順に取得
+	ListTags(ctx context.Context) ([]NameCount, error)
+
+	// AddTags は表現にタグをつける（sourceは models.TagSourceManual など）
+	AddTags(ctx context.Context, expressionID int, source string, tags ...string) error
+
+	// RemoveTags は表現からタグを外し、外したタグの数を返す
+	RemoveTags(ctx context.Context, expressionID int, tags ...string) (int, error)
+
+	// ListTagRules は会議名から自動でタグをつけるルールを取得
+	ListTagRules(ctx context.Context) ([]models.TagRule, error)
+
+	// SaveTagRule はタグのルールを追加
+	SaveTagRule(ctx context.Context, rule models.TagRule) error
+
+	// DeleteTagRule はタグのルールを削除
+	DeleteTagRule(ctx context.Context, rule models.TagRule) error
+
+	// GetLearnerProfile は保存された学習者のプロフィールを取得（未保存なら空）
+	GetLearnerProfile(ctx context.Context) (*models.LearnerProfile, error)
+
+	// SaveLearnerProfile は学習者のプロフィールを保存
+	SaveLearnerProfile(ctx context.Context, profile *models.LearnerProfile) error
+
+	// WithTx はfnに渡したリポジトリの操作を1つのトランザクションで実行（エラー時はロールバック）
+	WithTx(ctx context.Context, fn func(repo Repository) error) error
+
 	// Close はリソースをクリーンアップ
 	Close() error
 }
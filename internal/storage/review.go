@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+// reviewCardColumns はreview_cardsテーブルから取得するカラム（scanReviewCardと順序を揃える）
+const reviewCardColumns = `c.expression_id, c.ease, c.interval_days, c.due_at, c.reps, c.lapses,
+		       c.stability, c.difficulty, c.last_reviewed_at`
+
+// scanReviewCard はreviewCardColumnsとexpressionColumnsの順で1行をスキャン
+func scanReviewCard(row rowScanner) (*models.ReviewCard, error) {
+	var card models.ReviewCard
+	var expr expressionScan
+	var lastReviewedAt sql.NullTime
+	dest := []interface{}{
+		&card.ExpressionID, &card.Ease, &card.IntervalDays, &card.DueAt, &card.Reps, &card.Lapses,
+		&card.Stability, &card.Difficulty, &lastReviewedAt,
+	}
+	if err := row.Scan(append(dest, expr.dest()...)...); err != nil {
+		return nil, err
+	}
+	card.LastReviewedAt = lastReviewedAt.Time
+	card.Expression = expr.expression()
+	return &card, nil
+}
+
+// GetDueCards は復習期日を過ぎたカードを期日順に取得し、続けて未復習の表現を優先度順に最大newLimit件追加
+func (r *SQLiteRepository) GetDueCards(ctx context.Context, now time.Time, limit, newLimit int) ([]*models.ReviewCard, error) {
+	query := `
+		SELECT ` + reviewCardColumns + `, ` + expressionColumnsOf("e") + `
+		FROM review_cards c
+		JOIN expressions e ON e.id = c.expression_id
+		WHERE c.due_at <= ?
+		ORDER BY c.due_at ASC
+		LIMIT ?
+	`
+
+	rows, err := r.q.QueryContext(ctx, query, now.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due cards: %w", err)
+	}
+	defer rows.Close()
+
+	var cards []*models.ReviewCard
+	for rows.Next() {
+		card, err := scanReviewCard(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan review card: %w", err)
+		}
+		cards = append(cards, card)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if remaining := limit - len(cards); remaining < newLimit {
+		newLimit = remaining
+	}
+	if newLimit <= 0 {
+		return cards, nil
+	}
+
+	// 未復習の表現（カード未作成）は優先度・出現回数の高い順に出題
+	newQuery := `
+		SELECT ` + expressionColumns + `
+		FROM expressions
+		WHERE id NOT IN (SELECT expression_id FROM review_cards)
+		ORDER BY priority DESC, occurrence_count DESC, expression ASC
+		LIMIT ?
+	`
+	newRows, err := r.q.QueryContext(ctx, newQuery, newLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query new cards: %w", err)
+	}
+	defer newRows.Close()
+
+	expressions, err := scanExpressions(newRows)
+	if err != nil {
+		return nil, err
+	}
+	for _, expr := range expressions {
+		cards = append(cards, &models.ReviewCard{
+			ExpressionID: expr.ID,
+			DueAt:        now,
+			Expression:   expr,
+		})
+	}
+
+	return cards, nil
+}
+
+// SaveReview は復習結果（カードの状態と復習履歴）を保存
+func (r *SQLiteRepository) SaveReview(ctx context.Context, card *models.ReviewCard, log *models.ReviewLog) error {
+	tx, err := r.begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO review_cards (expression_id, ease, interval_days, due_at, reps, lapses, stability, difficulty, last_reviewed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(expression_id) DO UPDATE SET
+			ease = excluded.ease,
+			interval_days = excluded.interval_days,
+			due_at = excluded.due_at,
+			reps = excluded.reps,
+			lapses = excluded.lapses,
+			stability = excluded.stability,
+			difficulty = excluded.difficulty,
+			last_reviewed_at = excluded.last_reviewed_at
+	`, card.ExpressionID, card.Ease, card.IntervalDays, card.DueAt.UTC(), card.Reps, card.Lapses,
+		card.Stability, card.Difficulty, card.LastReviewedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to save review card: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO review_logs (expression_id, grade, scheduler, interval_days, reviewed_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, log.ExpressionID, log.Grade, log.Scheduler, log.IntervalDays, log.ReviewedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to add review log: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+	log.ID = int(id)
+
+	return tx.Commit()
+}
+
+// GetRecentGrades は表現の直近の復習評価を新しい順に最大limit件取得
+func (r *SQLiteRepository) GetRecentGrades(ctx context.Context, expressionID int, limit int) ([]int, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT grade FROM review_logs
+		WHERE expression_id = ?
+		ORDER BY reviewed_at DESC, id DESC
+		LIMIT ?
+	`, expressionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review grades: %w", err)
+	}
+	defer rows.Close()
+
+	var grades []int
+	for rows.Next() {
+		var grade int
+		if err := rows.Scan(&grade); err != nil {
+			return nil, fmt.Errorf("failed to scan review grade: %w", err)
+		}
+		grades = append(grades, grade)
+	}
+
+	return grades, rows.Err()
+}
+
+// CountDueCards は復習期日を過ぎたカード数と未復習の表現数を取得
+func (r *SQLiteRepository) CountDueCards(ctx context.Context, now time.Time) (due int, unseen int, err error) {
+	err = r.q.QueryRowContext(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM review_cards WHERE due_at <= ?),
+			(SELECT COUNT(*) FROM expressions WHERE id NOT IN (SELECT expression_id FROM review_cards))
+	`, now.UTC()).Scan(&due, &unseen)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count due cards: %w", err)
+	}
+	return due, unseen, nil
+}
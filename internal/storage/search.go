@@ -0,0 +1,179 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+// SearchOptions は検索条件
+type SearchOptions struct {
+	Query       string   // 検索語（英語は前方一致、日本語は意味の部分一致）
+	Type        string   // "word" / "phrase"（空文字列ならすべて）
+	Category    string   // カテゴリでフィルタ（いずれかのカテゴリが一致。空文字列ならすべて）
+	Tags        []string // タグでフィルタ（すべてのタグがついた表現のみ）
+	MinPriority int      // 最小優先度（0ならフィルタなし）
+	Limit       int      // 最大件数（0なら無制限）
+}
+
+// ftsMigration は全文検索インデックスを作成するマイグレーション（FTS5が使えるビルドでのみ適用）
+const ftsMigration = "015_fts.sql"
+
+// ftsTriggers はFTS5インデックスを同期するトリガー（ftsMigrationで作成）
+var ftsTriggers = []string{
+	"expressions_fts_insert",
+	"expressions_fts_update",
+	"expressions_fts_delete",
+	"expressions_fts_occurrence_insert",
+	"expressions_fts_occurrence_move",
+	"expressions_fts_occurrence_delete",
+}
+
+// fts5Available はgo-sqlite3が sqlite_fts5 タグ付きでビルドされ、FTS5を使えるか判定
+func fts5Available(db *sql.DB) (bool, error) {
+	_, err := db.Exec(`CREATE VIRTUAL TABLE temp.fts5_probe USING fts5(x)`)
+	if err != nil {
+		if strings.Contains(err.Error(), "no such module") {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check fts5 support: %w", err)
+	}
+	if _, err := db.Exec(`DROP TABLE temp.fts5_probe`); err != nil {
+		return false, fmt.Errorf("failed to check fts5 support: %w", err)
+	}
+	return true, nil
+}
+
+// disableFullTextSearch はFTS5が使えないビルドで開いたときに、FTS5のビルドで作ったインデックスの同期トリガーを外す
+// トリガーが残っているとexpressionsへの書き込みが "no such module: fts5" で失敗するため
+// インデックスは同期されなくなるので、ftsMigrationを未適用に戻し、次にFTS5のビルドで開いたときに作り直す
+func disableFullTextSearch(db *sql.DB) error {
+	var exists bool
+	err := db.QueryRow(`SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE name = 'expressions_fts')`).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check fts table: %w", err)
+	}
+	if !exists {
+		return nil
+	}
+
+	for _, trigger := range ftsTriggers {
+		if _, err := db.Exec(`DROP TRIGGER IF EXISTS ` + trigger); err != nil {
+			return fmt.Errorf("failed to drop fts trigger: %w", err)
+		}
+	}
+	// schema_migrationsは最初のマイグレーションの前には存在しないので、テーブルがある場合のみ
+	_, err = db.Exec(`DELETE FROM schema_migrations WHERE version = ?`, ftsMigration)
+	if err != nil && !strings.Contains(err.Error(), "no such table") {
+		return fmt.Errorf("failed to reset fts migration: %w", err)
+	}
+	return nil
+}
+
+// Search は表現・意味・contextを全文検索
+func (r *SQLiteRepository) Search(ctx context.Context, opts SearchOptions) ([]*models.Expression, error) {
+	query := strings.TrimSpace(opts.Query)
+
+	var where []string
+	var args []interface{}
+	orderBy := "e.priority DESC, e.occurrence_count DESC, e.expression ASC"
+	from := "expressions e"
+
+	switch {
+	case query == "":
+		// 検索語なしの場合はフィルタのみ
+	case containsNonASCIILetter(query):
+		// 日本語は単語区切りがないため、意味・contextの部分一致で検索
+		pattern := "%" + escapeLike(query) + "%"
+		where = append(where, `(e.meaning LIKE ? ESCAPE '\' OR EXISTS (
+			SELECT 1 FROM expression_occurrences o WHERE o.expression_id = e.id AND o.context LIKE ? ESCAPE '\'))`)
+		args = append(args, pattern, pattern)
+	case r.fts:
+		from = "expressions_fts JOIN expressions e ON e.id = expressions_fts.rowid"
+		where = append(where, "expressions_fts MATCH ?")
+		args = append(args, buildMatchQuery(query))
+		orderBy = "bm25(expressions_fts), " + orderBy
+	default:
+		// FTS5が使えない場合は各語の前方一致（単語境界）をLIKEで代用
+		for _, term := range strings.Fields(query) {
+			prefix := escapeLike(term) + "%"
+			inner := "% " + escapeLike(term) + "%"
+			where = append(where, `(e.expression LIKE ? ESCAPE '\' OR e.expression LIKE ? ESCAPE '\'
+				OR e.meaning LIKE ? ESCAPE '\' OR EXISTS (
+				SELECT 1 FROM expression_occurrences o WHERE o.expression_id = e.id
+				AND (o.context LIKE ? ESCAPE '\' OR o.context LIKE ? ESCAPE '\')))`)
+			args = append(args, prefix, inner, "%"+escapeLike(term)+"%", prefix, inner)
+		}
+	}
+
+	if opts.Type != "" {
+		where = append(where, "e.type = ?")
+		args = append(args, opts.Type)
+	}
+	if opts.Category != "" {
+		// 主カテゴリ以外のカテゴリ（子カテゴリを選んだ場合の親カテゴリを含む）でも絞り込む
+		where = append(where, "EXISTS (SELECT 1 FROM expression_categories ec WHERE ec.expression_id = e.id AND ec.category = ?)")
+		args = append(args, opts.Category)
+	}
+	for _, tag := range opts.Tags {
+		where = append(where, "EXISTS (SELECT 1 FROM expression_tags et WHERE et.expression_id = e.id AND et.tag = ?)")
+		args = append(args, tag)
+	}
+	if opts.MinPriority > 0 {
+		where = append(where, "e.priority >= ?")
+		args = append(args, opts.MinPriority)
+	}
+
+	sqlQuery := `
+		SELECT ` + expressionColumnsOf("e") + `
+		FROM ` + from
+	if len(where) > 0 {
+		sqlQuery += "\n\t\tWHERE " + strings.Join(where, " AND ")
+	}
+	sqlQuery += "\n\t\tORDER BY " + orderBy
+	if opts.Limit > 0 {
+		sqlQuery += "\n\t\tLIMIT ?"
+		args = append(args, opts.Limit)
+	}
+
+	rows, err := r.q.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search expressions: %w", err)
+	}
+	defer rows.Close()
+
+	return scanExpressions(rows)
+}
+
+// buildMatchQuery は検索語をFTS5のMATCH式（各語の前方一致のAND）に変換
+func buildMatchQuery(query string) string {
+	terms := strings.Fields(query)
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		// ダブルクォートで囲んでFTS5の演算子として解釈されないようにする
+		term = strings.TrimSuffix(term, "*")
+		parts = append(parts, `"`+strings.ReplaceAll(term, `"`, `""`)+`"*`)
+	}
+	return strings.Join(parts, " ")
+}
+
+// escapeLike はLIKEパターンの特殊文字をエスケープ
+func escapeLike(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "%", `\%`)
+	return strings.ReplaceAll(s, "_", `\_`)
+}
+
+// containsNonASCIILetter は英字以外の文字（日本語など）を含むか判定
+func containsNonASCIILetter(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII && unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
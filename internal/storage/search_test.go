@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+func newTestRepository(t *testing.T) *SQLiteRepository {
+	t.Helper()
+	repo, err := NewSQLiteRepository(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+func TestSearch(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	fixtures := []struct {
+		expr    models.Expression
+		context string
+	}{
+		{models.Expression{Expression: "deprecate", Type: "word", Meaning: "非推奨にする", Priority: 5, Category: "engineering"}, "We need to deprecate the old endpoint."},
+		{models.Expression{Expression: "deployment", Type: "word", Meaning: "デプロイ", Priority: 4, Category: "engineering"}, "The deployment plan is ready."},
+		{models.Expression{Expression: "touch base", Type: "phrase", Meaning: "連絡を取る", Priority: 3, Category: "business"}, "I touched base with the frontend team."},
+		{models.Expression{Expression: "circle back", Type: "phrase", Meaning: "後で話し合う", Priority: 3, Category: "business"}, "Let's circle back on the API migration."},
+	}
+	for _, f := range fixtures {
+		expr := f.expr
+		if err := repo.SaveExpression(ctx, &expr); err != nil {
+			t.Fatalf("SaveExpression(%q): %v", expr.Expression, err)
+		}
+		if err := repo.AddOccurrence(ctx, &models.ExpressionOccurrence{ExpressionID: expr.ID, Context: f.context}); err != nil {
+			t.Fatalf("AddOccurrence(%q): %v", expr.Expression, err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		opts     SearchOptions
+		expected []string
+	}{
+		{"前方一致", SearchOptions{Query: "dep"}, []string{"deprecate", "deployment"}},
+		{"熟語の前方一致", SearchOptions{Query: "circ"}, []string{"circle back"}},
+		{"日本語の意味検索", SearchOptions{Query: "連絡"}, []string{"touch base"}},
+		{"contextの検索", SearchOptions{Query: "frontend"}, []string{"touch base"}},
+		{"タイプフィルタ", SearchOptions{Query: "api", Type: "phrase"}, []string{"circle back"}},
+		{"カテゴリフィルタ", SearchOptions{Query: "dep", Category: "business"}, nil},
+		{"優先度フィルタ", SearchOptions{Query: "dep", MinPriority: 5}, []string{"deprecate"}},
+		{"該当なし", SearchOptions{Query: "kubernetes"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := repo.Search(ctx, tt.opts)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+
+			found := make(map[string]bool)
+			for _, expr := range result {
+				found[expr.Expression] = true
+			}
+			if len(result) != len(tt.expected) {
+				t.Errorf("Search(%+v) returned %d results, expected %v", tt.opts, len(result), tt.expected)
+			}
+			for _, expected := range tt.expected {
+				if !found[expected] {
+					t.Errorf("Expected expression '%s' not found in result", expected)
+				}
+			}
+		})
+	}
+}
+
+func TestMigrationsAreIdempotent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	for i := 0; i < 2; i++ {
+		repo, err := NewSQLiteRepository(path)
+		if err != nil {
+			t.Fatalf("open #%d: %v", i+1, err)
+		}
+		repo.Close()
+	}
+}
+
+// FTS5のビルドで作ったDBをFTS5非対応のビルドで開いても書き込めて、FTS5のビルドで開き直すとインデックスが作り直されること
+func TestDisableFullTextSearch(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "test.db")
+	repo, err := NewSQLiteRepository(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteRepository: %v", err)
+	}
+	if !repo.fts {
+		repo.Close()
+		t.Skip("FTS5 is not available (build with -tags sqlite_fts5)")
+	}
+
+	// FTS5非対応のビルドで開いたときの処理
+	if err := disableFullTextSearch(repo.db); err != nil {
+		t.Fatalf("disableFullTextSearch: %v", err)
+	}
+	var triggers, applied int
+	repo.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name LIKE 'expressions_fts_%'`).Scan(&triggers)
+	repo.db.QueryRow(`SELECT COUNT(*) FROM schema_migrations WHERE version = ?`, ftsMigration).Scan(&applied)
+	if triggers != 0 || applied != 0 {
+		t.Fatalf("triggers = %d, applied = %d, expected both 0", triggers, applied)
+	}
+
+	expr := &models.Expression{Expression: "circle back", Type: "phrase", Meaning: "後で話し合う", Priority: 3}
+	if err := repo.SaveExpression(ctx, expr); err != nil {
+		t.Fatalf("SaveExpression: %v", err)
+	}
+	if err := repo.AddOccurrence(ctx, &models.ExpressionOccurrence{ExpressionID: expr.ID, Context: "Let's circle back on the API migration."}); err != nil {
+		t.Fatalf("AddOccurrence: %v", err)
+	}
+	repo.Close()
+
+	repo, err = NewSQLiteRepository(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer repo.Close()
+	result, err := repo.Search(ctx, SearchOptions{Query: "migration"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(result) != 1 || result[0].Expression != "circle back" {
+		t.Errorf("Search returned %v, expected [circle back]", result)
+	}
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+// ListSenses は表現の語義を登録順に取得（語義ごとの出現回数を含む）
+func (r *SQLiteRepository) ListSenses(ctx context.Context, expressionID int) ([]*models.Sense, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT s.id, s.expression_id, s.part_of_speech, s.gloss, s.definition, s.created_at,
+		       (SELECT COUNT(*) FROM expression_occurrences o WHERE o.sense_id = s.id)
+		FROM expression_senses s
+		WHERE s.expression_id = ?
+		ORDER BY s.id ASC
+	`, expressionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query senses: %w", err)
+	}
+	defer rows.Close()
+
+	var senses []*models.Sense
+	for rows.Next() {
+		var s models.Sense
+		if err := rows.Scan(&s.ID, &s.ExpressionID, &s.PartOfSpeech, &s.Gloss, &s.Definition, &s.CreatedAt, &s.OccurrenceCount); err != nil {
+			return nil, fmt.Errorf("failed to scan sense: %w", err)
+		}
+		senses = append(senses, &s)
+	}
+
+	return senses, rows.Err()
+}
+
+// AddSense は表現に語義を追加（sense.IDに採番されたIDを設定）
+func (r *SQLiteRepository) AddSense(ctx context.Context, sense *models.Sense) error {
+	if sense.Gloss == "" && sense.Definition == "" {
+		return fmt.Errorf("sense has neither gloss nor definition")
+	}
+	sense.PartOfSpeech = models.NormalizePartOfSpeech(sense.PartOfSpeech)
+
+	result, err := r.q.ExecContext(ctx, `
+		INSERT INTO expression_senses (expression_id, part_of_speech, gloss, definition)
+		VALUES (?, ?, ?, ?)
+	`, sense.ExpressionID, sense.PartOfSpeech, sense.Gloss, sense.Definition)
+	if err != nil {
+		return fmt.Errorf("failed to add sense: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	sense.ID = int(id)
+	return nil
+}
+
+// splitSenses は分割で復元した表現の出現履歴が参照する語義を復元した表現に戻す
+// 統合先の出現履歴でも使われている語義は複製し、復元した表現の出現履歴を複製に付け替える
+func splitSenses(ctx context.Context, q querier, restoredID, targetID int) error {
+	rows, err := q.QueryContext(ctx, `
+		SELECT DISTINCT o.sense_id,
+		       EXISTS (SELECT 1 FROM expression_occurrences t WHERE t.expression_id = ?2 AND t.sense_id = o.sense_id)
+		FROM expression_occurrences o
+		WHERE o.expression_id = ?1 AND o.sense_id IS NOT NULL
+	`, restoredID, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to query senses to split: %w", err)
+	}
+	type senseUse struct {
+		id     int
+		shared bool
+	}
+	var uses []senseUse
+	for rows.Next() {
+		var u senseUse
+		if err := rows.Scan(&u.id, &u.shared); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan sense: %w", err)
+		}
+		uses = append(uses, u)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to query senses to split: %w", err)
+	}
+
+	for _, u := range uses {
+		if !u.shared {
+			if _, err := q.ExecContext(ctx, `UPDATE expression_senses SET expression_id = ? WHERE id = ?`, restoredID, u.id); err != nil {
+				return fmt.Errorf("failed to move sense: %w", err)
+			}
+			continue
+		}
+
+		result, err := q.ExecContext(ctx, `
+			INSERT INTO expression_senses (expression_id, part_of_speech, gloss, definition, created_at)
+			SELECT ?, part_of_speech, gloss, definition, created_at FROM expression_senses WHERE id = ?
+		`, restoredID, u.id)
+		if err != nil {
+			return fmt.Errorf("failed to copy sense: %w", err)
+		}
+		copyID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+		_, err = q.ExecContext(ctx, `UPDATE expression_occurrences SET sense_id = ? WHERE expression_id = ? AND sense_id = ?`, copyID, restoredID, u.id)
+		if err != nil {
+			return fmt.Errorf("failed to update occurrence sense: %w", err)
+		}
+	}
+
+	return nil
+}
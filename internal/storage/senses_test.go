@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+func TestSenses(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	expr := &models.Expression{Expression: "ship", Type: "word", Priority: 4}
+	if err := repo.SaveExpression(ctx, expr); err != nil {
+		t.Fatalf("SaveExpression: %v", err)
+	}
+	deliver := &models.Sense{ExpressionID: expr.ID, PartOfSpeech: "Verb", Gloss: "出荷する", Definition: "to send goods to a customer"}
+	release := &models.Sense{ExpressionID: expr.ID, PartOfSpeech: "verb", Gloss: "リリースする", Definition: "to release software to users"}
+	for _, s := range []*models.Sense{deliver, release} {
+		if err := repo.AddSense(ctx, s); err != nil {
+			t.Fatalf("AddSense: %v", err)
+		}
+	}
+	if err := repo.AddSense(ctx, &models.Sense{ExpressionID: expr.ID, PartOfSpeech: "noun"}); err == nil {
+		t.Error("AddSense accepted a sense without gloss or definition")
+	}
+
+	for _, occ := range []*models.ExpressionOccurrence{
+		{ExpressionID: expr.ID, Context: "We ship the boxes today.", SenseID: deliver.ID},
+		{ExpressionID: expr.ID, Context: "Let's ship v2 on Friday.", SenseID: release.ID},
+		{ExpressionID: expr.ID, Context: "Ship it!", SenseID: release.ID},
+		{ExpressionID: expr.ID, Context: "ship"},
+	} {
+		if err := repo.AddOccurrence(ctx, occ); err != nil {
+			t.Fatalf("AddOccurrence: %v", err)
+		}
+	}
+
+	senses, err := repo.ListSenses(ctx, expr.ID)
+	if err != nil {
+		t.Fatalf("ListSenses: %v", err)
+	}
+	if len(senses) != 2 || senses[0].ID != deliver.ID || senses[0].PartOfSpeech != "verb" || senses[0].OccurrenceCount != 1 || senses[1].OccurrenceCount != 2 {
+		t.Errorf("unexpected senses: %+v %+v", senses[0], senses[1])
+	}
+
+	occurrences, err := repo.GetOccurrences(ctx, expr.ID)
+	if err != nil {
+		t.Fatalf("GetOccurrences: %v", err)
+	}
+	if occurrences[1].SenseID != release.ID || occurrences[3].SenseID != 0 {
+		t.Errorf("occurrence senses: %d %d", occurrences[1].SenseID, occurrences[3].SenseID)
+	}
+}
+
+func TestMergeSenses(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	target := &models.Expression{Expression: "ship", Type: "word", Priority: 4}
+	source := &models.Expression{Expression: "shipped", Type: "word", Priority: 3}
+	for _, expr := range []*models.Expression{target, source} {
+		if err := repo.SaveExpression(ctx, expr); err != nil {
+			t.Fatalf("SaveExpression: %v", err)
+		}
+	}
+	release := &models.Sense{ExpressionID: target.ID, PartOfSpeech: "verb", Gloss: "リリースする"}
+	deliver := &models.Sense{ExpressionID: source.ID, PartOfSpeech: "verb", Gloss: "出荷する"}
+	for _, s := range []*models.Sense{release, deliver} {
+		if err := repo.AddSense(ctx, s); err != nil {
+			t.Fatalf("AddSense: %v", err)
+		}
+	}
+	for _, occ := range []*models.ExpressionOccurrence{
+		{ExpressionID: target.ID, Context: "We ship v2 today.", SenseID: release.ID},
+		{ExpressionID: source.ID, Context: "The boxes shipped.", SenseID: deliver.ID},
+	} {
+		if err := repo.AddOccurrence(ctx, occ); err != nil {
+			t.Fatalf("AddOccurrence: %v", err)
+		}
+	}
+
+	if _, err := repo.MergeExpressions(ctx, source.ID, target.ID); err != nil {
+		t.Fatalf("MergeExpressions: %v", err)
+	}
+	senses, err := repo.ListSenses(ctx, target.ID)
+	if err != nil {
+		t.Fatalf("ListSenses: %v", err)
+	}
+	if len(senses) != 2 {
+		t.Fatalf("merged senses: %+v", senses)
+	}
+
+	// 統合後に統合先で使われた語義は、分割すると両方に残る
+	if err := repo.AddOccurrence(ctx, &models.ExpressionOccurrence{ExpressionID: target.ID, Context: "It shipped to the warehouse.", SenseID: deliver.ID}); err != nil {
+		t.Fatalf("AddOccurrence: %v", err)
+	}
+	if err := repo.AddOccurrence(ctx, &models.ExpressionOccurrence{ExpressionID: target.ID, Context: "v3 shipped.", Surface: "shipped", SenseID: release.ID}); err != nil {
+		t.Fatalf("AddOccurrence: %v", err)
+	}
+	restored, split, err := repo.SplitExpression(ctx, "shipped")
+	if err != nil {
+		t.Fatalf("SplitExpression: %v", err)
+	}
+
+	restoredSenses, err := repo.ListSenses(ctx, restored.ID)
+	if err != nil {
+		t.Fatalf("ListSenses: %v", err)
+	}
+	targetSenses, err := repo.ListSenses(ctx, split.ID)
+	if err != nil {
+		t.Fatalf("ListSenses: %v", err)
+	}
+	if len(restoredSenses) != 2 || restoredSenses[0].OccurrenceCount != 1 || restoredSenses[1].OccurrenceCount != 1 {
+		t.Errorf("restored senses: %+v", restoredSenses)
+	}
+	if len(targetSenses) != 2 || targetSenses[0].OccurrenceCount != 1 || targetSenses[1].OccurrenceCount != 1 {
+		t.Errorf("target senses: %+v", targetSenses)
+	}
+}
@@ -4,15 +4,21 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"os"
+	"io/fs"
+	"sort"
+	"strings"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/mamyudapao/learn-by-transcript/internal/models"
+	"github.com/mamyudapao/learn-by-transcript/migrations"
 )
 
 // SQLiteRepository はSQLiteベースのRepository実装
 type SQLiteRepository struct {
-	db *sql.DB
+	db  *sql.DB
+	q   querier // クエリの発行先（通常はdb、WithTx内ではtx）
+	tx  *sql.Tx // WithTxで束縛されたトランザクション（なければnil）
+	fts bool    // FTS5による全文検索が利用可能か
 }
 
 // NewSQLiteRepository は新しいSQLiteRepositoryを作成
@@ -22,38 +28,192 @@ func NewSQLiteRepository(dbPath string) (*SQLiteRepository, error) {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
+	// 全文検索はFTS5対応ビルドのみ（非対応ビルドではLIKE検索にフォールバック）
+	fts, err := fts5Available(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if !fts {
+		if err := disableFullTextSearch(db); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to disable full-text search: %w", err)
+		}
+	}
+
 	// マイグレーション実行
-	if err := runMigrations(db); err != nil {
+	if err := runMigrations(db, fts); err != nil {
 		db.Close()
 		return nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
 
-	return &SQLiteRepository{db: db}, nil
+	return &SQLiteRepository{db: db, q: db, fts: fts}, nil
 }
 
-// runMigrations はDBマイグレーションを実行
-func runMigrations(db *sql.DB) error {
-	schema, err := os.ReadFile("migrations/001_initial_schema.sql")
+// runMigrations はDBマイグレーションを番号順に実行（適用済みのものはスキップ）
+// ftsがfalseなら全文検索のマイグレーションは適用しない（FTS5対応ビルドで開いたときに適用する）
+func runMigrations(db *sql.DB, fts bool) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version TEXT PRIMARY KEY,
+			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
 	if err != nil {
-		return fmt.Errorf("failed to read migration file: %w", err)
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
 	}
 
-	_, err = db.Exec(string(schema))
+	files, err := fs.Glob(migrations.FS, "*.sql")
 	if err != nil {
-		return fmt.Errorf("failed to execute migration: %w", err)
+		return fmt.Errorf("failed to list migration files: %w", err)
+	}
+	sort.Strings(files)
+
+	for _, name := range files {
+		var applied int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM schema_migrations WHERE version = ?`, name).Scan(&applied); err != nil {
+			return fmt.Errorf("failed to check migration %s: %w", name, err)
+		}
+		if applied > 0 || (name == ftsMigration && !fts) {
+			continue
+		}
+
+		schema, err := fs.ReadFile(migrations.FS, name)
+		if err != nil {
+			return fmt.Errorf("failed to read migration file %s: %w", name, err)
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to begin migration %s: %w", name, err)
+		}
+		if _, err := tx.Exec(string(schema)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to execute migration %s: %w", name, err)
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version) VALUES (?)`, name); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record migration %s: %w", name, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %s: %w", name, err)
+		}
 	}
 
 	return nil
 }
 
-// SaveExpression は新しい表現を保存
+// expressionColumns はexpressionsテーブルから取得するカラム（scanExpressionと順序を揃える）
+var expressionColumns = expressionColumnsOf("expressions")
+
+// expressionColumnsOf は別名tableで参照するexpressionsテーブルのカラム（JOINするクエリ用）
+// 最後の2カラムはexpression_categoriesのカテゴリ（関連の強い順）とexpression_tagsのタグ（名前順）をカンマ区切りにしたもの
+func expressionColumnsOf(table string) string {
+	return strings.NewReplacer("{t}", table).Replace(`{t}.id, {t}.expression, {t}.type, {t}.meaning, {t}.priority, {t}.category,
+		       {t}.occurrence_count, {t}.first_seen_at, {t}.last_seen_at, {t}.updated_at, {t}.manually_edited,
+		       COALESCE({t}.base_priority, {t}.priority), COALESCE({t}.score, {t}.priority),
+		       COALESCE((SELECT GROUP_CONCAT(category, ',') FROM (
+		           SELECT category FROM expression_categories WHERE expression_id = {t}.id ORDER BY position
+		       )), ''),
+		       COALESCE((SELECT GROUP_CONCAT(tag, ',') FROM (
+		           SELECT tag FROM expression_tags WHERE expression_id = {t}.id ORDER BY tag
+		       )), '')`)
+}
+
+// rowScanner は*sql.Rowと*sql.Rowsの共通インターフェース
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// expressionScan はexpressionColumnsの順のスキャン先（他のカラムと一緒にスキャンする場合に使う）
+type expressionScan struct {
+	expr       models.Expression
+	categories string
+	tags       string
+}
+
+// dest はRow.Scanに渡すスキャン先
+func (s *expressionScan) dest() []interface{} {
+	e := &s.expr
+	return []interface{}{
+		&e.ID, &e.Expression, &e.Type, &e.Meaning, &e.Priority, &e.Category,
+		&e.OccurrenceCount, &e.FirstSeenAt, &e.LastSeenAt, &e.UpdatedAt, &e.ManuallyEdited,
+		&e.BasePriority, &e.Score, &s.categories, &s.tags,
+	}
+}
+
+// expression はスキャンした表現（カテゴリ・タグを展開）
+func (s *expressionScan) expression() *models.Expression {
+	expr := s.expr
+	expr.Categories = splitCategories(s.categories)
+	expr.Tags = splitCategories(s.tags)
+	// 分類体系にないカテゴリなどで対応が未登録の場合は主カテゴリのみ
+	if len(expr.Categories) == 0 && expr.Category != "" {
+		expr.Categories = []string{expr.Category}
+	}
+	return &expr
+}
+
+// splitCategories はカンマ区切りのカテゴリ（またはタグ）を分割
+func splitCategories(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// scanExpression はexpressionColumnsの順で1行をスキャン
+func scanExpression(row rowScanner) (*models.Expression, error) {
+	var s expressionScan
+	if err := row.Scan(s.dest()...); err != nil {
+		return nil, err
+	}
+	return s.expression(), nil
+}
+
+// scanExpressions は複数行をスキャン
+func scanExpressions(rows *sql.Rows) ([]*models.Expression, error) {
+	var expressions []*models.Expression
+	for rows.Next() {
+		expr, err := scanExpression(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan expression: %w", err)
+		}
+		expressions = append(expressions, expr)
+	}
+
+	return expressions, rows.Err()
+}
+
+// basePriority は表現の基準の優先度（未設定なら優先度）
+func basePriority(expr *models.Expression) int {
+	if expr.BasePriority == 0 {
+		return expr.Priority
+	}
+	return expr.BasePriority
+}
+
+// SaveExpression は新しい表現を保存（基準の優先度が未設定なら優先度を基準とし、スコアは基準の優先度から始める）
 func (r *SQLiteRepository) SaveExpression(ctx context.Context, expr *models.Expression) error {
+	tx, err := r.begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 出現回数は出現履歴の追加時にトリガーで数えるので0から始める（列の初期値1のままだと1件多くなる）
 	query := `
-		INSERT INTO expressions (expression, type, meaning, priority, category)
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO expressions (expression, type, meaning, priority, category, manually_edited, occurrence_count, base_priority, score)
+		VALUES (?, ?, ?, ?, ?, ?, 0, ?, ?)
 	`
 
-	result, err := r.db.ExecContext(ctx, query, expr.Expression, expr.Type, expr.Meaning, expr.Priority, expr.Category)
+	normalizeCategories(expr)
+	expr.BasePriority = basePriority(expr)
+	if expr.Score == 0 {
+		expr.Score = float64(expr.Priority)
+	}
+	result, err := tx.ExecContext(ctx, query, expr.Expression, expr.Type, expr.Meaning, expr.Priority, expr.Category, expr.ManuallyEdited,
+		expr.BasePriority, expr.Score)
 	if err != nil {
 		return fmt.Errorf("failed to save expression: %w", err)
 	}
@@ -62,34 +222,78 @@ func (r *SQLiteRepository) SaveExpression(ctx context.Context, expr *models.Expr
 	if err != nil {
 		return fmt.Errorf("failed to get last insert ID: %w", err)
 	}
+	if err := setCategories(ctx, tx, int(id), expr.Categories); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit expression: %w", err)
+	}
 
 	expr.ID = int(id)
 	return nil
 }
 
-// GetExpression は表現を取得
+// normalizeCategories は表現のカテゴリの重複・空文字列を除き、主カテゴリ（Category）を先頭に揃える
+// Categoriesが空ならCategoryのみ、CategoryがCategoriesの先頭と異なればCategoryを先頭に追加する
+func normalizeCategories(expr *models.Expression) {
+	categories := expr.Categories
+	if expr.Category != "" && (len(categories) == 0 || categories[0] != expr.Category) {
+		categories = append([]string{expr.Category}, categories...)
+	}
+
+	seen := make(map[string]bool)
+	normalized := make([]string, 0, len(categories))
+	for _, c := range categories {
+		if c == "" || seen[c] {
+			continue
+		}
+		seen[c] = true
+		normalized = append(normalized, c)
+	}
+
+	expr.Categories = normalized
+	expr.Category = ""
+	if len(normalized) > 0 {
+		expr.Category = normalized[0]
+	}
+}
+
+// setCategories は表現のカテゴリを置き換える（categoriesの順に関連が強い）
+func setCategories(ctx context.Context, q querier, expressionID int, categories []string) error {
+	if _, err := q.ExecContext(ctx, `DELETE FROM expression_categories WHERE expression_id = ?`, expressionID); err != nil {
+		return fmt.Errorf("failed to clear categories: %w", err)
+	}
+	for i, category := range categories {
+		_, err := q.ExecContext(ctx, `
+			INSERT INTO expression_categories (expression_id, category, position)
+			VALUES (?, ?, ?)
+		`, expressionID, category, i)
+		if err != nil {
+			return fmt.Errorf("failed to add category: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetExpression は表現を取得（別名の場合は統合先の表現を返す）
 func (r *SQLiteRepository) GetExpression(ctx context.Context, expression string) (*models.Expression, error) {
 	query := `
-		SELECT id, expression, type, meaning, priority, category, occurrence_count,
-		       first_seen_at, last_seen_at, updated_at
+		SELECT ` + expressionColumns + `
 		FROM expressions
 		WHERE expression = ?
 	`
 
-	var expr models.Expression
-	err := r.db.QueryRowContext(ctx, query, expression).Scan(
-		&expr.ID, &expr.Expression, &expr.Type, &expr.Meaning, &expr.Priority, &expr.Category,
-		&expr.OccurrenceCount, &expr.FirstSeenAt, &expr.LastSeenAt, &expr.UpdatedAt,
-	)
-
+	expr, err := scanExpression(r.q.QueryRowContext(ctx, query, expression))
 	if err == sql.ErrNoRows {
-		return nil, nil
+		// 統合済みの表記なら統合先の表現を返す
+		return r.getExpressionByAlias(ctx, r.q, expression)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get expression: %w", err)
 	}
 
-	return &expr, nil
+	return expr, nil
 }
 
 // ExpressionExists は表現が既に存在するか確認
@@ -102,139 +306,156 @@ func (r *SQLiteRepository) ExpressionExists(ctx context.Context, expression stri
 }
 
 // AddOccurrence は出現履歴を追加
-func (r *SQLiteRepository) AddOccurrence(ctx context.Context, expressionID int, context string) error {
+func (r *SQLiteRepository) AddOccurrence(ctx context.Context, occ *models.ExpressionOccurrence) error {
 	query := `
-		INSERT INTO expression_occurrences (expression_id, context)
-		VALUES (?, ?)
+		INSERT INTO expression_occurrences (expression_id, context, surface, meeting, sense_id)
+		VALUES (?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, 0))
 	`
 
-	_, err := r.db.ExecContext(ctx, query, expressionID, context)
+	result, err := r.q.ExecContext(ctx, query, occ.ExpressionID, occ.Context, occ.Surface, occ.Meeting, occ.SenseID)
 	if err != nil {
 		return fmt.Errorf("failed to add occurrence: %w", err)
 	}
 
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	occ.ID = int(id)
 	return nil
 }
 
-// UpdatePriority は優先度を更新
-func (r *SQLiteRepository) UpdatePriority(ctx context.Context, expressionID int, priority int) error {
+// UpdateScore はスコアと優先度を更新（差分エクスポートの対象にならないよう、更新日時は優先度が変わった場合のみ更新）
+func (r *SQLiteRepository) UpdateScore(ctx context.Context, expressionID int, score float64, priority int) error {
 	query := `
 		UPDATE expressions
-		SET priority = ?, updated_at = CURRENT_TIMESTAMP
-		WHERE id = ?
+		SET score = ?1, priority = ?2,
+		    updated_at = CASE WHEN priority = ?2 THEN updated_at ELSE CURRENT_TIMESTAMP END
+		WHERE id = ?3
 	`
 
-	_, err := r.db.ExecContext(ctx, query, priority, expressionID)
+	_, err := r.q.ExecContext(ctx, query, score, priority, expressionID)
 	if err != nil {
-		return fmt.Errorf("failed to update priority: %w", err)
+		return fmt.Errorf("failed to update score: %w", err)
 	}
 
 	return nil
 }
 
+// UpdateExpression は表現の意味・優先度・基準の優先度・カテゴリ・種類・手動編集フラグを更新
+func (r *SQLiteRepository) UpdateExpression(ctx context.Context, expr *models.Expression) error {
+	tx, err := r.begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE expressions
+		SET type = ?, meaning = ?, priority = ?, base_priority = ?, category = ?, manually_edited = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`
+
+	normalizeCategories(expr)
+	_, err = tx.ExecContext(ctx, query, expr.Type, expr.Meaning, expr.Priority, basePriority(expr), expr.Category, expr.ManuallyEdited, expr.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update expression: %w", err)
+	}
+	if err := setCategories(ctx, tx, expr.ID, expr.Categories); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteExpression は表現と出現履歴を削除
+func (r *SQLiteRepository) DeleteExpression(ctx context.Context, expressionID int) error {
+	tx, err := r.begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// foreign_keysが無効でもCASCADE相当になるよう関連テーブルを先に削除
+	for _, table := range []string{"expression_occurrences", "expression_senses", "expression_aliases", "expression_categories", "expression_tags", "expression_usage", "expression_examples", "expression_related_words",
+		"review_cards", "review_logs", "notion_pages"} {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE expression_id = ?`, expressionID); err != nil {
+			return fmt.Errorf("failed to delete from %s: %w", table, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM expressions WHERE id = ?`, expressionID); err != nil {
+		return fmt.Errorf("failed to delete expression: %w", err)
+	}
+	if err := deleteUnusedTags(ctx, tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 // GetAllExpressions はすべての表現を取得
 func (r *SQLiteRepository) GetAllExpressions(ctx context.Context) ([]*models.Expression, error) {
 	query := `
-		SELECT id, expression, type, meaning, priority, category, occurrence_count,
-		       first_seen_at, last_seen_at, updated_at
+		SELECT ` + expressionColumns + `
 		FROM expressions
 		ORDER BY priority DESC, occurrence_count DESC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.q.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query expressions: %w", err)
 	}
 	defer rows.Close()
 
-	var expressions []*models.Expression
-	for rows.Next() {
-		var expr models.Expression
-		err := rows.Scan(
-			&expr.ID, &expr.Expression, &expr.Type, &expr.Meaning, &expr.Priority, &expr.Category,
-			&expr.OccurrenceCount, &expr.FirstSeenAt, &expr.LastSeenAt, &expr.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan expression: %w", err)
-		}
-		expressions = append(expressions, &expr)
-	}
-
-	return expressions, nil
+	return scanExpressions(rows)
 }
 
 // GetTopExpressions は優先度・出現頻度の高い表現を取得
 func (r *SQLiteRepository) GetTopExpressions(ctx context.Context, limit int) ([]*models.Expression, error) {
 	query := `
-		SELECT id, expression, type, meaning, priority, category, occurrence_count,
-		       first_seen_at, last_seen_at, updated_at
+		SELECT ` + expressionColumns + `
 		FROM expressions
 		ORDER BY priority DESC, occurrence_count DESC
 		LIMIT ?
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, limit)
+	rows, err := r.q.QueryContext(ctx, query, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query top expressions: %w", err)
 	}
 	defer rows.Close()
 
-	var expressions []*models.Expression
-	for rows.Next() {
-		var expr models.Expression
-		err := rows.Scan(
-			&expr.ID, &expr.Expression, &expr.Type, &expr.Meaning, &expr.Priority, &expr.Category,
-			&expr.OccurrenceCount, &expr.FirstSeenAt, &expr.LastSeenAt, &expr.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan expression: %w", err)
-		}
-		expressions = append(expressions, &expr)
-	}
-
-	return expressions, nil
+	return scanExpressions(rows)
 }
 
 // ListExpressions はすべての表現を取得（優先度・出現回数順）
 func (r *SQLiteRepository) ListExpressions(ctx context.Context) ([]*models.Expression, error) {
 	query := `
-		SELECT id, expression, type, meaning, priority, category, occurrence_count, first_seen_at, last_seen_at, updated_at
+		SELECT ` + expressionColumns + `
 		FROM expressions
 		ORDER BY priority DESC, occurrence_count DESC, expression ASC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.q.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query expressions: %w", err)
 	}
 	defer rows.Close()
 
-	var expressions []*models.Expression
-	for rows.Next() {
-		var expr models.Expression
-		err := rows.Scan(
-			&expr.ID, &expr.Expression, &expr.Type, &expr.Meaning, &expr.Priority, &expr.Category,
-			&expr.OccurrenceCount, &expr.FirstSeenAt, &expr.LastSeenAt, &expr.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan expression: %w", err)
-		}
-		expressions = append(expressions, &expr)
-	}
-
-	return expressions, nil
+	return scanExpressions(rows)
 }
 
 // GetOccurrences は表現の出現履歴を取得
 func (r *SQLiteRepository) GetOccurrences(ctx context.Context, expressionID int) ([]*models.ExpressionOccurrence, error) {
 	query := `
-		SELECT id, expression_id, context, occurred_at
+		SELECT id, expression_id, context, COALESCE(surface, ''), COALESCE(meeting, ''), COALESCE(sense_id, 0), occurred_at
 		FROM expression_occurrences
 		WHERE expression_id = ?
 		ORDER BY occurred_at ASC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, expressionID)
+	rows, err := r.q.QueryContext(ctx, query, expressionID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query occurrences: %w", err)
 	}
@@ -243,7 +464,7 @@ func (r *SQLiteRepository) GetOccurrences(ctx context.Context, expressionID int)
 	var occurrences []*models.ExpressionOccurrence
 	for rows.Next() {
 		var occ models.ExpressionOccurrence
-		err := rows.Scan(&occ.ID, &occ.ExpressionID, &occ.Context, &occ.OccurredAt)
+		err := rows.Scan(&occ.ID, &occ.ExpressionID, &occ.Context, &occ.Surface, &occ.Meeting, &occ.SenseID, &occ.OccurredAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan occurrence: %w", err)
 		}
@@ -255,5 +476,9 @@ func (r *SQLiteRepository) GetOccurrences(ctx context.Context, expressionID int)
 
 // Close はリソースをクリーンアップ
 func (r *SQLiteRepository) Close() error {
+	// トランザクションに束縛されたリポジトリはDBを閉じない
+	if r.tx != nil {
+		return nil
+	}
 	return r.db.Close()
 }
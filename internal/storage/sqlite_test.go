@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+func TestUpdateAndDeleteExpression(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	expr := &models.Expression{Expression: "ship", Type: "word", Meaning: "船", Priority: 2, Category: "casual"}
+	if err := repo.SaveExpression(ctx, expr); err != nil {
+		t.Fatalf("SaveExpression: %v", err)
+	}
+	if err := repo.AddOccurrence(ctx, &models.ExpressionOccurrence{ExpressionID: expr.ID, Context: "We ship on Friday."}); err != nil {
+		t.Fatalf("AddOccurrence: %v", err)
+	}
+
+	expr.Meaning = "リリースする"
+	expr.Priority = 5
+	expr.Category = "engineering"
+	expr.ManuallyEdited = true
+	if err := repo.UpdateExpression(ctx, expr); err != nil {
+		t.Fatalf("UpdateExpression: %v", err)
+	}
+
+	got, err := repo.GetExpression(ctx, "ship")
+	if err != nil {
+		t.Fatalf("GetExpression: %v", err)
+	}
+	if got.Meaning != "リリースする" || got.Priority != 5 || got.Category != "engineering" || !got.ManuallyEdited {
+		t.Errorf("UpdateExpression did not persist changes: %+v", got)
+	}
+
+	if err := repo.DeleteExpression(ctx, expr.ID); err != nil {
+		t.Fatalf("DeleteExpression: %v", err)
+	}
+	got, err = repo.GetExpression(ctx, "ship")
+	if err != nil {
+		t.Fatalf("GetExpression: %v", err)
+	}
+	if got != nil {
+		t.Errorf("expression still exists after delete: %+v", got)
+	}
+	occurrences, err := repo.GetOccurrences(ctx, expr.ID)
+	if err != nil {
+		t.Fatalf("GetOccurrences: %v", err)
+	}
+	if len(occurrences) != 0 {
+		t.Errorf("occurrences still exist after delete: %d", len(occurrences))
+	}
+}
+
+// 出現回数は出現履歴の件数と一致する（以前は列の初期値1にトリガーの加算が重なり1件多かった）
+func TestOccurrenceCountMatchesHistory(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	expr := &models.Expression{Expression: "ship", Type: "word", Meaning: "リリースする", Priority: 3}
+	if err := repo.SaveExpression(ctx, expr); err != nil {
+		t.Fatalf("SaveExpression: %v", err)
+	}
+	for _, c := range []string{"We ship on Friday.", "Let's ship it."} {
+		if err := repo.AddOccurrence(ctx, &models.ExpressionOccurrence{ExpressionID: expr.ID, Context: c}); err != nil {
+			t.Fatalf("AddOccurrence: %v", err)
+		}
+	}
+	got, err := repo.GetExpression(ctx, "ship")
+	if err != nil {
+		t.Fatalf("GetExpression: %v", err)
+	}
+	if got.OccurrenceCount != 2 {
+		t.Errorf("occurrence count = %d, expected 2", got.OccurrenceCount)
+	}
+
+	// 修正前に保存された1件多い出現回数はマイグレーションで数え直す
+	if _, err := repo.db.Exec(`UPDATE expressions SET occurrence_count = occurrence_count + 1`); err != nil {
+		t.Fatalf("failed to inflate occurrence count: %v", err)
+	}
+	if _, err := repo.db.Exec(`DELETE FROM schema_migrations WHERE version = '016_occurrence_count.sql'`); err != nil {
+		t.Fatalf("failed to reset migration: %v", err)
+	}
+	if err := runMigrations(repo.db, repo.fts); err != nil {
+		t.Fatalf("runMigrations: %v", err)
+	}
+	got, err = repo.GetExpression(ctx, "ship")
+	if err != nil {
+		t.Fatalf("GetExpression: %v", err)
+	}
+	if got.OccurrenceCount != 2 {
+		t.Errorf("occurrence count after migration = %d, expected 2", got.OccurrenceCount)
+	}
+}
+
+func TestMergeAndSplitExpressions(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	save := func(expression string, priority int, contexts ...string) *models.Expression {
+		t.Helper()
+		expr := &models.Expression{Expression: expression, Type: "phrase", Meaning: "", Priority: priority, Category: "engineering"}
+		if err := repo.SaveExpression(ctx, expr); err != nil {
+			t.Fatalf("SaveExpression(%q): %v", expression, err)
+		}
+		for _, c := range contexts {
+			occ := &models.ExpressionOccurrence{ExpressionID: expr.ID, Context: c, Surface: expression}
+			if err := repo.AddOccurrence(ctx, occ); err != nil {
+				t.Fatalf("AddOccurrence(%q): %v", expression, err)
+			}
+		}
+		return expr
+	}
+
+	target := save("pull request", 4, "Open a pull request.")
+	source := save("pull requests", 3, "Review pull requests.", "Merge the pull requests.")
+
+	merged, err := repo.MergeExpressions(ctx, source.ID, target.ID)
+	if err != nil {
+		t.Fatalf("MergeExpressions: %v", err)
+	}
+	if merged.OccurrenceCount != 3 {
+		t.Errorf("merged occurrence count = %d, expected 3", merged.OccurrenceCount)
+	}
+
+	// 統合元の表記は別名として統合先に解決される
+	resolved, err := repo.GetExpression(ctx, "pull requests")
+	if err != nil {
+		t.Fatalf("GetExpression: %v", err)
+	}
+	if resolved == nil || resolved.ID != target.ID {
+		t.Fatalf("alias was not resolved to target: %+v", resolved)
+	}
+
+	restored, updatedTarget, err := repo.SplitExpression(ctx, "pull requests")
+	if err != nil {
+		t.Fatalf("SplitExpression: %v", err)
+	}
+	if restored.Expression != "pull requests" || restored.Priority != 3 || restored.OccurrenceCount != 2 {
+		t.Errorf("unexpected restored expression: %+v", restored)
+	}
+	if updatedTarget.OccurrenceCount != 1 {
+		t.Errorf("target occurrence count = %d, expected 1", updatedTarget.OccurrenceCount)
+	}
+	aliases, err := repo.GetAliases(ctx, target.ID)
+	if err != nil {
+		t.Fatalf("GetAliases: %v", err)
+	}
+	if len(aliases) != 0 {
+		t.Errorf("alias still exists after split: %v", aliases)
+	}
+}
@@ -0,0 +1,251 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+// Interval は統計を集計する時間の単位
+type Interval string
+
+const (
+	IntervalDay   Interval = "day"
+	IntervalWeek  Interval = "week" // 月曜始まり
+	IntervalMonth Interval = "month"
+)
+
+// Intervals は指定できる集計単位
+var Intervals = []Interval{IntervalDay, IntervalWeek, IntervalMonth}
+
+// ParseInterval は文字列を集計単位に変換
+func ParseInterval(s string) (Interval, error) {
+	for _, i := range Intervals {
+		if string(i) == s {
+			return i, nil
+		}
+	}
+	return "", fmt.Errorf("invalid interval: %s (expected day, week or month)", s)
+}
+
+// Truncate はtを含む期間の開始（ローカル時刻の0時）を返す
+func (i Interval) Truncate(t time.Time) time.Time {
+	t = t.Local()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+	switch i {
+	case IntervalWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case IntervalMonth:
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day
+}
+
+// Add は期間の開始startからn期間後（nが負なら前）の期間の開始を返す
+func (i Interval) Add(start time.Time, n int) time.Time {
+	switch i {
+	case IntervalWeek:
+		return start.AddDate(0, 0, 7*n)
+	case IntervalMonth:
+		return start.AddDate(0, n, 0)
+	}
+	return start.AddDate(0, 0, n)
+}
+
+// PeriodRange は期間ごとに集計する範囲（SinceとUntilを含む期間すべて）
+type PeriodRange struct {
+	Interval Interval
+	Since    time.Time
+	Until    time.Time
+}
+
+// PeriodCount は1つの期間の件数
+type PeriodCount struct {
+	Start time.Time // 期間の開始（ローカル時刻）
+	Count int
+}
+
+// NameCount は名前（カテゴリなど）ごとの件数
+type NameCount struct {
+	Name  string
+	Count int
+}
+
+// FrequentExpression は期間内の出現回数付きの表現
+type FrequentExpression struct {
+	Expression *models.Expression
+	Count      int // 期間内の出現回数
+}
+
+// StatsTotals はデータベース全体の件数
+type StatsTotals struct {
+	Expressions    int
+	Words          int
+	Phrases        int
+	Occurrences    int
+	MissingMeaning int // 意味が空の表現
+	ManuallyEdited int
+}
+
+// sqliteTimestamp はCURRENT_TIMESTAMPと同じ形式（UTC）で日時を文字列にする（保存済みの値と文字列で比較するため）
+func sqliteTimestamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+// CountNewExpressions は期間ごとに新しく登録された表現（first_seen_at）の数を集計
+func (r *SQLiteRepository) CountNewExpressions(ctx context.Context, rng PeriodRange) ([]PeriodCount, error) {
+	counts, err := r.countByPeriod(ctx, "expressions", "first_seen_at", rng)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count new expressions: %w", err)
+	}
+	return counts, nil
+}
+
+// CountOccurrences は期間ごとの出現回数を集計
+func (r *SQLiteRepository) CountOccurrences(ctx context.Context, rng PeriodRange) ([]PeriodCount, error) {
+	counts, err := r.countByPeriod(ctx, "expression_occurrences", "occurred_at", rng)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count occurrences: %w", err)
+	}
+	return counts, nil
+}
+
+// countByPeriod はtableの日時columnを期間ごとに数える（件数0の期間も含む）
+// 夏時間の切り替えをまたぐとUTCとの時差が変わるため、SQLでは範囲の絞り込みだけ行い、
+// 各行の日時をtime.Localで期間に振り分ける
+func (r *SQLiteRepository) countByPeriod(ctx context.Context, table, column string, rng PeriodRange) ([]PeriodCount, error) {
+	start := rng.Interval.Truncate(rng.Since)
+	end := rng.Interval.Add(rng.Interval.Truncate(rng.Until), 1)
+
+	var counts []PeriodCount
+	index := make(map[time.Time]int)
+	for t := start; t.Before(end); t = rng.Interval.Add(t, 1) {
+		index[t] = len(counts)
+		counts = append(counts, PeriodCount{Start: t})
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %[2]s
+		FROM %[1]s
+		WHERE %[2]s >= ? AND %[2]s < ?
+	`, table, column)
+	rows, err := r.q.QueryContext(ctx, query, sqliteTimestamp(start), sqliteTimestamp(end))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var at time.Time
+		if err := rows.Scan(&at); err != nil {
+			return nil, err
+		}
+		if i, ok := index[rng.Interval.Truncate(at)]; ok {
+			counts[i].Count++
+		}
+	}
+
+	return counts, rows.Err()
+}
+
+// CountByCategory はカテゴリごとの表現数を多い順に集計
+// 複数のカテゴリに属する表現はそれぞれのカテゴリで数える（カテゴリのない表現は名前が空の行）
+func (r *SQLiteRepository) CountByCategory(ctx context.Context) ([]NameCount, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT category, COUNT(*) AS n
+		FROM expression_categories
+		GROUP BY category
+		UNION ALL
+		SELECT '', COUNT(*) AS n
+		FROM expressions e
+		WHERE NOT EXISTS (SELECT 1 FROM expression_categories c WHERE c.expression_id = e.id)
+		HAVING n > 0
+		ORDER BY n DESC, category ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count categories: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []NameCount
+	for rows.Next() {
+		var c NameCount
+		if err := rows.Scan(&c.Name, &c.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan category count: %w", err)
+		}
+		counts = append(counts, c)
+	}
+
+	return counts, rows.Err()
+}
+
+// GetFrequentExpressions はsince以降の出現回数が多い表現を取得
+func (r *SQLiteRepository) GetFrequentExpressions(ctx context.Context, since time.Time, limit int) ([]*FrequentExpression, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT `+expressionColumnsOf("e")+`, COUNT(*) AS n
+		FROM expression_occurrences o
+		JOIN expressions e ON e.id = o.expression_id
+		WHERE o.occurred_at >= ?
+		GROUP BY e.id
+		ORDER BY n DESC, e.priority DESC, e.expression ASC
+		LIMIT ?
+	`, sqliteTimestamp(since), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get frequent expressions: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*FrequentExpression
+	for rows.Next() {
+		var expr expressionScan
+		var count int
+		if err := rows.Scan(append(expr.dest(), &count)...); err != nil {
+			return nil, fmt.Errorf("failed to scan expression: %w", err)
+		}
+		results = append(results, &FrequentExpression{Expression: expr.expression(), Count: count})
+	}
+
+	return results, rows.Err()
+}
+
+// GetExpressionsWithoutMeaning は意味が空の表現を優先度・出現頻度の高い順に取得
+func (r *SQLiteRepository) GetExpressionsWithoutMeaning(ctx context.Context, limit int) ([]*models.Expression, error) {
+	query := `
+		SELECT ` + expressionColumns + `
+		FROM expressions
+		WHERE TRIM(COALESCE(meaning, '')) = ''
+		ORDER BY priority DESC, occurrence_count DESC, expression ASC
+		LIMIT ?
+	`
+	rows, err := r.q.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expressions without meaning: %w", err)
+	}
+	defer rows.Close()
+
+	return scanExpressions(rows)
+}
+
+// GetStatsTotals はデータベース全体の件数を取得
+func (r *SQLiteRepository) GetStatsTotals(ctx context.Context) (*StatsTotals, error) {
+	var totals StatsTotals
+	err := r.q.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*),
+			COALESCE(SUM(type = ?), 0),
+			COALESCE(SUM(type = ?), 0),
+			(SELECT COUNT(*) FROM expression_occurrences),
+			COALESCE(SUM(TRIM(COALESCE(meaning, '')) = ''), 0),
+			COALESCE(SUM(manually_edited), 0)
+		FROM expressions
+	`, string(models.TypeWord), string(models.TypePhrase)).Scan(
+		&totals.Expressions, &totals.Words, &totals.Phrases, &totals.Occurrences, &totals.MissingMeaning, &totals.ManuallyEdited,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get totals: %w", err)
+	}
+
+	return &totals, nil
+}
@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+func TestStats(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	fixtures := []*models.Expression{
+		{Expression: "deprecate", Type: "word", Meaning: "非推奨にする", Priority: 5, Category: "engineering"},
+		{Expression: "rollout", Type: "word", Meaning: "", Priority: 4, Category: "engineering"},
+		{Expression: "circle back", Type: "phrase", Meaning: "後で話し合う", Priority: 3, Category: "business", Categories: []string{"business", "engineering"}},
+	}
+	for _, expr := range fixtures {
+		if err := repo.SaveExpression(ctx, expr); err != nil {
+			t.Fatalf("SaveExpression: %v", err)
+		}
+	}
+
+	// 出現日時を固定（ローカル日付がずれないよう正午UTC）
+	// 2026-01-05（月）と01-11（日）は同じ週、01-12（月）は翌週
+	occurrences := []struct {
+		expr *models.Expression
+		at   string
+	}{
+		{fixtures[0], "2026-01-05 12:00:00"},
+		{fixtures[0], "2026-01-11 12:00:00"},
+		{fixtures[0], "2026-01-12 12:00:00"},
+		{fixtures[2], "2026-01-12 12:00:00"},
+	}
+	for _, o := range occurrences {
+		occ := &models.ExpressionOccurrence{ExpressionID: o.expr.ID, Context: "..."}
+		if err := repo.AddOccurrence(ctx, occ); err != nil {
+			t.Fatalf("AddOccurrence: %v", err)
+		}
+		if _, err := repo.db.Exec(`UPDATE expression_occurrences SET occurred_at = ? WHERE id = ?`, o.at, occ.ID); err != nil {
+			t.Fatalf("failed to set occurred_at: %v", err)
+		}
+	}
+	if _, err := repo.db.Exec(`UPDATE expressions SET first_seen_at = '2026-01-06 12:00:00'`); err != nil {
+		t.Fatalf("failed to set first_seen_at: %v", err)
+	}
+
+	rng := PeriodRange{
+		Interval: IntervalWeek,
+		Since:    time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local),
+		Until:    time.Date(2026, 1, 20, 12, 0, 0, 0, time.Local),
+	}
+	occCounts, err := repo.CountOccurrences(ctx, rng)
+	if err != nil {
+		t.Fatalf("CountOccurrences: %v", err)
+	}
+	expected := []int{0, 2, 2, 0} // 12/29, 1/5, 1/12, 1/19の週
+	if len(occCounts) != len(expected) {
+		t.Fatalf("got %d periods, expected %d: %+v", len(occCounts), len(expected), occCounts)
+	}
+	for i, c := range occCounts {
+		if c.Count != expected[i] {
+			t.Errorf("period %s: count = %d, expected %d", c.Start.Format("2006-01-02"), c.Count, expected[i])
+		}
+		if c.Start.Weekday() != time.Monday {
+			t.Errorf("period %s does not start on Monday", c.Start.Format("2006-01-02"))
+		}
+	}
+
+	newCounts, err := repo.CountNewExpressions(ctx, PeriodRange{Interval: IntervalMonth, Since: rng.Since, Until: rng.Until})
+	if err != nil {
+		t.Fatalf("CountNewExpressions: %v", err)
+	}
+	if len(newCounts) != 1 || newCounts[0].Count != 3 {
+		t.Errorf("unexpected new expression counts: %+v", newCounts)
+	}
+
+	categories, err := repo.CountByCategory(ctx)
+	if err != nil {
+		t.Fatalf("CountByCategory: %v", err)
+	}
+	// 複数のカテゴリに属する表現はそれぞれで数える
+	expectedCategories := []NameCount{{Name: "engineering", Count: 3}, {Name: "business", Count: 1}}
+	if !reflect.DeepEqual(categories, expectedCategories) {
+		t.Errorf("unexpected categories: %+v", categories)
+	}
+
+	frequent, err := repo.GetFrequentExpressions(ctx, time.Date(2026, 1, 12, 0, 0, 0, 0, time.UTC), 10)
+	if err != nil {
+		t.Fatalf("GetFrequentExpressions: %v", err)
+	}
+	if len(frequent) != 2 || frequent[0].Expression.Expression != "deprecate" || frequent[0].Count != 1 {
+		t.Errorf("unexpected frequent expressions: %+v", frequent)
+	}
+
+	missing, err := repo.GetExpressionsWithoutMeaning(ctx, 10)
+	if err != nil {
+		t.Fatalf("GetExpressionsWithoutMeaning: %v", err)
+	}
+	if len(missing) != 1 || missing[0].Expression != "rollout" {
+		t.Errorf("unexpected expressions without meaning: %+v", missing)
+	}
+
+	totals, err := repo.GetStatsTotals(ctx)
+	if err != nil {
+		t.Fatalf("GetStatsTotals: %v", err)
+	}
+	if *totals != (StatsTotals{Expressions: 3, Words: 2, Phrases: 1, Occurrences: 4, MissingMeaning: 1}) {
+		t.Errorf("unexpected totals: %+v", totals)
+	}
+}
+
+// 夏時間の切り替えをまたいでも、各日時はその時点の時差でローカル日付に振り分けられる
+func TestCountByPeriodAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data is not available: %v", err)
+	}
+	local := time.Local
+	time.Local = loc
+	t.Cleanup(func() { time.Local = local })
+
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	expr := &models.Expression{Expression: "deprecate", Type: "word", Meaning: "非推奨にする", Priority: 5}
+	if err := repo.SaveExpression(ctx, expr); err != nil {
+		t.Fatalf("SaveExpression: %v", err)
+	}
+	// 冬時間（UTC-5）では01-09 23:30、夏時間（UTC-4）では07-09 23:30のローカル時刻
+	for _, at := range []string{"2026-01-10 04:30:00", "2026-07-10 03:30:00"} {
+		occ := &models.ExpressionOccurrence{ExpressionID: expr.ID, Context: "..."}
+		if err := repo.AddOccurrence(ctx, occ); err != nil {
+			t.Fatalf("AddOccurrence: %v", err)
+		}
+		if _, err := repo.db.Exec(`UPDATE expression_occurrences SET occurred_at = ? WHERE id = ?`, at, occ.ID); err != nil {
+			t.Fatalf("failed to set occurred_at: %v", err)
+		}
+	}
+
+	counts, err := repo.CountOccurrences(ctx, PeriodRange{
+		Interval: IntervalDay,
+		Since:    time.Date(2026, 1, 1, 0, 0, 0, 0, loc),
+		Until:    time.Date(2026, 7, 31, 0, 0, 0, 0, loc),
+	})
+	if err != nil {
+		t.Fatalf("CountOccurrences: %v", err)
+	}
+	for _, c := range counts {
+		day := c.Start.Format("2006-01-02")
+		expected := 0
+		if day == "2026-01-09" || day == "2026-07-09" {
+			expected = 1
+		}
+		if c.Count != expected {
+			t.Errorf("%s: count = %d, expected %d", day, c.Count, expected)
+		}
+	}
+}
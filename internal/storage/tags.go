@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+// ListTags はタグごとの表現数を名前順に取得（ルールだけで使われているタグは0件）
+func (r *SQLiteRepository) ListTags(ctx context.Context) ([]NameCount, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT t.name, COUNT(et.expression_id)
+		FROM tags t
+		LEFT JOIN expression_tags et ON et.tag = t.name
+		GROUP BY t.name
+		ORDER BY t.name ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []NameCount
+	for rows.Next() {
+		var c NameCount
+		if err := rows.Scan(&c.Name, &c.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, c)
+	}
+
+	return tags, rows.Err()
+}
+
+// AddTags は表現にタグをつける（sourceは models.TagSourceManual など。既についているタグはそのまま）
+func (r *SQLiteRepository) AddTags(ctx context.Context, expressionID int, source string, tags ...string) error {
+	for _, tag := range tags {
+		if err := models.ValidateTag(tag); err != nil {
+			return err
+		}
+	}
+
+	tx, err := r.begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := addTags(ctx, tx, expressionID, source, tags); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// addTags はタグを登録して表現につける
+func addTags(ctx context.Context, q querier, expressionID int, source string, tags []string) error {
+	for _, tag := range tags {
+		if _, err := q.ExecContext(ctx, `INSERT OR IGNORE INTO tags (name) VALUES (?)`, tag); err != nil {
+			return fmt.Errorf("failed to add tag: %w", err)
+		}
+		_, err := q.ExecContext(ctx, `
+			INSERT OR IGNORE INTO expression_tags (expression_id, tag, source)
+			VALUES (?, ?, ?)
+		`, expressionID, tag, source)
+		if err != nil {
+			return fmt.Errorf("failed to tag expression: %w", err)
+		}
+	}
+	return nil
+}
+
+// RemoveTags は表現からタグを外す（どの表現・ルールにも使われなくなったタグは削除）
+// 戻り値は実際に外したタグの数
+func (r *SQLiteRepository) RemoveTags(ctx context.Context, expressionID int, tags ...string) (int, error) {
+	tx, err := r.begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	removed := 0
+	for _, tag := range tags {
+		result, err := tx.ExecContext(ctx, `DELETE FROM expression_tags WHERE expression_id = ? AND tag = ?`, expressionID, tag)
+		if err != nil {
+			return 0, fmt.Errorf("failed to remove tag: %w", err)
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get affected rows: %w", err)
+		}
+		removed += int(n)
+	}
+	if err := deleteUnusedTags(ctx, tx); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit tag removal: %w", err)
+	}
+	return removed, nil
+}
+
+// deleteUnusedTags はどの表現・ルールにも使われていないタグを削除
+func deleteUnusedTags(ctx context.Context, q querier) error {
+	_, err := q.ExecContext(ctx, `
+		DELETE FROM tags
+		WHERE name NOT IN (SELECT tag FROM expression_tags)
+		  AND name NOT IN (SELECT tag FROM tag_rules)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to delete unused tags: %w", err)
+	}
+	return nil
+}
+
+// ListTagRules は会議名から自動でタグをつけるルールを取得
+func (r *SQLiteRepository) ListTagRules(ctx context.Context) ([]models.TagRule, error) {
+	rows, err := r.q.QueryContext(ctx, `SELECT tag, pattern FROM tag_rules ORDER BY tag ASC, pattern ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tag rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []models.TagRule
+	for rows.Next() {
+		var rule models.TagRule
+		if err := rows.Scan(&rule.Tag, &rule.Pattern); err != nil {
+			return nil, fmt.Errorf("failed to scan tag rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+// SaveTagRule はタグのルールを追加（同じルールが既にあれば何もしない）
+func (r *SQLiteRepository) SaveTagRule(ctx context.Context, rule models.TagRule) error {
+	if err := models.ValidateTag(rule.Tag); err != nil {
+		return err
+	}
+	if err := models.ValidateTagPattern(rule.Pattern); err != nil {
+		return err
+	}
+
+	tx, err := r.begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO tags (name) VALUES (?)`, rule.Tag); err != nil {
+		return fmt.Errorf("failed to add tag: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO tag_rules (tag, pattern) VALUES (?, ?)`, rule.Tag, rule.Pattern); err != nil {
+		return fmt.Errorf("failed to save tag rule: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// DeleteTagRule はタグのルールを削除（既についたタグは外さない）
+func (r *SQLiteRepository) DeleteTagRule(ctx context.Context, rule models.TagRule) error {
+	tx, err := r.begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM tag_rules WHERE tag = ? AND pattern = ?`, rule.Tag, rule.Pattern)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag rule: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("tag rule not found: %s %q", rule.Tag, rule.Pattern)
+	}
+	if err := deleteUnusedTags(ctx, tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
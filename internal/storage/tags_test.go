@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+func TestTags(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	save := func(expression string) *models.Expression {
+		expr := &models.Expression{Expression: expression, Type: "phrase", Priority: 3}
+		if err := repo.SaveExpression(ctx, expr); err != nil {
+			t.Fatalf("SaveExpression: %v", err)
+		}
+		if err := repo.AddOccurrence(ctx, &models.ExpressionOccurrence{ExpressionID: expr.ID, Context: "Let's " + expression + "."}); err != nil {
+			t.Fatalf("AddOccurrence: %v", err)
+		}
+		return expr
+	}
+	circle := save("circle back")
+	touch := save("touch base")
+
+	if err := repo.AddTags(ctx, circle.ID, models.TagSourceManual, "project-x", "interview"); err != nil {
+		t.Fatalf("AddTags: %v", err)
+	}
+	// 既についているタグは重複しない
+	if err := repo.AddTags(ctx, touch.ID, models.TagSourceMeeting, "project-x", "project-x"); err != nil {
+		t.Fatalf("AddTags: %v", err)
+	}
+	if err := repo.AddTags(ctx, touch.ID, models.TagSourceManual, "Project X"); err == nil {
+		t.Error("AddTags accepted an unnormalized tag")
+	}
+
+	got, err := repo.GetExpression(ctx, "circle back")
+	if err != nil {
+		t.Fatalf("GetExpression: %v", err)
+	}
+	if !reflect.DeepEqual(got.Tags, []string{"interview", "project-x"}) {
+		t.Errorf("tags = %v", got.Tags)
+	}
+
+	tags, err := repo.ListTags(ctx)
+	if err != nil {
+		t.Fatalf("ListTags: %v", err)
+	}
+	if !reflect.DeepEqual(tags, []NameCount{{"interview", 1}, {"project-x", 2}}) {
+		t.Errorf("ListTags = %+v", tags)
+	}
+
+	// 複数のタグはすべてついた表現に絞り込む
+	results, err := repo.Search(ctx, SearchOptions{Query: "let's", Tags: []string{"project-x", "interview"}})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != circle.ID {
+		t.Errorf("search by tags: %+v", results)
+	}
+
+	// ルールで使われているタグは表現から外しても残る
+	if err := repo.SaveTagRule(ctx, models.TagRule{Tag: "interview", Pattern: "*interview*"}); err != nil {
+		t.Fatalf("SaveTagRule: %v", err)
+	}
+	if err := repo.SaveTagRule(ctx, models.TagRule{Tag: "hiring", Pattern: "[bad"}); err == nil {
+		t.Error("SaveTagRule accepted an invalid pattern")
+	}
+	removed, err := repo.RemoveTags(ctx, circle.ID, "interview", "project-x", "missing")
+	if err != nil {
+		t.Fatalf("RemoveTags: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("RemoveTags removed %d tags, expected 2", removed)
+	}
+	tags, err = repo.ListTags(ctx)
+	if err != nil {
+		t.Fatalf("ListTags: %v", err)
+	}
+	if !reflect.DeepEqual(tags, []NameCount{{"interview", 0}, {"project-x", 1}}) {
+		t.Errorf("ListTags after remove = %+v", tags)
+	}
+
+	// ルールを削除すると使われなくなったタグも消える
+	if err := repo.DeleteTagRule(ctx, models.TagRule{Tag: "interview", Pattern: "*interview*"}); err != nil {
+		t.Fatalf("DeleteTagRule: %v", err)
+	}
+	if err := repo.DeleteTagRule(ctx, models.TagRule{Tag: "interview", Pattern: "*interview*"}); err == nil {
+		t.Error("DeleteTagRule succeeded for a missing rule")
+	}
+	tags, err = repo.ListTags(ctx)
+	if err != nil {
+		t.Fatalf("ListTags: %v", err)
+	}
+	if !reflect.DeepEqual(tags, []NameCount{{"project-x", 1}}) {
+		t.Errorf("ListTags after rule deletion = %+v", tags)
+	}
+
+	// 表現を削除するとタグも外れる
+	if err := repo.DeleteExpression(ctx, touch.ID); err != nil {
+		t.Fatalf("DeleteExpression: %v", err)
+	}
+	tags, err = repo.ListTags(ctx)
+	if err != nil {
+		t.Fatalf("ListTags: %v", err)
+	}
+	if len(tags) != 0 {
+		t.Errorf("ListTags after delete = %+v", tags)
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	target := &models.Expression{Expression: "pull request", Type: "phrase", Priority: 4}
+	source := &models.Expression{Expression: "pull requests", Type: "phrase", Priority: 3}
+	for _, expr := range []*models.Expression{target, source} {
+		if err := repo.SaveExpression(ctx, expr); err != nil {
+			t.Fatalf("SaveExpression: %v", err)
+		}
+	}
+	if err := repo.AddTags(ctx, target.ID, models.TagSourceManual, "code-review"); err != nil {
+		t.Fatalf("AddTags: %v", err)
+	}
+	if err := repo.AddTags(ctx, source.ID, models.TagSourceMeeting, "project-x"); err != nil {
+		t.Fatalf("AddTags: %v", err)
+	}
+
+	merged, err := repo.MergeExpressions(ctx, source.ID, target.ID)
+	if err != nil {
+		t.Fatalf("MergeExpressions: %v", err)
+	}
+	if !reflect.DeepEqual(merged.Tags, []string{"code-review", "project-x"}) {
+		t.Errorf("merged tags: %v", merged.Tags)
+	}
+
+	restored, _, err := repo.SplitExpression(ctx, "pull requests")
+	if err != nil {
+		t.Fatalf("SplitExpression: %v", err)
+	}
+	if !reflect.DeepEqual(restored.Tags, []string{"project-x"}) {
+		t.Errorf("restored tags: %v", restored.Tags)
+	}
+}
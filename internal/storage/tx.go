@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// txQuerier はトランザクション内で使うクエリ発行先（*sql.Txを満たす）
+type txQuerier interface {
+	querier
+	Commit() error
+	Rollback() error
+}
+
+// nestedTx は外側のトランザクションに参加する内側のトランザクション
+// コミット・ロールバックは外側のWithTxに任せる
+type nestedTx struct {
+	*sql.Tx
+}
+
+func (nestedTx) Commit() error   { return nil }
+func (nestedTx) Rollback() error { return nil }
+
+// begin はトランザクションを開始（WithTx内なら外側のトランザクションに参加）
+func (r *SQLiteRepository) begin(ctx context.Context) (txQuerier, error) {
+	if r.tx != nil {
+		return nestedTx{r.tx}, nil
+	}
+	return r.db.BeginTx(ctx, nil)
+}
+
+// WithTx はfnに渡したリポジトリの操作を1つのトランザクションで実行する
+// fnがエラーを返した場合はすべての変更をロールバックする（WithTx内で呼んだ場合は外側に参加）
+func (r *SQLiteRepository) WithTx(ctx context.Context, fn func(repo Repository) error) error {
+	if r.tx != nil {
+		return fn(r)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(&SQLiteRepository{db: r.db, q: tx, tx: tx, fts: r.fts}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+func TestWithTx(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	// エラーを返すとすべてロールバックされる（内側のトランザクションも含む）
+	errAbort := errors.New("abort")
+	err := repo.WithTx(ctx, func(tx Repository) error {
+		expr := &models.Expression{Expression: "deprecate", Type: "word", Priority: 3, Category: "engineering"}
+		if err := tx.SaveExpression(ctx, expr); err != nil {
+			return err
+		}
+		if err := tx.AddOccurrence(ctx, &models.ExpressionOccurrence{ExpressionID: expr.ID, Context: "We deprecate it."}); err != nil {
+			return err
+		}
+		if err := tx.DeleteExpression(ctx, expr.ID); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("WithTx error = %v, want %v", err, errAbort)
+	}
+	if exists, err := repo.ExpressionExists(ctx, "deprecate"); err != nil || exists {
+		t.Fatalf("rolled back expression should not exist: exists=%v err=%v", exists, err)
+	}
+
+	// 成功するとコミットされ、トランザクション内の変更が読める
+	err = repo.WithTx(ctx, func(tx Repository) error {
+		expr := &models.Expression{Expression: "touch base", Type: "phrase", Priority: 3, Category: "business"}
+		if err := tx.SaveExpression(ctx, expr); err != nil {
+			return err
+		}
+		got, err := tx.GetExpression(ctx, "touch base")
+		if err != nil || got == nil {
+			t.Errorf("expression should be visible inside the transaction: %v", err)
+		}
+		return tx.Close() // トランザクション内のCloseはDBを閉じない
+	})
+	if err != nil {
+		t.Fatalf("WithTx: %v", err)
+	}
+	if exists, err := repo.ExpressionExists(ctx, "touch base"); err != nil || !exists {
+		t.Fatalf("committed expression should exist: exists=%v err=%v", exists, err)
+	}
+}
@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+// 関連語の種類（expression_related_words.relation）
+const (
+	relationCollocation = "collocation"
+	relationSynonym     = "synonym"
+	relationAntonym     = "antonym"
+)
+
+// usageTables は表現の使い方を保存するテーブル
+var usageTables = []string{"expression_usage", "expression_examples", "expression_related_words"}
+
+// GetUsage は表現の使い方を取得（まだ生成していなければnil）
+func (r *SQLiteRepository) GetUsage(ctx context.Context, expressionID int) (*models.Usage, error) {
+	usage := &models.Usage{ExpressionID: expressionID}
+	err := r.q.QueryRowContext(ctx, `
+		SELECT register, note, generated_at FROM expression_usage WHERE expression_id = ?
+	`, expressionID).Scan(&usage.Register, &usage.Note, &usage.GeneratedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage: %w", err)
+	}
+
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT sentence, translation FROM expression_examples
+		WHERE expression_id = ?
+		ORDER BY position ASC
+	`, expressionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query examples: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var e models.Example
+		if err := rows.Scan(&e.Sentence, &e.Translation); err != nil {
+			return nil, fmt.Errorf("failed to scan example: %w", err)
+		}
+		usage.Examples = append(usage.Examples, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query examples: %w", err)
+	}
+
+	words, err := r.q.QueryContext(ctx, `
+		SELECT relation, word FROM expression_related_words
+		WHERE expression_id = ?
+		ORDER BY relation, position ASC
+	`, expressionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query related words: %w", err)
+	}
+	defer words.Close()
+	for words.Next() {
+		var relation, word string
+		if err := words.Scan(&relation, &word); err != nil {
+			return nil, fmt.Errorf("failed to scan related word: %w", err)
+		}
+		switch relation {
+		case relationCollocation:
+			usage.Collocations = append(usage.Collocations, word)
+		case relationSynonym:
+			usage.Synonyms = append(usage.Synonyms, word)
+		case relationAntonym:
+			usage.Antonyms = append(usage.Antonyms, word)
+		}
+	}
+
+	return usage, words.Err()
+}
+
+// SaveUsage は表現の使い方を保存（生成済みの使い方は置き換える）
+func (r *SQLiteRepository) SaveUsage(ctx context.Context, usage *models.Usage) error {
+	tx, err := r.begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := deleteUsage(ctx, tx, usage.ExpressionID); err != nil {
+		return err
+	}
+
+	usage.Register = models.NormalizeRegister(usage.Register)
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO expression_usage (expression_id, register, note) VALUES (?, ?, ?)
+	`, usage.ExpressionID, usage.Register, usage.Note)
+	if err != nil {
+		return fmt.Errorf("failed to save usage: %w", err)
+	}
+	for i, e := range usage.Examples {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO expression_examples (expression_id, position, sentence, translation) VALUES (?, ?, ?, ?)
+		`, usage.ExpressionID, i, e.Sentence, e.Translation)
+		if err != nil {
+			return fmt.Errorf("failed to save example: %w", err)
+		}
+	}
+	related := []struct {
+		relation string
+		words    []string
+	}{
+		{relationCollocation, usage.Collocations},
+		{relationSynonym, usage.Synonyms},
+		{relationAntonym, usage.Antonyms},
+	}
+	for _, rel := range related {
+		for i, word := range rel.words {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO expression_related_words (expression_id, relation, position, word) VALUES (?, ?, ?, ?)
+			`, usage.ExpressionID, rel.relation, i, word)
+			if err != nil {
+				return fmt.Errorf("failed to save related word: %w", err)
+			}
+		}
+	}
+	if err := tx.QueryRowContext(ctx, `SELECT generated_at FROM expression_usage WHERE expression_id = ?`, usage.ExpressionID).Scan(&usage.GeneratedAt); err != nil {
+		return fmt.Errorf("failed to get usage: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// deleteUsage は表現の使い方を削除
+func deleteUsage(ctx context.Context, q querier, expressionID int) error {
+	for _, table := range usageTables {
+		if _, err := q.ExecContext(ctx, `DELETE FROM `+table+` WHERE expression_id = ?`, expressionID); err != nil {
+			return fmt.Errorf("failed to delete from %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// mergeUsage は統合先に使い方がなければ統合元の使い方を引き継ぐ（あれば統合元の使い方は破棄）
+func mergeUsage(ctx context.Context, q querier, sourceID, targetID int) error {
+	var exists bool
+	if err := q.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM expression_usage WHERE expression_id = ?)`, targetID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check usage: %w", err)
+	}
+	if exists {
+		return deleteUsage(ctx, q, sourceID)
+	}
+	for _, table := range usageTables {
+		if _, err := q.ExecContext(ctx, `UPDATE `+table+` SET expression_id = ? WHERE expression_id = ?`, targetID, sourceID); err != nil {
+			return fmt.Errorf("failed to move %s: %w", table, err)
+		}
+	}
+	return nil
+}
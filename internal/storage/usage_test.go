@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+func TestUsage(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	expr := &models.Expression{Expression: "circle back", Type: "phrase", Priority: 3}
+	if err := repo.SaveExpression(ctx, expr); err != nil {
+		t.Fatalf("SaveExpression: %v", err)
+	}
+	if usage, err := repo.GetUsage(ctx, expr.ID); err != nil || usage != nil {
+		t.Fatalf("GetUsage before enrichment = %+v, %v", usage, err)
+	}
+
+	usage := &models.Usage{
+		ExpressionID: expr.ID,
+		Examples: []models.Example{
+			{Sentence: "Let's circle back on this tomorrow.", Translation: "明日この件に戻りましょう。"},
+			{Sentence: "I'll circle back with the client."},
+		},
+		Register:     "Informal",
+		Note:         "社内の会話で使う",
+		Collocations: []string{"circle back on", "circle back with"},
+		Synonyms:     []string{"follow up", "revisit"},
+	}
+	if err := repo.SaveUsage(ctx, usage); err != nil {
+		t.Fatalf("SaveUsage: %v", err)
+	}
+	got, err := repo.GetUsage(ctx, expr.ID)
+	if err != nil {
+		t.Fatalf("GetUsage: %v", err)
+	}
+	if got.GeneratedAt.IsZero() {
+		t.Error("generated_at is not set")
+	}
+	got.GeneratedAt = usage.GeneratedAt
+	usage.Register = models.RegisterInformal
+	if !reflect.DeepEqual(got, usage) {
+		t.Errorf("GetUsage = %+v, want %+v", got, usage)
+	}
+
+	// 保存し直すと置き換える
+	if err := repo.SaveUsage(ctx, &models.Usage{ExpressionID: expr.ID, Antonyms: []string{"drop"}}); err != nil {
+		t.Fatalf("SaveUsage: %v", err)
+	}
+	got, err = repo.GetUsage(ctx, expr.ID)
+	if err != nil {
+		t.Fatalf("GetUsage: %v", err)
+	}
+	if got.Examples != nil || got.Synonyms != nil || !reflect.DeepEqual(got.Antonyms, []string{"drop"}) || got.Note != "" {
+		t.Errorf("usage was not replaced: %+v", got)
+	}
+
+	// 表現を削除すると使い方も消える
+	if err := repo.DeleteExpression(ctx, expr.ID); err != nil {
+		t.Fatalf("DeleteExpression: %v", err)
+	}
+	for _, table := range usageTables {
+		var n int
+		if err := repo.db.QueryRow(`SELECT COUNT(*) FROM ` + table).Scan(&n); err != nil {
+			t.Fatalf("count %s: %v", table, err)
+		}
+		if n != 0 {
+			t.Errorf("%s has %d rows after delete", table, n)
+		}
+	}
+}
+
+func TestMergeUsage(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	save := func(expression string) *models.Expression {
+		expr := &models.Expression{Expression: expression, Type: "phrase", Priority: 3}
+		if err := repo.SaveExpression(ctx, expr); err != nil {
+			t.Fatalf("SaveExpression: %v", err)
+		}
+		return expr
+	}
+	target, source, other := save("pull request"), save("pull requests"), save("PR")
+	if err := repo.SaveUsage(ctx, &models.Usage{ExpressionID: source.ID, Examples: []models.Example{{Sentence: "Open two pull requests."}}}); err != nil {
+		t.Fatalf("SaveUsage: %v", err)
+	}
+	if err := repo.SaveUsage(ctx, &models.Usage{ExpressionID: other.ID, Synonyms: []string{"pull request"}}); err != nil {
+		t.Fatalf("SaveUsage: %v", err)
+	}
+
+	// 統合先に使い方がなければ引き継ぐ
+	if _, err := repo.MergeExpressions(ctx, source.ID, target.ID); err != nil {
+		t.Fatalf("MergeExpressions: %v", err)
+	}
+	usage, err := repo.GetUsage(ctx, target.ID)
+	if err != nil || usage == nil {
+		t.Fatalf("GetUsage: %+v, %v", usage, err)
+	}
+	if len(usage.Examples) != 1 || usage.Examples[0].Sentence != "Open two pull requests." {
+		t.Errorf("merged usage: %+v", usage)
+	}
+
+	// あれば統合先の使い方を残す
+	if _, err := repo.MergeExpressions(ctx, other.ID, target.ID); err != nil {
+		t.Fatalf("MergeExpressions: %v", err)
+	}
+	usage, err = repo.GetUsage(ctx, target.ID)
+	if err != nil {
+		t.Fatalf("GetUsage: %v", err)
+	}
+	if len(usage.Examples) != 1 || usage.Synonyms != nil {
+		t.Errorf("target usage was overwritten: %+v", usage)
+	}
+	if usage, err := repo.GetUsage(ctx, other.ID); err != nil || usage != nil {
+		t.Errorf("source usage remains: %+v, %v", usage, err)
+	}
+}
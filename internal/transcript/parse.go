@@ -0,0 +1,107 @@
+package transcript
+
+import (
+	"bufio"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Extensions は読み込めるtranscriptファイルの拡張子
+var Extensions = []string{".txt", ".vtt", ".srt"}
+
+// cueTimingPattern はWebVTT/SRTのタイミング行（"00:00:01.000 --> 00:00:04.000"）
+var cueTimingPattern = regexp.MustCompile(`^\s*((?:\d{1,2}:)?\d{1,2}:\d{2}[.,]\d{1,3})\s*-->\s*\S+`)
+
+// vttTagPattern はWebVTTのタグ（<v Alice>, </c> など）
+var vttTagPattern = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+
+// IsSupported はファイルが読み込めるtranscriptか判定
+func IsSupported(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range Extensions {
+		if e == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse はファイルの内容をプレーンテキストのtranscriptに変換
+// .vtt/.srt は字幕ごとに開始時刻の行（"00:01:02"）と本文に変換するため、FindTimestampでタイムスタンプを検出できる
+func Parse(path string, content []byte) (string, error) {
+	text := strings.TrimPrefix(string(content), "\ufeff")
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".vtt", ".srt":
+		return parseCues(text)
+	case ".txt", "":
+		return text, nil
+	default:
+		return "", fmt.Errorf("unsupported transcript format: %s", filepath.Ext(path))
+	}
+}
+
+// parseCues はWebVTT/SRTの字幕を "開始時刻\n本文" の並びに変換
+// 話者タグ（<v Alice>）は "Alice: " に変換し、NOTE/STYLEブロックと字幕番号は除く
+func parseCues(text string) (string, error) {
+	var b strings.Builder
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	inCue := false
+	skipBlock := false
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "" {
+			inCue = false
+			skipBlock = false
+			continue
+		}
+		if skipBlock {
+			continue
+		}
+
+		if m := cueTimingPattern.FindStringSubmatch(line); m != nil {
+			d, err := ParseTimestamp(m[1])
+			if err != nil {
+				return "", err
+			}
+			if b.Len() > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString(FormatTimestamp(d))
+			b.WriteString("\n")
+			inCue = true
+			continue
+		}
+
+		if !inCue {
+			// ヘッダー・NOTE・STYLE・REGION・字幕番号・キューIDは本文ではない
+			if strings.HasPrefix(trimmed, "NOTE") || trimmed == "STYLE" || trimmed == "REGION" {
+				skipBlock = true
+			}
+			continue
+		}
+
+		b.WriteString(cueText(trimmed))
+		b.WriteString("\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read subtitles: %w", err)
+	}
+
+	return b.String(), nil
+}
+
+// voiceTagPattern はWebVTTの話者タグ（<v Alice> / <v.loud Alice>）
+var voiceTagPattern = regexp.MustCompile(`<v(?:\.[^ >]*)? ([^>]+)>`)
+
+// cueText は字幕の本文から話者タグを "話者: " に変換し、その他のタグを除く
+func cueText(line string) string {
+	line = voiceTagPattern.ReplaceAllString(line, "$1: ")
+	line = vttTagPattern.ReplaceAllString(line, "")
+	return strings.Join(strings.Fields(line), " ")
+}
@@ -0,0 +1,45 @@
+package transcript
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseVTT(t *testing.T) {
+	vtt := "\ufeffWEBVTT\n\nNOTE generated by Meet\nsecond line\n\n1\n00:00:01.000 --> 00:00:04.000\n<v Alice>Good morning everyone.</v>\n\n00:01:30.500 --> 00:01:35.000\n<v Bob>We need to <i>deprecate</i> the old endpoint.</v>\n"
+
+	text, err := Parse("meeting.vtt", []byte(vtt))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	expected := "00:00:01\nAlice: Good morning everyone.\n\n00:01:30\nBob: We need to deprecate the old endpoint.\n"
+	if text != expected {
+		t.Errorf("Parse = %q, expected %q", text, expected)
+	}
+
+	if d, ok := FindTimestamp(text, "We need to deprecate the old endpoint"); !ok || d != 90*time.Second {
+		t.Errorf("FindTimestamp = (%v, %v), expected 1m30s", d, ok)
+	}
+}
+
+func TestParseSRT(t *testing.T) {
+	srt := "1\r\n00:00:01,000 --> 00:00:04,000\r\nLet's circle back\r\non the pricing.\r\n\r\n2\r\n00:02:03,000 --> 00:02:05,000\r\nSounds good.\r\n"
+
+	text, err := Parse("meeting.SRT", []byte(srt))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	expected := "00:00:01\nLet's circle back\non the pricing.\n\n00:02:03\nSounds good.\n"
+	if text != expected {
+		t.Errorf("Parse = %q, expected %q", text, expected)
+	}
+}
+
+func TestParseUnsupported(t *testing.T) {
+	if _, err := Parse("notes.docx", []byte("x")); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+	if !IsSupported("a.VTT") || IsSupported("a.md") {
+		t.Error("IsSupported returned unexpected result")
+	}
+}
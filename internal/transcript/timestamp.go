@@ -0,0 +1,96 @@
+// Package transcript はtranscriptファイルの解析（タイムスタンプの検出など）を行う
+package transcript
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timestampPattern は行頭のタイムスタンプ（"00:12:34", "[12:34]", "00:12:34.567 --> ..." など）
+var timestampPattern = regexp.MustCompile(`(?m)^[ \t]*\[?((?:\d{1,2}:)?\d{1,2}:\d{2})(?:[.,]\d{1,3})?\]?`)
+
+// fallbackPrefixLength は文脈が完全一致しない場合に検索に使う先頭の文字数
+const fallbackPrefixLength = 30
+
+// FindTimestamp はtranscript中でsentenceが出現する位置の直前のタイムスタンプを返す
+// 文脈の前にタイムスタンプがなければ文脈内の最初のタイムスタンプを使う
+func FindTimestamp(transcript, sentence string) (time.Duration, bool) {
+	sentence = strings.TrimSpace(sentence)
+	if sentence == "" {
+		return 0, false
+	}
+
+	pos := strings.Index(transcript, sentence)
+	if pos < 0 {
+		// LLMが抽出した文脈は表記が揺れることがあるので、先頭部分を大文字小文字を無視して検索
+		prefix := sentence
+		if runes := []rune(prefix); len(runes) > fallbackPrefixLength {
+			prefix = string(runes[:fallbackPrefixLength])
+		}
+		pos = strings.Index(strings.ToLower(transcript), strings.ToLower(prefix))
+		if pos < 0 {
+			return 0, false
+		}
+	}
+	end := pos + len(sentence)
+
+	var found string
+	for _, m := range timestampPattern.FindAllStringSubmatchIndex(transcript, -1) {
+		start := m[0]
+		if start > pos {
+			// 文脈の前に見つからなければ文脈内の最初のもの
+			if found == "" && start < end {
+				found = transcript[m[2]:m[3]]
+			}
+			break
+		}
+		found = transcript[m[2]:m[3]]
+	}
+	if found == "" {
+		return 0, false
+	}
+
+	d, err := ParseTimestamp(found)
+	if err != nil {
+		return 0, false
+	}
+	return d, true
+}
+
+// ParseTimestamp は "hh:mm:ss" / "mm:ss"（小数秒は "." または "," 区切り）を時間に変換
+func ParseTimestamp(s string) (time.Duration, error) {
+	var fraction time.Duration
+	if i := strings.IndexAny(s, ".,"); i >= 0 {
+		digits := s[i+1:]
+		ms, err := strconv.Atoi((digits + "000")[:3])
+		if err != nil || len(digits) > 3 {
+			return 0, fmt.Errorf("invalid timestamp: %s", s)
+		}
+		fraction = time.Duration(ms) * time.Millisecond
+		s = s[:i]
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid timestamp: %s", s)
+	}
+
+	var total time.Duration
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid timestamp: %s", s)
+		}
+		total = total*60 + time.Duration(n)*time.Second
+	}
+	return total + fraction, nil
+}
+
+// FormatTimestamp は時間を "hh:mm:ss" 形式に変換
+func FormatTimestamp(d time.Duration) string {
+	seconds := int(d / time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds%3600/60, seconds%60)
+}
@@ -0,0 +1,64 @@
+package transcript
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindTimestamp(t *testing.T) {
+	text := `Weekly Sync
+00:00:05
+Alice: Good morning everyone.
+00:01:30
+Bob: We need to deprecate the old endpoint.
+[00:02:10] Alice: Let's circle back on the pricing.
+`
+
+	tests := []struct {
+		name     string
+		sentence string
+		expected time.Duration
+		ok       bool
+	}{
+		{"直前の行", "We need to deprecate the old endpoint", 90 * time.Second, true},
+		{"同じ行", "Let's circle back on the pricing", 130 * time.Second, true},
+		{"大文字小文字の揺れ", "good morning everyone", 5 * time.Second, true},
+		{"見つからない", "This sentence is not in the transcript", 0, false},
+		{"タイムスタンプより前", "Weekly Sync", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := FindTimestamp(text, tt.sentence)
+			if got != tt.expected || ok != tt.ok {
+				t.Errorf("FindTimestamp(%q) = (%v, %v), expected (%v, %v)", tt.sentence, got, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"01:02:03", time.Hour + 2*time.Minute + 3*time.Second, false},
+		{"12:34", 12*time.Minute + 34*time.Second, false},
+		{"00:00:01.500", 1500 * time.Millisecond, false},
+		{"00:00:01,25", 1250 * time.Millisecond, false},
+		{"abc", 0, true},
+		{"1:2:3:4", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseTimestamp(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.expected {
+			t.Errorf("ParseTimestamp(%q) = (%v, %v), expected (%v, wantErr=%v)", tt.input, got, err, tt.expected, tt.wantErr)
+		}
+	}
+
+	if got := FormatTimestamp(time.Hour + 2*time.Minute + 3*time.Second); got != "01:02:03" {
+		t.Errorf("FormatTimestamp = %q", got)
+	}
+}
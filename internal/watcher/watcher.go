@@ -0,0 +1,239 @@
+// Package watcher はフォルダを監視して新しいtranscriptを自動で処理する
+package watcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/service"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+	"github.com/mamyudapao/learn-by-transcript/internal/transcript"
+)
+
+const (
+	// DefaultInterval はフォルダを確認する間隔
+	DefaultInterval = 30 * time.Second
+	// DefaultSettle は書き込み中のファイルを避けるため、最終更新からこの時間が経つまで処理しない
+	DefaultSettle = 5 * time.Second
+	// DefaultArchiveDirName は処理済みファイルの移動先（監視フォルダ内）
+	DefaultArchiveDirName = "processed"
+)
+
+// Processor はtranscriptを処理する（service.TranscriptProcessor）
+type Processor interface {
+	Process(ctx context.Context, meeting, transcript string) (*service.ProcessResult, error)
+}
+
+// Options はWatcherの設定（ゼロ値の項目はデフォルト値を使用）
+type Options struct {
+	ArchiveDir string        // 処理済みファイルの移動先（デフォルト: <dir>/processed）
+	Interval   time.Duration // 確認間隔
+	Settle     time.Duration // 最終更新から処理を始めるまでの待ち時間（負数なら待たない）
+	Logger     *slog.Logger  // 処理したファイルはInfo、失敗はError・Warnで記録（デフォルトは記録しない）
+}
+
+// Watcher はフォルダをポーリングし、新しい.txt/.vtt/.srtを1回ずつ処理してアーカイブに移動する
+// 処理済みかどうかは内容のハッシュで判定するため、同じ内容のファイルを置き直しても再処理しない
+type Watcher struct {
+	dir        string
+	archiveDir string
+	interval   time.Duration
+	settle     time.Duration
+	repo       storage.Repository
+	processor  Processor
+	logger     *slog.Logger
+
+	// failed はこのセッションで処理に失敗した内容のハッシュ（内容が変わるまで再試行しない）
+	failed map[string]bool
+}
+
+// ScanResult は1回の確認の結果
+type ScanResult struct {
+	Processed  int // 処理した件数
+	Duplicates int // 処理済みの内容だったためアーカイブのみ行った件数
+	Failed     int // 処理に失敗した件数
+}
+
+// New は新しいWatcherを作成
+func New(dir string, repo storage.Repository, processor Processor, opts Options) *Watcher {
+	w := &Watcher{
+		dir:        dir,
+		archiveDir: opts.ArchiveDir,
+		interval:   opts.Interval,
+		settle:     opts.Settle,
+		repo:       repo,
+		processor:  processor,
+		logger:     opts.Logger,
+		failed:     make(map[string]bool),
+	}
+	if w.archiveDir == "" {
+		w.archiveDir = filepath.Join(dir, DefaultArchiveDirName)
+	}
+	if w.interval <= 0 {
+		w.interval = DefaultInterval
+	}
+	if w.settle == 0 {
+		w.settle = DefaultSettle
+	}
+	if w.logger == nil {
+		w.logger = slog.New(slog.DiscardHandler)
+	}
+	return w
+}
+
+// Run はctxがキャンセルされるまでフォルダを確認し続ける（個々のファイルの失敗では止まらない）
+func (w *Watcher) Run(ctx context.Context) error {
+	if err := os.MkdirAll(w.archiveDir, 0755); err != nil {
+		return fmt.Errorf("failed to create archive dir: %w", err)
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.Scan(ctx); err != nil {
+			w.logger.Error("scan failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Scan はフォルダを1回確認し、新しいtranscriptを処理する
+func (w *Watcher) Scan(ctx context.Context) (*ScanResult, error) {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dir: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	result := &ScanResult{}
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return result, nil
+		}
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") || !transcript.IsSupported(entry.Name()) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if w.settle > 0 && time.Since(info.ModTime()) < w.settle {
+			continue // 書き込み中の可能性がある
+		}
+
+		w.handleFile(ctx, filepath.Join(w.dir, entry.Name()), result)
+	}
+	return result, nil
+}
+
+// handleFile は1ファイルを処理（失敗はログに記録して続行）
+func (w *Watcher) handleFile(ctx context.Context, path string, result *ScanResult) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		w.logger.Error("failed to read transcript", "path", path, "error", err)
+		result.Failed++
+		return
+	}
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+	if w.failed[hash] {
+		return
+	}
+
+	processed, err := w.repo.IsFileProcessed(ctx, hash)
+	if err != nil {
+		w.logger.Error("failed to check processed files", "path", path, "error", err)
+		result.Failed++
+		return
+	}
+	if processed {
+		w.logger.Info("skipped transcript: same content was already processed", "file", filepath.Base(path))
+		result.Duplicates++
+		w.archive(path)
+		return
+	}
+
+	meeting := service.MeetingName(path)
+	text, err := transcript.Parse(path, content)
+	if err == nil {
+		var r *service.ProcessResult
+		r, err = w.processor.Process(ctx, meeting, text)
+		if err == nil {
+			w.logger.Info("processed transcript", "file", filepath.Base(path), "expressions", r.TotalExpressions, "new", r.NewExpressions)
+		}
+	}
+	if err != nil {
+		w.logger.Error("failed to process transcript", "path", path, "error", err)
+		w.failed[hash] = true
+		result.Failed++
+		return
+	}
+
+	// アーカイブに失敗しても再処理しないよう先に記録
+	if err := w.repo.MarkFileProcessed(ctx, hash, path, meeting); err != nil {
+		w.logger.Warn("failed to record processed file", "path", path, "error", err)
+	}
+	result.Processed++
+	w.archive(path)
+}
+
+// archive はファイルをアーカイブに移動（同名のファイルがあれば日時を付ける）
+func (w *Watcher) archive(path string) {
+	if err := os.MkdirAll(w.archiveDir, 0755); err != nil {
+		w.logger.Warn("failed to create archive dir", "dir", w.archiveDir, "error", err)
+		return
+	}
+
+	dest := filepath.Join(w.archiveDir, filepath.Base(path))
+	if _, err := os.Stat(dest); err == nil {
+		ext := filepath.Ext(path)
+		dest = filepath.Join(w.archiveDir, strings.TrimSuffix(filepath.Base(path), ext)+time.Now().Format("-20060102-150405")+ext)
+	}
+
+	if err := moveFile(path, dest); err != nil {
+		w.logger.Warn("failed to archive transcript", "path", path, "dest", dest, "error", err)
+	}
+}
+
+// moveFile はファイルを移動（別のファイルシステムならコピーして削除）
+func moveFile(src, dest string) error {
+	if err := os.Rename(src, dest); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dest)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
@@ -0,0 +1,107 @@
+package watcher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/service"
+	"github.com/mamyudapao/learn-by-transcript/internal/storage"
+)
+
+// fakeProcessor は受け取ったtranscriptを記録する（"fail" を含むものは失敗させる）
+type fakeProcessor struct {
+	calls []string
+}
+
+func (p *fakeProcessor) Process(ctx context.Context, meeting, transcript string) (*service.ProcessResult, error) {
+	p.calls = append(p.calls, meeting+": "+strings.TrimSpace(transcript))
+	if strings.Contains(transcript, "fail") {
+		return nil, errors.New("LLM unavailable")
+	}
+	return &service.ProcessResult{Meeting: meeting, TotalExpressions: 1}, nil
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestScan(t *testing.T) {
+	ctx := context.Background()
+	repo, err := storage.NewSQLiteRepository(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "standup.txt"), "We deprecate it.")
+	writeFile(t, filepath.Join(dir, "retro.srt"), "1\n00:00:01,000 --> 00:00:02,000\nLet's circle back.\n")
+	writeFile(t, filepath.Join(dir, "broken.txt"), "this will fail")
+	writeFile(t, filepath.Join(dir, "notes.md"), "ignored")
+
+	var logs bytes.Buffer
+	processor := &fakeProcessor{}
+	w := New(dir, repo, processor, Options{Settle: -1, Logger: slog.New(slog.NewTextHandler(&logs, nil))})
+
+	result, err := w.Scan(ctx)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if result.Processed != 2 || result.Failed != 1 {
+		t.Errorf("first scan = %+v, expected 2 processed and 1 failed", result)
+	}
+	if len(processor.calls) != 3 || processor.calls[1] != "retro: 00:00:01\nLet's circle back." {
+		t.Errorf("unexpected calls: %q", processor.calls)
+	}
+	for _, name := range []string{"standup.txt", "retro.srt"} {
+		if _, err := os.Stat(filepath.Join(dir, DefaultArchiveDirName, name)); err != nil {
+			t.Errorf("%s should be archived: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "broken.txt")); err != nil {
+		t.Error("failed file should stay in place")
+	}
+	if !strings.Contains(logs.String(), "failed to process") {
+		t.Errorf("failure should be logged: %s", logs.String())
+	}
+
+	// 失敗したファイルは内容が変わるまで再試行せず、同じ内容のファイルは処理せずにアーカイブ
+	writeFile(t, filepath.Join(dir, "standup-copy.txt"), "We deprecate it.")
+	result, err = w.Scan(ctx)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if result.Processed != 0 || result.Duplicates != 1 || result.Failed != 0 {
+		t.Errorf("second scan = %+v, expected 1 duplicate", result)
+	}
+	if len(processor.calls) != 3 {
+		t.Errorf("no new processing expected, got %q", processor.calls)
+	}
+	if _, err := os.Stat(filepath.Join(dir, DefaultArchiveDirName, "standup-copy.txt")); err != nil {
+		t.Errorf("duplicate should be archived: %v", err)
+	}
+
+	// 別のWatcher（再起動後）でも処理済みの内容は処理しない
+	writeFile(t, filepath.Join(dir, "standup.txt"), "We deprecate it.")
+	result, err = New(dir, repo, processor, Options{Settle: -1, Logger: slog.New(slog.NewTextHandler(&logs, nil))}).Scan(ctx)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if result.Duplicates != 1 || len(processor.calls) != 4 {
+		// broken.txt は新しいWatcherで1回だけ再試行される
+		t.Errorf("after restart = %+v, calls=%d", result, len(processor.calls))
+	}
+	entries, _ := os.ReadDir(filepath.Join(dir, DefaultArchiveDirName))
+	if len(entries) != 4 {
+		t.Errorf("expected 4 archived files (including renamed duplicate), got %d", len(entries))
+	}
+}
@@ -0,0 +1,2 @@
+-- 手動編集フラグ: 1の場合は優先度の自動更新・再エンリッチで上書きしない
+ALTER TABLE expressions ADD COLUMN manually_edited INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,26 @@
+-- 出現時の表記（統合・分割で出現履歴を元の表現に戻すために使用）
+ALTER TABLE expression_occurrences ADD COLUMN surface TEXT;
+
+-- 別名テーブル: 統合元の表記を統合先の表現に対応付ける
+CREATE TABLE IF NOT EXISTS expression_aliases (
+    alias TEXT PRIMARY KEY,
+    expression_id INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (expression_id) REFERENCES expressions(id) ON DELETE CASCADE
+);
+
+-- 統合履歴テーブル: 分割時に統合元の表現を復元するためのスナップショット
+CREATE TABLE IF NOT EXISTS expression_merges (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    source_expression TEXT NOT NULL,
+    target_id INTEGER NOT NULL,
+    type TEXT NOT NULL,
+    meaning TEXT,
+    priority INTEGER,
+    category TEXT,
+    manually_edited INTEGER NOT NULL DEFAULT 0,
+    merged_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_aliases_expression ON expression_aliases(expression_id);
+CREATE INDEX IF NOT EXISTS idx_merges_source ON expression_merges(source_expression);
@@ -0,0 +1,27 @@
+-- 復習スケジュールテーブル（表現ごとに1行、未復習の表現は行なし）
+CREATE TABLE IF NOT EXISTS review_cards (
+    expression_id INTEGER PRIMARY KEY,
+    ease REAL NOT NULL DEFAULT 2.5,
+    interval_days REAL NOT NULL DEFAULT 0,
+    due_at TIMESTAMP NOT NULL,
+    reps INTEGER NOT NULL DEFAULT 0,
+    lapses INTEGER NOT NULL DEFAULT 0,
+    stability REAL NOT NULL DEFAULT 0,
+    difficulty REAL NOT NULL DEFAULT 0,
+    last_reviewed_at TIMESTAMP,
+    FOREIGN KEY (expression_id) REFERENCES expressions(id) ON DELETE CASCADE
+);
+
+-- 復習履歴テーブル
+CREATE TABLE IF NOT EXISTS review_logs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    expression_id INTEGER NOT NULL,
+    grade INTEGER NOT NULL,
+    scheduler TEXT NOT NULL,
+    interval_days REAL NOT NULL,
+    reviewed_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (expression_id) REFERENCES expressions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_cards_due ON review_cards(due_at);
+CREATE INDEX IF NOT EXISTS idx_review_logs_expression ON review_logs(expression_id);
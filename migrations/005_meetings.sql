@@ -0,0 +1,4 @@
+-- 出現した会議名（transcriptのファイル名または --meeting で指定した名前）
+ALTER TABLE expression_occurrences ADD COLUMN meeting TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_occurrences_meeting ON expression_occurrences(meeting);
@@ -0,0 +1,9 @@
+-- Notionデータベースの行（ページ）と表現の対応（同期時の upsert に使用）
+CREATE TABLE IF NOT EXISTS notion_pages (
+    database_id TEXT NOT NULL,
+    expression_id INTEGER NOT NULL,
+    page_id TEXT NOT NULL,
+    synced_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (database_id, expression_id),
+    FOREIGN KEY (expression_id) REFERENCES expressions(id) ON DELETE CASCADE
+);
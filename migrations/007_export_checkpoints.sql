@@ -0,0 +1,6 @@
+-- 出力先ごとの最終エクスポート日時（export --since-last で使用）
+-- destination は "csv:/abs/path/expressions.csv" や "notion:<database_id>" のような出力先の識別子
+CREATE TABLE IF NOT EXISTS export_checkpoints (
+    destination TEXT PRIMARY KEY,
+    exported_at DATETIME NOT NULL
+);
@@ -0,0 +1,7 @@
+-- 処理済みのtranscriptファイル（watchで同じ内容を二重に処理しないため、内容のハッシュで記録）
+CREATE TABLE IF NOT EXISTS processed_files (
+    content_hash TEXT PRIMARY KEY,
+    path TEXT NOT NULL,
+    meeting TEXT,
+    processed_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
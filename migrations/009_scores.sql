@@ -0,0 +1,12 @@
+-- 基準の優先度: LLMが判定した優先度（手動で優先度を編集した場合はその値）
+-- priorityは基準の優先度に出現頻度・会議の多様さ・復習成績を加味したスコアから算出する
+ALTER TABLE expressions ADD COLUMN base_priority INTEGER;
+-- 優先度の算出に使ったスコア（rescoreで履歴から再計算できる）
+ALTER TABLE expressions ADD COLUMN score REAL;
+
+-- 以前のpriorityは出現回数による加算を含むことがあるが、加算前の値は残っていないためそのまま基準とする
+UPDATE expressions SET base_priority = priority, score = priority;
+
+-- 分割時に基準の優先度も復元できるよう統合履歴にも記録
+ALTER TABLE expression_merges ADD COLUMN base_priority INTEGER;
+UPDATE expression_merges SET base_priority = priority;
@@ -0,0 +1,10 @@
+-- 学習者のプロフィール（1行のみ）。優先度・カテゴリ判定のプロンプトに埋め込む
+CREATE TABLE IF NOT EXISTS learner_profile (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    role TEXT NOT NULL DEFAULT '',
+    domain TEXT NOT NULL DEFAULT '',
+    level TEXT NOT NULL DEFAULT '',
+    goals TEXT NOT NULL DEFAULT '',
+    native_language TEXT NOT NULL DEFAULT '',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
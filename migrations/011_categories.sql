@@ -0,0 +1,33 @@
+-- カテゴリの分類体系（チームごとに追加・変更できる。parentで階層化）
+CREATE TABLE IF NOT EXISTS categories (
+    name TEXT PRIMARY KEY,
+    display_name TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    parent TEXT REFERENCES categories(name),
+    position INTEGER NOT NULL DEFAULT 0
+);
+
+INSERT OR IGNORE INTO categories (name, display_name, description, position) VALUES
+    ('engineering', 'エンジニアリング', '学習者の専門分野の技術・専門用語', 1),
+    ('business', 'ビジネス', '仕事の進め方・調整・ビジネス関連', 2),
+    ('casual', '日常会話', '雑談・日常会話', 3);
+
+-- 既存の表現で使われているカテゴリも分類体系に登録
+INSERT OR IGNORE INTO categories (name, position)
+SELECT DISTINCT category, 100 FROM expressions WHERE COALESCE(category, '') != '';
+
+-- 表現とカテゴリの対応（1つの表現に複数のカテゴリ。positionが小さいほど関連が強く、0がexpressions.categoryと同じ主カテゴリ）
+CREATE TABLE IF NOT EXISTS expression_categories (
+    expression_id INTEGER NOT NULL REFERENCES expressions(id) ON DELETE CASCADE,
+    category TEXT NOT NULL REFERENCES categories(name) ON UPDATE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (expression_id, category)
+);
+
+CREATE INDEX IF NOT EXISTS idx_expression_categories_category ON expression_categories(category);
+
+INSERT OR IGNORE INTO expression_categories (expression_id, category, position)
+SELECT id, category, 0 FROM expressions WHERE COALESCE(category, '') != '';
+
+-- 分割時にカテゴリも復元できるよう統合履歴に記録（カンマ区切り）
+ALTER TABLE expression_merges ADD COLUMN categories TEXT;
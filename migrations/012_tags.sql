@@ -0,0 +1,27 @@
+-- 自由につけられるタグ（"interview-prep", "q3-planning" など。テーマ別の学習セットを作るため）
+CREATE TABLE IF NOT EXISTS tags (
+    name TEXT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 表現とタグの対応（sourceは "manual"（手動）または "meeting"（会議から自動付与））
+CREATE TABLE IF NOT EXISTS expression_tags (
+    expression_id INTEGER NOT NULL REFERENCES expressions(id) ON DELETE CASCADE,
+    tag TEXT NOT NULL REFERENCES tags(name) ON UPDATE CASCADE,
+    source TEXT NOT NULL DEFAULT 'manual',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (expression_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_expression_tags_tag ON expression_tags(tag);
+
+-- 会議名のパターン（大文字小文字を区別しないglob）に一致した会議で出現した表現に自動でつけるタグ
+CREATE TABLE IF NOT EXISTS tag_rules (
+    tag TEXT NOT NULL REFERENCES tags(name) ON UPDATE CASCADE,
+    pattern TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tag, pattern)
+);
+
+-- 分割時にタグも復元できるよう統合履歴に記録（カンマ区切り）
+ALTER TABLE expression_merges ADD COLUMN tags TEXT;
@@ -0,0 +1,24 @@
+-- 表現の語義（"ship" の「出荷する」「リリースする」のように、会議の文脈によって異なる意味）
+CREATE TABLE IF NOT EXISTS expression_senses (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    expression_id INTEGER NOT NULL REFERENCES expressions(id) ON DELETE CASCADE,
+    part_of_speech TEXT NOT NULL DEFAULT '', -- 品詞（"verb", "noun", "phrasal verb" など）
+    gloss TEXT NOT NULL DEFAULT '',          -- 学習者の母語での意味
+    definition TEXT NOT NULL DEFAULT '',     -- 英語での定義
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_expression_senses_expression ON expression_senses(expression_id);
+
+-- 出現した文脈で使われた語義（NULLなら未判定）
+ALTER TABLE expression_occurrences ADD COLUMN sense_id INTEGER REFERENCES expression_senses(id);
+
+CREATE INDEX IF NOT EXISTS idx_occurrences_sense ON expression_occurrences(sense_id);
+
+-- 既存の意味を最初の語義として登録し、既存の出現履歴はその語義で使われたものとする
+INSERT INTO expression_senses (expression_id, gloss)
+SELECT id, meaning FROM expressions WHERE COALESCE(meaning, '') != '';
+
+UPDATE expression_occurrences
+SET sense_id = (SELECT s.id FROM expression_senses s WHERE s.expression_id = expression_occurrences.expression_id)
+WHERE sense_id IS NULL;
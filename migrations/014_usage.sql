@@ -0,0 +1,25 @@
+-- LLMが生成した表現の使い方（transcriptの文脈は話し言葉の断片が多いため、学習用の例文・注意を別に持つ）
+CREATE TABLE IF NOT EXISTS expression_usage (
+    expression_id INTEGER PRIMARY KEY REFERENCES expressions(id) ON DELETE CASCADE,
+    register TEXT NOT NULL DEFAULT '', -- 使用域（"formal", "neutral", "informal", "slang"）
+    note TEXT NOT NULL DEFAULT '',     -- 使用上の注意（学習者の母語）
+    generated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 生成した例文（positionの順に表示）
+CREATE TABLE IF NOT EXISTS expression_examples (
+    expression_id INTEGER NOT NULL REFERENCES expressions(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    sentence TEXT NOT NULL,
+    translation TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (expression_id, position)
+);
+
+-- コロケーション・類義語・対義語（relationは "collocation", "synonym", "antonym"）
+CREATE TABLE IF NOT EXISTS expression_related_words (
+    expression_id INTEGER NOT NULL REFERENCES expressions(id) ON DELETE CASCADE,
+    relation TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    word TEXT NOT NULL,
+    PRIMARY KEY (expression_id, relation, position)
+);
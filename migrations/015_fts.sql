@@ -0,0 +1,72 @@
+-- 表現・意味・出現した文脈のFTS5全文検索インデックス（contextsには出現履歴のcontextを連結して格納）
+-- FTS5はgo-sqlite3を sqlite_fts5 タグ付きでビルドした場合のみ使えるため、タグなしのビルドでは適用を見送る
+-- タグなしのビルドで開くとトリガーを外して未適用に戻すので、作り直せるよう既存のインデックス・トリガーは削除してから作成する
+DROP TRIGGER IF EXISTS expressions_fts_insert;
+DROP TRIGGER IF EXISTS expressions_fts_update;
+DROP TRIGGER IF EXISTS expressions_fts_delete;
+DROP TRIGGER IF EXISTS expressions_fts_occurrence_insert;
+DROP TRIGGER IF EXISTS expressions_fts_occurrence_move;
+DROP TRIGGER IF EXISTS expressions_fts_occurrence_delete;
+DROP TABLE IF EXISTS expressions_fts;
+
+CREATE VIRTUAL TABLE expressions_fts USING fts5(expression, meaning, contexts);
+
+CREATE TRIGGER expressions_fts_insert
+AFTER INSERT ON expressions
+BEGIN
+    INSERT INTO expressions_fts (rowid, expression, meaning, contexts)
+    VALUES (NEW.id, NEW.expression, COALESCE(NEW.meaning, ''), '');
+END;
+
+CREATE TRIGGER expressions_fts_update
+AFTER UPDATE OF expression, meaning ON expressions
+BEGIN
+    UPDATE expressions_fts
+    SET expression = NEW.expression, meaning = COALESCE(NEW.meaning, '')
+    WHERE rowid = NEW.id;
+END;
+
+CREATE TRIGGER expressions_fts_delete
+AFTER DELETE ON expressions
+BEGIN
+    DELETE FROM expressions_fts WHERE rowid = OLD.id;
+END;
+
+CREATE TRIGGER expressions_fts_occurrence_insert
+AFTER INSERT ON expression_occurrences
+BEGIN
+    UPDATE expressions_fts
+    SET contexts = (
+        SELECT COALESCE(GROUP_CONCAT(context, ' '), '')
+        FROM expression_occurrences WHERE expression_id = NEW.expression_id
+    )
+    WHERE rowid = NEW.expression_id;
+END;
+
+CREATE TRIGGER expressions_fts_occurrence_move
+AFTER UPDATE OF expression_id ON expression_occurrences
+BEGIN
+    UPDATE expressions_fts
+    SET contexts = (
+        SELECT COALESCE(GROUP_CONCAT(context, ' '), '')
+        FROM expression_occurrences WHERE expression_id = expressions_fts.rowid
+    )
+    WHERE rowid IN (OLD.expression_id, NEW.expression_id);
+END;
+
+CREATE TRIGGER expressions_fts_occurrence_delete
+AFTER DELETE ON expression_occurrences
+BEGIN
+    UPDATE expressions_fts
+    SET contexts = (
+        SELECT COALESCE(GROUP_CONCAT(context, ' '), '')
+        FROM expression_occurrences WHERE expression_id = OLD.expression_id
+    )
+    WHERE rowid = OLD.expression_id;
+END;
+
+-- 既存データからインデックスを構築
+INSERT INTO expressions_fts (rowid, expression, meaning, contexts)
+SELECT e.id, e.expression, COALESCE(e.meaning, ''),
+       COALESCE((SELECT GROUP_CONCAT(o.context, ' ') FROM expression_occurrences o WHERE o.expression_id = e.id), '')
+FROM expressions e;
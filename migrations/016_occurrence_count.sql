@@ -0,0 +1,7 @@
+-- 出現回数を出現履歴の実件数に合わせる
+-- occurrence_countの初期値は1で、出現履歴を追加するたびにトリガーでも1増えるため、1件多く数えていた
+-- 新しい表現は0で保存するようにしたので、既存の表現の出現回数を数え直す
+UPDATE expressions
+SET occurrence_count = (
+    SELECT COUNT(*) FROM expression_occurrences WHERE expression_id = expressions.id
+);
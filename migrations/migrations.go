@@ -0,0 +1,9 @@
+// Package migrations はSQLiteスキーマのマイグレーションファイルを埋め込む
+package migrations
+
+import "embed"
+
+// FS は番号順に適用されるマイグレーションSQLファイル
+//
+//go:embed *.sql
+var FS embed.FS
@@ -1,14 +1,86 @@
 package prompt
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mamyudapao/learn-by-transcript/internal/models"
+)
+
+// learnerSection は学習者のプロフィールの説明（空の項目は省略）
+func learnerSection(profile models.LearnerProfile) string {
+	var b strings.Builder
+	b.WriteString("# 学習者\n")
+	fmt.Fprintf(&b, "- 役割: %s\n", profile.Role)
+	if profile.Domain != "" {
+		fmt.Fprintf(&b, "- 業務の分野: %s\n", profile.Domain)
+	}
+	if profile.Level != "" {
+		fmt.Fprintf(&b, "- 英語のレベル: %s\n", profile.Level)
+	}
+	if profile.Goals != "" {
+		fmt.Fprintf(&b, "- 学習の目的: %s\n", profile.Goals)
+	}
+	fmt.Fprintf(&b, "- 母語: %s\n", profile.NativeLanguage)
+	return b.String()
+}
+
+// specialty は学習者の専門（分野があれば役割と分野）
+func specialty(profile models.LearnerProfile) string {
+	if profile.Domain == "" {
+		return profile.Role
+	}
+	return fmt.Sprintf("%s（%s）", profile.Role, profile.Domain)
+}
+
+// categorySection はカテゴリの一覧（子カテゴリは親の下に字下げして並べる）
+func categorySection(taxonomy models.Taxonomy) string {
+	var b strings.Builder
+	var write func(parent string, depth int)
+	write = func(parent string, depth int) {
+		for _, c := range taxonomy.Children(parent) {
+			fmt.Fprintf(&b, "%s- %s", strings.Repeat("  ", depth), c.Name)
+			if c.DisplayName != "" && c.DisplayName != c.Name {
+				fmt.Fprintf(&b, "（%s）", c.DisplayName)
+			}
+			if c.Description != "" {
+				fmt.Fprintf(&b, ": %s", c.Description)
+			}
+			b.WriteString("\n")
+			// 循環した定義で無限に再帰しないよう深さを制限
+			if depth < len(taxonomy) {
+				write(c.Name, depth+1)
+			}
+		}
+	}
+	write("", 0)
+	// 親が分類体系にないカテゴリはトップレベルに並べる
+	for _, c := range taxonomy {
+		if c.Parent != "" && taxonomy.Find(c.Parent) == nil {
+			fmt.Fprintf(&b, "- %s: %s\n", c.Name, c.Description)
+		}
+	}
+	return b.String()
+}
+
+// exampleCategory はプロンプトの出力例に使うカテゴリ（分類体系のi番目、足りなければ先頭）
+func exampleCategory(taxonomy models.Taxonomy, i int) string {
+	if i < len(taxonomy) {
+		return taxonomy[i].Name
+	}
+	return taxonomy[0].Name
+}
 
 // ExtractPhrasesPrompt は熟語・慣用表現を抽出するプロンプト
-func ExtractPhrasesPrompt(transcript string) string {
-	return fmt.Sprintf(`以下の英語の会議transcriptから、熟語・慣用表現（2語以上の表現）を抽出してください。
+// profileの空の項目はmodels.DefaultLearnerProfileの値を使う
+func ExtractPhrasesPrompt(profile models.LearnerProfile, transcript string) string {
+	profile = models.DefaultLearnerProfile().Merge(profile)
+	return fmt.Sprintf(`以下の英語の会議transcriptから、学習者が覚えるべき熟語・慣用表現（2語以上の表現）を抽出してください。
 
+%s
 # 抽出対象
 - ビジネス英語の熟語（例: "circle back", "touch base", "reach out"）
-- 技術用語の組み合わせ（例: "code review", "pull request", "API endpoint"）
+- 学習者の専門（%s）の用語の組み合わせ（例: "code review", "pull request", "API endpoint"）
 - 一般的な慣用表現（例: "at the end of the day", "in terms of"）
 - コロケーション（例: "make sense", "take a look"）
 
@@ -30,47 +102,136 @@ func ExtractPhrasesPrompt(transcript string) string {
 # Transcript
 %s
 
-# 抽出結果（JSON形式、1行1熟語）`, transcript)
+# 抽出結果（JSON形式、1行1熟語）`, learnerSection(profile), specialty(profile), transcript)
 }
 
-// PrioritizeExpressionsPrompt は表現に優先度とカテゴリを付けるプロンプト
-func PrioritizeExpressionsPrompt(expressions []string, transcript string) string {
-	exprList := ""
+// expressionList は判定する表現の一覧（文脈と登録済みの語義を表現の下に字下げして並べる）
+func expressionList(expressions []*models.Expression) string {
+	var b strings.Builder
 	for i, expr := range expressions {
-		exprList += fmt.Sprintf("%d. %s\n", i+1, expr)
+		fmt.Fprintf(&b, "%d. %s\n", i+1, expr.Expression)
+		if expr.Context != "" {
+			fmt.Fprintf(&b, "   文脈: %s\n", expr.Context)
+		}
+		if len(expr.Senses) > 0 {
+			b.WriteString("   登録済みの語義:\n")
+			for j, s := range expr.Senses {
+				fmt.Fprintf(&b, "   - %d %s\n", j+1, s)
+			}
+		}
 	}
+	return b.String()
+}
 
-	return fmt.Sprintf(`以下の英語表現について、優先度とカテゴリを判定してください。
+// PrioritizeExpressionsPrompt は表現に優先度とカテゴリを付けるプロンプト
+// 優先度は学習者の役割・分野・レベル・目的から判定し、意味は学習者の母語で説明させる
+// カテゴリはtaxonomyから関連の強い順に複数選ばせる
+// 語義（品詞・意味・英語の定義）は表現の文脈から判定させ、登録済みの語義（expr.Senses）と同じなら番号を選ばせる
+// profileの空の項目はmodels.DefaultLearnerProfileの値を、taxonomyが空ならmodels.DefaultCategoriesを使う
+func PrioritizeExpressionsPrompt(profile models.LearnerProfile, taxonomy models.Taxonomy, expressions []*models.Expression, transcript string) string {
+	profile = models.DefaultLearnerProfile().Merge(profile)
+	if len(taxonomy) == 0 {
+		taxonomy = models.DefaultCategories()
+	}
+	exprList := expressionList(expressions)
+
+	return fmt.Sprintf(`以下の英語表現について、この学習者にとっての優先度とカテゴリを判定してください。
 
+%s
 # 判定基準
 
 ## 優先度（1〜5）
-5: ソフトウェアエンジニアとして必須の表現
-4: 仕事・技術的な議論でよく使う表現
+5: %[2]sとして必須の表現
+4: %[2]sの仕事・議論でよく使う表現
 3: ビジネス英語として重要な表現
 2: 知っておくと便利な表現
 1: 日常会話レベルの表現
+学習者のレベルで既に知っているはずの基本的な表現は低く、学習の目的に直結する表現は高くしてください。
 
-## カテゴリ
-- engineering: ソフトウェアエンジニアリング関連
-- business: ビジネス・仕事関連
-- casual: 雑談・日常会話
+## カテゴリ（学習者から見た分類）
+以下の識別名から、該当するものを関連の強い順に1〜3個選んでください。リストにないカテゴリは使わないでください。
+%[6]s
+## 語義
+同じ表現でも文脈によって意味が異なります（例: "ship" は「出荷する」「リリースする」）。表現の文脈での品詞（英語。"verb", "noun", "adjective", "phrasal verb", "idiom" など）・意味・英語での短い定義を答えてください。
+登録済みの語義があり、文脈での意味がそのどれかと同じなら、その番号を "sense" にしてください。どれとも違う場合や登録済みの語義がない場合は "sense" を0にしてください。
 
 # 表現リスト
-%s
-
+%[3]s
 # 文脈（参考）
-%s
+%[4]s
+
+# 出力形式
+各表現を1行につき1つ、以下のJSON形式で出力してください：
+{"expression": "表現", "pos": "品詞", "meaning": "文脈での%[5]sの意味", "definition": "English definition", "sense": 語義番号, "priority": 優先度数値, "categories": ["カテゴリ", ...]}
+
+例：
+{"expression": "deprecate", "pos": "verb", "meaning": "非推奨にする", "definition": "to mark a feature as no longer recommended", "sense": 0, "priority": 5, "categories": ["%[7]s"]}
+{"expression": "touch base", "pos": "phrasal verb", "meaning": "連絡を取る", "definition": "to briefly contact someone", "sense": 1, "priority": 3, "categories": ["%[8]s"]}
+
+重要: 各行は必ず正しいJSON形式にしてください。配列全体を[]で囲む必要はありません。
+
+# 判定結果（JSON形式、1行1表現）`, learnerSection(profile), specialty(profile), exprList, transcript, profile.NativeLanguage,
+		categorySection(taxonomy), exampleCategory(taxonomy, 0), exampleCategory(taxonomy, 1))
+}
 
+// usageList は使い方を生成する表現の一覧（意味・語義・transcriptでの文脈を表現の下に字下げして並べる）
+func usageList(expressions []*models.Expression) string {
+	var b strings.Builder
+	for i, expr := range expressions {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, expr.Expression)
+		// 手動で編集した意味は語義と異なることがあるので、どの語義とも違えば別に並べる
+		if expr.Meaning != "" && !hasGloss(expr.Senses, expr.Meaning) {
+			fmt.Fprintf(&b, "   - 意味: %s\n", expr.Meaning)
+		}
+		for _, s := range expr.Senses {
+			fmt.Fprintf(&b, "   - 語義: %s\n", s)
+		}
+		if expr.Context != "" {
+			fmt.Fprintf(&b, "   - 会議での文脈: %s\n", expr.Context)
+		}
+	}
+	return b.String()
+}
+
+// hasGloss は語義のいずれかの意味がglossか判定
+func hasGloss(senses []*models.Sense, gloss string) bool {
+	for _, s := range senses {
+		if s.Gloss == gloss {
+			return true
+		}
+	}
+	return false
+}
+
+// EnrichExpressionsPrompt は表現の例文・使用域・使用上の注意・コロケーション・類義語・対義語を生成するプロンプト
+// 例文は学習者の役割・分野の場面で、表現の意味（expr.Meaning）と登録済みの語義（expr.Senses）に沿って作らせる
+// profileの空の項目はmodels.DefaultLearnerProfileの値を使う
+func EnrichExpressionsPrompt(profile models.LearnerProfile, expressions []*models.Expression) string {
+	profile = models.DefaultLearnerProfile().Merge(profile)
+
+	return fmt.Sprintf(`以下の英語表現について、学習用の例文と使い方の説明を作成してください。
+
+%s
+# 作成する内容
+- examples: 自然で文法的に正しい英語の例文を2〜3個（%[2]sの仕事の場面を中心に）と、その%[3]s訳
+  - 会議での文脈は話し言葉の断片であることが多いので、そのまま使わず、完結した文にしてください
+  - 語義が複数ある場合は、それぞれの語義の例文を含めてください
+- register: 使用域（"formal", "neutral", "informal", "slang" のいずれか）
+- note: フォーマルさ・使う相手や場面・ニュアンスなど、使う上での注意を%[3]sで1〜2文
+- collocations: よく一緒に使われる語句（表現を含む形で最大5個。例: "raise a concern"）
+- synonyms: 類義語・言い換え表現（最大5個）
+- antonyms: 対義語（なければ空の配列）
+
+# 使い方を生成する表現
+%[4]s
 # 出力形式
 各表現を1行につき1つ、以下のJSON形式で出力してください：
-{"expression": "表現", "meaning": "日本語の意味", "priority": 優先度数値, "category": "カテゴリ"}
+{"expression": "表現", "examples": [{"sentence": "English sentence", "translation": "%[3]s訳"}], "register": "使用域", "note": "使用上の注意", "collocations": ["..."], "synonyms": ["..."], "antonyms": ["..."]}
 
 例：
-{"expression": "deprecate", "meaning": "非推奨にする", "priority": 5, "category": "engineering"}
-{"expression": "touch base", "meaning": "連絡を取る", "priority": 3, "category": "business"}
+{"expression": "circle back", "examples": [{"sentence": "Let's circle back on the pricing once we have the numbers.", "translation": "数字が出たら価格の件に戻りましょう。"}, {"sentence": "I'll circle back with the client tomorrow.", "translation": "明日クライアントに改めて連絡します。"}], "register": "informal", "note": "社内や親しい取引先との会話で使う。正式な文書では \"follow up\" や \"revisit\" が無難。", "collocations": ["circle back on", "circle back with"], "synonyms": ["follow up", "revisit"], "antonyms": []}
 
 重要: 各行は必ず正しいJSON形式にしてください。配列全体を[]で囲む必要はありません。
 
-# 判定結果（JSON形式、1行1表現）`, exprList, transcript)
+# 生成結果（JSON形式、1行1表現）`, learnerSection(profile), specialty(profile), profile.NativeLanguage, usageList(expressions))
 }